@@ -1,31 +1,138 @@
 package api
 
 import (
+	"log/slog"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/reflection/frog_blossom_db/config"
 	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/cache"
+	"github.com/reflection/frog_blossom_db/internal/components"
+	"github.com/reflection/frog_blossom_db/internal/content"
 	"github.com/reflection/frog_blossom_db/internal/handler"
+	"github.com/reflection/frog_blossom_db/internal/health"
+	"github.com/reflection/frog_blossom_db/internal/imaging"
+	"github.com/reflection/frog_blossom_db/internal/logging"
+	"github.com/reflection/frog_blossom_db/internal/metrics"
+	"github.com/reflection/frog_blossom_db/internal/middleware"
+	"github.com/reflection/frog_blossom_db/internal/settings"
+	"github.com/reflection/frog_blossom_db/internal/storage"
+	"github.com/reflection/frog_blossom_db/internal/tracing"
+	"github.com/reflection/frog_blossom_db/internal/validation"
 )
 
 // Server serves HTTP requets for CMS
 type Server struct {
-	Store  *db.Store
+	Store  db.Store
+	config config.Config
 	router *gin.Engine
 }
 
-// NewServer creates new HTTP server and sets up routing
-func NewServer(store *db.Store) *Server {
-	server := &Server{Store: store}
-	router := gin.Default()
+// NewServer creates new HTTP server and sets up routing. Media files are
+// kept in blobs, their image variants by variants. Rate limits and CORS
+// origins follow the runtime settings.
+func NewServer(config config.Config, store db.Store, blobs storage.Storage, variants *imaging.Variants, checker *health.Checker, runtime *settings.Manager) *Server {
+	validation.Register()
+	signer := imaging.NewSigner(config.MediaSigningKey)
+	pipeline := content.NewPipeline(nil, 0)
+	if config.ContentCacheTTL > 0 {
+		pipeline = content.NewPipeline(cache.NewLRU(config.ContentCacheSize), config.ContentCacheTTL)
+	}
+
+	limiter := middleware.NewRateLimiter(config.RateLimitRPS, config.RateLimitBurst)
+	runtime.Subscribe(func(event settings.Event) {
+		limiter.SetLimit(event.Current.RateLimitRPS, event.Current.RateLimitBurst)
+	})
+
+	server := &Server{Store: store, config: config}
+	router := gin.New()
+	// ctx.ClientIP, which rate limits are keyed by, only believes forwarding
+	// headers set by these proxies; config.Validate checked they parse
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		panic(err)
+	}
+	// let ctx.Value see request-scoped values such as the active span and request ID
+	router.ContextWithFallback = true
+	router.Use(logging.Middleware(slog.Default()))
+	router.Use(gin.Recovery())
+	router.Use(tracing.Middleware())
+	router.Use(metrics.Middleware())
+	router.Use(middleware.Errors())
+	if config.DBReadYourWrites {
+		router.Use(middleware.ReadYourWrites())
+	}
+	router.Use(middleware.CORS(func() []string { return runtime.Current().CORSAllowedOrigins }))
 
-	subrouter := router.Group("api/v1")
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.GET("/healthz", handler.HealthzHandler())
+	router.GET("/readyz", handler.ReadyzHandler(checker))
+	router.GET("/debug/info", middleware.RequireAdminToken(config.AdminToken), handler.DebugInfoHandler(checker))
 
-	subrouter.POST("/users", handler.CreateUsersHandler(store))
+	api := router.Group("api/v1")
+	api.Use(limiter.Middleware())
+
+	// sites are managed from any host, everything else is scoped to the
+	// site the Host header names
+	sites := api.Group("/sites", middleware.RequireAdminToken(config.AdminToken))
+	sites.POST("", handler.CreateSiteHandler(store))
+	sites.GET("", handler.ListSitesHandler(store))
+
+	subrouter := api.Group("", middleware.Site(store))
+	subrouter.POST("/users", middleware.IdentifyAdmin(config.AdminToken), handler.CreateUsersHandler(store))
 	subrouter.GET("/users/:id", handler.GetUsersHandler(store))
+	subrouter.POST("/pages", handler.CreatePagesHandler(store))
+	subrouter.GET("/pages/:id", handler.GetPagesHandler(store))
+	subrouter.POST("/posts", handler.CreatePostsHandler(store))
+	subrouter.GET("/posts/:id", handler.GetPostsHandler(store, pipeline))
+	subrouter.GET("/posts/:id/translations", handler.ListPostTranslationsHandler(store))
+	subrouter.PUT("/posts/:id/translations/:locale", handler.PutPostTranslationHandler(store))
+	subrouter.DELETE("/posts/:id/translations", handler.DeletePostTranslationHandler(store))
+	subrouter.PUT("/pages/:id", handler.UpdatePagesHandler(store))
+	subrouter.GET("/pages/:id/tree", handler.GetPageTreeHandler(store))
+	subrouter.PUT("/pages/:id/parent", handler.MovePageHandler(store))
+	subrouter.GET("/pages/:id/translations", handler.ListPageTranslationsHandler(store))
+	subrouter.PUT("/pages/:id/translations/:locale", handler.PutPageTranslationHandler(store))
+	subrouter.DELETE("/pages/:id/translations", handler.DeletePageTranslationHandler(store))
+	subrouter.GET("/navigation", handler.GetNavigationHandler(store))
+	subrouter.PUT("/navigation/order", handler.ReorderPagesHandler(store))
+	subrouter.GET("/component-types", handler.ListComponentTypesHandler(components.Default))
+	subrouter.GET("/pages/:id/components", handler.ListPageComponentsHandler(store))
+	subrouter.POST("/pages/:id/components", handler.AddPageComponentHandler(store, components.Default))
+	subrouter.PUT("/pages/:id/components/:component_id/position", handler.MovePageComponentHandler(store))
+	subrouter.DELETE("/pages/:id/components/:component_id", handler.RemovePageComponentHandler(store))
+	// public delivery of pages by URL path, see handler.DeliverPageHandler
+	subrouter.GET("/delivery/*path", handler.DeliverPageHandler(store))
+	subrouter.POST("/media", handler.UploadMediaHandler(store, blobs, config.MediaMaxUploadBytes))
+	subrouter.GET("/media", handler.ListMediaHandler(store))
+	subrouter.GET("/media/:id", handler.GetMediaHandler(store, signer))
+	subrouter.PUT("/media/:id", handler.UpdateMediaHandler(store))
+	subrouter.DELETE("/media/:id", handler.DeleteMediaHandler(store, blobs, variants))
+	subrouter.GET("/media/:id/file", handler.ServeMediaFileHandler(store, blobs))
+	subrouter.GET("/media/:id/variants/:variant", handler.ServeMediaVariantHandler(store, variants, signer))
+
+	site := subrouter.Group("/site", middleware.RequireAdminToken(config.AdminToken))
+	site.GET("/settings", handler.GetSiteSettingsHandler(store))
+	site.PUT("/settings/:key", handler.PutSiteSettingHandler(store))
+	site.DELETE("/settings/:key", handler.DeleteSiteSettingHandler(store))
+	site.GET("/members", handler.ListSiteMembersHandler(store))
+	site.PUT("/members/:user_id", handler.PutSiteMemberHandler(store))
+	site.DELETE("/members/:user_id", handler.DeleteSiteMemberHandler(store))
 
 	server.router = router
 	return server
 }
 
-func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+// HTTPServer returns an http.Server that serves the router on address with
+// the configured timeouts. Its lifecycle (listen, graceful shutdown) is owned
+// by the caller.
+func (server *Server) HTTPServer(address string) *http.Server {
+	return &http.Server{
+		Addr:              address,
+		Handler:           server.router,
+		ReadHeaderTimeout: server.config.HTTPReadTimeout,
+		ReadTimeout:       server.config.HTTPReadTimeout,
+		WriteTimeout:      server.config.HTTPWriteTimeout,
+		IdleTimeout:       server.config.HTTPIdleTimeout,
+	}
 }
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reflection/frog_blossom_db/config"
+	"github.com/reflection/frog_blossom_db/internal/health"
+	"github.com/reflection/frog_blossom_db/internal/imaging"
+	"github.com/reflection/frog_blossom_db/internal/settings"
+	"github.com/reflection/frog_blossom_db/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name    string
+		proxies []string
+		// status of a second request, from the same peer, that claims to
+		// forward for another client
+		status int
+	}{
+		{name: "SpoofedForwardedFor", status: http.StatusTooManyRequests},
+		// httptest requests come from 192.0.2.1
+		{name: "TrustedProxy", proxies: []string{"192.0.2.0/24"}, status: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.Config{
+				RateLimitRPS:    1,
+				RateLimitBurst:  1,
+				TrustedProxies:  tc.proxies,
+				AdminToken:      "admin-token",
+				MediaSigningKey: config.DevMediaSigningKey,
+			}
+			blobs := storage.NewLocal(t.TempDir())
+			server := NewServer(cfg, nil, blobs, imaging.NewVariants(blobs), health.NewChecker(nil), settings.New(cfg, config.Options{}))
+
+			request := func(forwardedFor string) int {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/sites", nil)
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				recorder := httptest.NewRecorder()
+				server.router.ServeHTTP(recorder, req)
+				return recorder.Code
+			}
+
+			require.Equal(t, http.StatusForbidden, request("203.0.113.1"))
+			require.Equal(t, tc.status, request("203.0.113.2"))
+		})
+	}
+}
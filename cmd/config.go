@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/reflection/frog_blossom_db/config"
+	"github.com/spf13/pflag"
+)
+
+// printConfig implements `config print`: it writes the effective config with
+// secrets redacted, then any validation errors, and returns the exit code
+func printConfig(args []string) int {
+	cfg, err := config.Load(config.Options{Path: ".", Args: args})
+	if errors.Is(err, pflag.ErrHelp) {
+		return 0
+	}
+
+	if printErr := cfg.Print(os.Stdout); printErr != nil {
+		fmt.Fprintln(os.Stderr, printErr)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -1,32 +1,150 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/reflection/frog_blossom_db/api"
 	"github.com/reflection/frog_blossom_db/config"
 	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/buildinfo"
+	"github.com/reflection/frog_blossom_db/internal/cache"
+	"github.com/reflection/frog_blossom_db/internal/database"
+	"github.com/reflection/frog_blossom_db/internal/health"
+	"github.com/reflection/frog_blossom_db/internal/imaging"
+	"github.com/reflection/frog_blossom_db/internal/lifecycle"
+	"github.com/reflection/frog_blossom_db/internal/logging"
+	"github.com/reflection/frog_blossom_db/internal/metrics"
+	"github.com/reflection/frog_blossom_db/internal/settings"
+	"github.com/reflection/frog_blossom_db/internal/storage"
+	"github.com/reflection/frog_blossom_db/internal/tracing"
+	"github.com/spf13/pflag"
 )
 
 func main() {
+	args := os.Args[1:]
+	if len(args) >= 2 && args[0] == "config" && args[1] == "print" {
+		os.Exit(printConfig(args[2:]))
+	}
+
+	options := config.Options{Path: ".", Args: args}
+	config, err := config.Load(options)
+	if errors.Is(err, pflag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fatal("cannot load config", err)
+	}
+
+	logger, level, err := logging.New(os.Stdout, config.LogFormat, config.LogLevel)
+	if err != nil {
+		fatal("cannot set up logging", err)
+	}
+	slog.SetDefault(logger)
+
+	runtime := settings.New(config, options)
+	runtime.Subscribe(func(event settings.Event) {
+		if err := logging.SetLevel(level, event.Current.LogLevel); err != nil {
+			slog.Error("cannot change log level", slog.Any("error", err))
+		}
+	})
 
-	config, err := config.LoadConfig(".")
+	conn, err := database.Open(config)
 	if err != nil {
-		log.Fatal("cannot load config:", err)
+		fatal("cannot connect to database", err)
 	}
 
-	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	replicaConns, err := database.OpenReplicas(config)
 	if err != nil {
-		log.Fatal("cannot connect to database:", err)
+		fatal("cannot connect to read replicas", err)
 	}
+	checkRoles(append([]*sql.DB{conn}, replicaConns...))
 
-	store := db.NewStore(conn)
-	server := api.NewServer(store)
+	app := lifecycle.New(config.ShutdownTimeout)
 
-	err = server.Start(config.ServerAddress)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     config.TracingExporter,
+		OTLPEndpoint: config.TracingOTLPEndpoint,
+		SampleRatio:  config.TracingSampleRatio,
+		Version:      buildinfo.Version,
+	})
 	if err != nil {
-		log.Fatal("cannot start server", err)
+		fatal("cannot set up tracing", err)
+	}
+	app.OnClose("tracing", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return shutdownTracing(ctx)
+	})
+
+	checker := health.NewChecker(conn)
+	checker.Add("database", health.DatabaseCheck(conn))
+	checker.Add("migrations", health.MigrationsCheck(conn))
+	checker.Add("workers", health.WorkersCheck(app.Workers))
+
+	metrics.RegisterDBStats("primary", conn)
+	storeOptions := []db.StoreOption{
+		db.WithDBTXMiddleware(metrics.DBTX, tracing.DBTX, logging.SlowQueries(logger, config.SlowQueryThreshold)),
+		db.WithTxObserver(metrics.ObserveTx),
 	}
+	if len(replicaConns) > 0 {
+		replicas := make([]db.Replica, len(replicaConns))
+		for i, replica := range replicaConns {
+			replicas[i] = replica
+			metrics.RegisterDBStats(fmt.Sprintf("replica_%d", i+1), replica)
+			app.OnClose(fmt.Sprintf("replica %d", i+1), replica.Close)
+		}
+		replicaSet := db.NewReplicaSet(replicas...)
+		storeOptions = append(storeOptions, db.WithReplicas(replicaSet))
+		app.Go("replicas", replicaSet.Watch(config.DBReplicaCheckInterval))
+	}
+	var store db.Store = db.NewStore(conn, storeOptions...)
+	if config.CacheTTL > 0 {
+		store = cache.Store(store, cache.NewLRU(config.CacheSize), cache.Options{
+			TTL:     config.CacheTTL,
+			Observe: metrics.ObserveCache,
+		})
+	}
+	blobs := storage.NewLocal(config.MediaDir)
+	variants := imaging.NewVariants(blobs)
+	store = imaging.Store(store, variants)
+	store = tracing.Store(store)
+	server := api.NewServer(config, store, blobs, variants, checker, runtime)
+
+	app.Go("settings", runtime.Watch)
+	app.Serve(server.HTTPServer(config.ServerAddress))
+	app.OnClose("database", conn.Close)
+
+	err = app.Run(context.Background())
+	if err != nil {
+		fatal("server stopped with error", err)
+	}
+}
+
+// checkRoles refuses to start with a database role that would let every
+// site see every other's rows. A database that can't be reached yet is
+// left to the health checks.
+func checkRoles(conns []*sql.DB) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, conn := range conns {
+		err := database.CheckRole(ctx, conn)
+		switch {
+		case errors.Is(err, database.ErrBypassesRLS):
+			fatal("refusing to start", err)
+		case err != nil:
+			slog.Warn("cannot check database role", slog.Any("error", err))
+		}
+	}
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
 }
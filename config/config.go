@@ -1,28 +1,75 @@
 package config
 
-import "github.com/spf13/viper"
+import "time"
 
-// Config stores all configurations of the appplication
-// Value are read by Viper from config file or env variables
+// Config stores all configurations of the appplication.
+// Values are layered by Load; fields tagged secret can be read from a
+// <KEY>_FILE path and are redacted by Print. Fields tagged reload can be
+// changed while running, see ApplyReload.
 type Config struct {
+	// Profile selects the app.<profile>.{env,yaml,toml} overlay: dev, test or prod
+	Profile       string `mapstructure:"APP_PROFILE"`
 	DBDriver      string `mapstructure:"DB_DRIVER"`
-	DBSource      string `mapstructure:"DB_SOURCE"`
+	DBSource      string `mapstructure:"DB_SOURCE" secret:"true"`
 	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
-}
-
-// LoadConfig reads configurations from file/ env vars
-func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName("app")
-	viper.SetConfigType("env")
-
-	viper.AutomaticEnv()
-
-	err = viper.ReadInConfig()
-	if err != nil {
-		return
-	}
-
-	err = viper.Unmarshal(&config)
-	return
+	// Pool sizing and recycling; a zero lifetime keeps connections forever
+	DBMaxOpenConns    int           `mapstructure:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns    int           `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetime time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`
+	// DBStatementTimeout is set as the server-side statement_timeout of every
+	// connection; 0 disables it
+	DBStatementTimeout time.Duration `mapstructure:"DB_STATEMENT_TIMEOUT"`
+	// DBReplicaSources are read replicas that take the read-only queries
+	// outside transactions, checked every DBReplicaCheckInterval
+	DBReplicaSources       []string      `mapstructure:"DB_REPLICA_SOURCES" secret:"true"`
+	DBReplicaCheckInterval time.Duration `mapstructure:"DB_REPLICA_CHECK_INTERVAL"`
+	// DBReadYourWrites sends a request's reads to the primary once it wrote
+	DBReadYourWrites bool `mapstructure:"DB_READ_YOUR_WRITES"`
+	// CacheTTL is how long posts, pages and meta are cached at most, in up
+	// to CacheSize entries; 0 disables the cache
+	CacheTTL  time.Duration `mapstructure:"CACHE_TTL"`
+	CacheSize int           `mapstructure:"CACHE_SIZE"`
+	// ContentCacheTTL is how long rendered post content is kept, in up to
+	// ContentCacheSize entries; 0 renders it on every read
+	ContentCacheTTL  time.Duration `mapstructure:"CONTENT_CACHE_TTL"`
+	ContentCacheSize int           `mapstructure:"CONTENT_CACHE_SIZE"`
+	// HTTP server timeouts, see http.Server
+	HTTPReadTimeout  time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`
+	HTTPWriteTimeout time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`
+	HTTPIdleTimeout  time.Duration `mapstructure:"HTTP_IDLE_TIMEOUT"`
+	// ShutdownTimeout bounds how long in-flight requests, and then workers,
+	// each get to finish on SIGINT/SIGTERM
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	// AdminToken guards the /debug endpoints; empty disables them
+	AdminToken string `mapstructure:"ADMIN_TOKEN" secret:"true"`
+	// TracingExporter is none, stdout or otlp
+	TracingExporter     string  `mapstructure:"TRACING_EXPORTER"`
+	TracingOTLPEndpoint string  `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	TracingSampleRatio  float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+	// LogFormat is json or text; LogLevel is debug, info, warn or error
+	LogFormat string `mapstructure:"LOG_FORMAT"`
+	LogLevel  string `mapstructure:"LOG_LEVEL" reload:"true"`
+	// SlowQueryThreshold logs queries running longer than it; 0 disables the log
+	SlowQueryThreshold time.Duration `mapstructure:"SLOW_QUERY_THRESHOLD"`
+	// RateLimitRPS is the sustained requests per second allowed per client IP
+	// on the API, with bursts up to RateLimitBurst; 0 disables the limit
+	RateLimitRPS   float64 `mapstructure:"RATE_LIMIT_RPS" reload:"true"`
+	RateLimitBurst int     `mapstructure:"RATE_LIMIT_BURST" reload:"true"`
+	// TrustedProxies lists the IPs and CIDRs of the reverse proxies whose
+	// X-Forwarded-For and X-Real-IP name the client; by default no one is
+	// trusted and the client is the peer address
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+	// CORSAllowedOrigins lists the origins, e.g. https://admin.example.com,
+	// allowed to call the API from a browser; "*" allows any
+	CORSAllowedOrigins []string `mapstructure:"CORS_ALLOWED_ORIGINS" reload:"true"`
+	// FeatureFlags lists the names of the enabled features
+	FeatureFlags []string `mapstructure:"FEATURE_FLAGS" reload:"true"`
+	// MediaDir is where uploaded media is stored; uploads are limited to
+	// MediaMaxUploadBytes
+	MediaDir            string `mapstructure:"MEDIA_DIR"`
+	MediaMaxUploadBytes int64  `mapstructure:"MEDIA_MAX_UPLOAD_BYTES"`
+	// MediaSigningKey signs the URLs of image variants so only variants the
+	// API handed out are rendered. Only the dev profile ships one, see
+	// DevMediaSigningKey.
+	MediaSigningKey string `mapstructure:"MEDIA_SIGNING_KEY" secret:"true"`
 }
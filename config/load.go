@@ -0,0 +1,200 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Profiles are the accepted values of APP_PROFILE
+var Profiles = []string{"dev", "test", "prod"}
+
+// profileExts are the formats a profile file may be written in
+var profileExts = []string{"env", "yaml", "yml", "toml"}
+
+// Options tells Load where to find configuration
+type Options struct {
+	// Path is the directory holding app.env and the profile files
+	Path string
+	// Args are command-line flags such as --log-level=debug, one per key
+	Args []string
+}
+
+// LoadConfig reads configurations from the files in path and env vars
+func LoadConfig(path string) (Config, error) {
+	return Load(Options{Path: path})
+}
+
+// Load builds the config from, lowest precedence first:
+//
+//  1. defaults
+//  2. app.env in Path
+//  3. the profile file app.<APP_PROFILE>.{env,yaml,yml,toml} in Path
+//  4. environment variables
+//  5. command-line flags
+//
+// A secret KEY is replaced by the contents of the file named by KEY_FILE
+// when that is set in any layer. Both files are optional. In prod the media
+// signing key must not come from them, only from the environment, a flag or
+// MEDIA_SIGNING_KEY_FILE. The returned
+// config is filled in even when it fails validation, so it can be printed.
+func Load(opts Options) (config Config, err error) {
+	v := viper.New()
+	setDefaults(v)
+
+	flags := pflag.NewFlagSet("frog-blossom", pflag.ContinueOnError)
+	for _, field := range fields() {
+		keys := []string{field.key}
+		if field.secret {
+			keys = append(keys, field.key+"_FILE")
+		}
+		for _, key := range keys {
+			name := strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+			flags.String(name, "", "overrides "+key)
+			if err = v.BindPFlag(key, flags.Lookup(name)); err != nil {
+				return
+			}
+			if err = v.BindEnv(key); err != nil {
+				return
+			}
+		}
+	}
+	if err = flags.Parse(opts.Args); err != nil {
+		return
+	}
+
+	v.SetConfigFile(filepath.Join(opts.Path, "app.env"))
+	if err = v.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return config, fmt.Errorf("read app.env: %w", err)
+	}
+
+	// an unknown profile is reported by Validate along with everything else
+	if profile := v.GetString("APP_PROFILE"); oneOf(profile, Profiles...) {
+		if err = mergeProfile(v, opts.Path, profile); err != nil {
+			return
+		}
+	}
+
+	for _, field := range fields() {
+		if !field.secret {
+			continue
+		}
+		if path := v.GetString(field.key + "_FILE"); path != "" {
+			secret, err := os.ReadFile(path)
+			if err != nil {
+				return config, fmt.Errorf("read %s_FILE: %w", field.key, err)
+			}
+			v.Set(field.key, strings.TrimRight(string(secret), "\r\n"))
+		}
+	}
+
+	if err = v.Unmarshal(&config); err != nil {
+		return
+	}
+
+	err = config.Validate()
+	if config.Profile == "prod" && v.InConfig("MEDIA_SIGNING_KEY") {
+		err = errors.Join(err, errors.New("MEDIA_SIGNING_KEY must not be set in a config file in prod, use the environment or MEDIA_SIGNING_KEY_FILE"))
+	}
+	return
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("APP_PROFILE", "dev")
+	v.SetDefault("DB_MAX_OPEN_CONNS", 25)
+	v.SetDefault("DB_MAX_IDLE_CONNS", 25)
+	v.SetDefault("DB_CONN_MAX_LIFETIME", 30*time.Minute)
+	v.SetDefault("DB_STATEMENT_TIMEOUT", 10*time.Second)
+	v.SetDefault("DB_REPLICA_SOURCES", []string{})
+	v.SetDefault("DB_REPLICA_CHECK_INTERVAL", 5*time.Second)
+	v.SetDefault("DB_READ_YOUR_WRITES", true)
+	v.SetDefault("CACHE_TTL", time.Minute)
+	v.SetDefault("CACHE_SIZE", 10000)
+	v.SetDefault("CONTENT_CACHE_TTL", 24*time.Hour)
+	v.SetDefault("CONTENT_CACHE_SIZE", 1000)
+	v.SetDefault("HTTP_READ_TIMEOUT", 10*time.Second)
+	v.SetDefault("HTTP_WRITE_TIMEOUT", 30*time.Second)
+	v.SetDefault("HTTP_IDLE_TIMEOUT", 2*time.Minute)
+	v.SetDefault("SHUTDOWN_TIMEOUT", 15*time.Second)
+	v.SetDefault("ADMIN_TOKEN", "")
+	v.SetDefault("TRACING_EXPORTER", "none")
+	v.SetDefault("TRACING_OTLP_ENDPOINT", "")
+	v.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	v.SetDefault("LOG_FORMAT", "json")
+	v.SetDefault("LOG_LEVEL", "info")
+	v.SetDefault("SLOW_QUERY_THRESHOLD", 200*time.Millisecond)
+	v.SetDefault("RATE_LIMIT_RPS", 0)
+	v.SetDefault("RATE_LIMIT_BURST", 20)
+	v.SetDefault("TRUSTED_PROXIES", []string{})
+	v.SetDefault("CORS_ALLOWED_ORIGINS", []string{})
+	v.SetDefault("FEATURE_FLAGS", []string{})
+	v.SetDefault("MEDIA_DIR", "./media")
+	v.SetDefault("MEDIA_MAX_UPLOAD_BYTES", 32<<20)
+	v.SetDefault("MEDIA_SIGNING_KEY", "")
+}
+
+// mergeProfile layers app.<profile>.<ext> over the base file. Having the
+// same profile in two formats is an error rather than a silent pick.
+func mergeProfile(v *viper.Viper, dir, profile string) error {
+	var found []string
+	for _, ext := range profileExts {
+		path := filepath.Join(dir, "app."+profile+"."+ext)
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil
+	case 1:
+		v.SetConfigFile(found[0])
+		if err := v.MergeInConfig(); err != nil {
+			return fmt.Errorf("read %s: %w", filepath.Base(found[0]), err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("profile %s is defined by more than one file: %s", profile, strings.Join(found, ", "))
+	}
+}
+
+// IsConfigFile reports whether a file name in the config directory is
+// read by Load: app.env or a profile file
+func IsConfigFile(name string) bool {
+	if name == "app.env" {
+		return true
+	}
+	parts := strings.Split(name, ".")
+	return len(parts) == 3 && parts[0] == "app" && oneOf(parts[1], Profiles...) && oneOf(parts[2], profileExts...)
+}
+
+type field struct {
+	index  int
+	key    string
+	secret bool
+	reload bool
+}
+
+// fields lists the Config fields with their env keys, in declaration order
+func fields() []field {
+	t := reflect.TypeOf(Config{})
+	out := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		out = append(out, field{
+			index:  i,
+			key:    f.Tag.Get("mapstructure"),
+			secret: f.Tag.Get("secret") == "true",
+			reload: f.Tag.Get("reload") == "true",
+		})
+	}
+	return out
+}
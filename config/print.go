@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// redacted replaces the value of secrets that are set
+const redacted = "[REDACTED]"
+
+// Print writes the config in app.env syntax, one KEY=value per line in
+// declaration order. Secrets that are set are shown as [REDACTED].
+func (config Config) Print(w io.Writer) error {
+	values := reflect.ValueOf(config)
+	for _, field := range fields() {
+		value := fmt.Sprint(values.Field(field.index).Interface())
+		if list, ok := values.Field(field.index).Interface().([]string); ok {
+			value = strings.Join(list, ",")
+		}
+		if field.secret && value != "" {
+			value = redacted
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", field.key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
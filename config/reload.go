@@ -0,0 +1,35 @@
+package config
+
+import "reflect"
+
+// ApplyReload returns the config to run with after the files changed to
+// next. Fields tagged reload are taken from next and listed in applied;
+// any other field that differs keeps its current value and is listed in
+// refused, since it only takes effect on restart.
+func (config Config) ApplyReload(next Config) (merged Config, applied, refused []string) {
+	merged = config
+	current := reflect.ValueOf(&merged).Elem()
+	incoming := reflect.ValueOf(next)
+
+	for _, field := range fields() {
+		from, to := current.Field(field.index), incoming.Field(field.index)
+		if equal(from, to) {
+			continue
+		}
+		if !field.reload {
+			refused = append(refused, field.key)
+			continue
+		}
+		from.Set(to)
+		applied = append(applied, field.key)
+	}
+	return merged, applied, refused
+}
+
+// equal treats nil and empty lists alike, as the sources can produce either
+func equal(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyReload(t *testing.T) {
+	// Arrange
+	current := validConfig()
+	next := current
+	next.LogLevel = "debug"
+	next.CORSAllowedOrigins = []string{"https://admin.example.com"}
+	next.ServerAddress = "0.0.0.0:9090"
+	next.DBSource = "postgres://elsewhere/frog"
+
+	// Act
+	merged, applied, refused := current.ApplyReload(next)
+
+	// Assert
+	require.Equal(t, []string{"LOG_LEVEL", "CORS_ALLOWED_ORIGINS"}, applied)
+	require.Equal(t, []string{"DB_SOURCE", "SERVER_ADDRESS"}, refused)
+	require.Equal(t, "debug", merged.LogLevel)
+	require.Equal(t, []string{"https://admin.example.com"}, merged.CORSAllowedOrigins)
+	require.Equal(t, current.ServerAddress, merged.ServerAddress)
+	require.Equal(t, current.DBSource, merged.DBSource)
+	require.Equal(t, "info", current.LogLevel, "receiver is not modified")
+}
+
+func TestApplyReloadTreatsEmptyListsAlike(t *testing.T) {
+	current := validConfig()
+	next := current
+	next.FeatureFlags = []string{}
+
+	_, applied, refused := current.ApplyReload(next)
+	require.Empty(t, applied)
+	require.Empty(t, refused)
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DevMediaSigningKey is the signing key of app.dev.env. It is public, so
+// prod refuses it.
+const DevMediaSigningKey = "dev-only-media-signing-key-change-me"
+
+// Validate reports every invalid setting at once, named by its env key
+func (config Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(oneOf(config.Profile, Profiles...), "APP_PROFILE must be one of %s, got %q", strings.Join(Profiles, ", "), config.Profile)
+	check(config.DBDriver != "", "DB_DRIVER is required")
+	check(config.DBSource != "", "DB_SOURCE is required")
+	check(config.ServerAddress != "", "SERVER_ADDRESS is required")
+
+	check(config.DBMaxOpenConns > 0, "DB_MAX_OPEN_CONNS must be at least 1, got %d", config.DBMaxOpenConns)
+	check(config.DBMaxIdleConns >= 0, "DB_MAX_IDLE_CONNS must not be negative, got %d", config.DBMaxIdleConns)
+	check(config.DBMaxIdleConns <= config.DBMaxOpenConns,
+		"DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", config.DBMaxIdleConns, config.DBMaxOpenConns)
+	check(config.DBConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME must not be negative, got %s", config.DBConnMaxLifetime)
+	check(config.DBStatementTimeout == 0 || config.DBStatementTimeout >= time.Millisecond,
+		"DB_STATEMENT_TIMEOUT must be 0 (disabled) or at least 1ms, got %s", config.DBStatementTimeout)
+	for i, source := range config.DBReplicaSources {
+		check(source != "", "DB_REPLICA_SOURCES entry %d is empty", i+1)
+	}
+	check(len(config.DBReplicaSources) == 0 || config.DBReplicaCheckInterval > 0,
+		"DB_REPLICA_CHECK_INTERVAL must be positive, got %s", config.DBReplicaCheckInterval)
+	check(config.CacheTTL >= 0, "CACHE_TTL must not be negative, got %s", config.CacheTTL)
+	check(config.CacheTTL == 0 || config.CacheSize >= 1, "CACHE_SIZE must be at least 1 when CACHE_TTL is set, got %d", config.CacheSize)
+	check(config.ContentCacheTTL >= 0, "CONTENT_CACHE_TTL must not be negative, got %s", config.ContentCacheTTL)
+	check(config.ContentCacheTTL == 0 || config.ContentCacheSize >= 1, "CONTENT_CACHE_SIZE must be at least 1 when CONTENT_CACHE_TTL is set, got %d", config.ContentCacheSize)
+
+	check(config.HTTPReadTimeout > 0, "HTTP_READ_TIMEOUT must be positive, got %s", config.HTTPReadTimeout)
+	check(config.HTTPWriteTimeout > 0, "HTTP_WRITE_TIMEOUT must be positive, got %s", config.HTTPWriteTimeout)
+	check(config.HTTPIdleTimeout > 0, "HTTP_IDLE_TIMEOUT must be positive, got %s", config.HTTPIdleTimeout)
+	check(config.DBStatementTimeout < config.HTTPWriteTimeout,
+		"DB_STATEMENT_TIMEOUT (%s) must be shorter than HTTP_WRITE_TIMEOUT (%s)", config.DBStatementTimeout, config.HTTPWriteTimeout)
+	check(config.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive, got %s", config.ShutdownTimeout)
+
+	check(config.SlowQueryThreshold >= 0, "SLOW_QUERY_THRESHOLD must not be negative, got %s", config.SlowQueryThreshold)
+	check(oneOf(config.LogFormat, "json", "text"), "LOG_FORMAT must be json or text, got %q", config.LogFormat)
+	var level slog.Level
+	check(level.UnmarshalText([]byte(config.LogLevel)) == nil,
+		"LOG_LEVEL must be debug, info, warn or error, got %q", config.LogLevel)
+	check(oneOf(config.TracingExporter, "none", "stdout", "otlp"),
+		"TRACING_EXPORTER must be none, stdout or otlp, got %q", config.TracingExporter)
+	check(config.TracingSampleRatio >= 0 && config.TracingSampleRatio <= 1,
+		"TRACING_SAMPLE_RATIO must be between 0 and 1, got %g", config.TracingSampleRatio)
+
+	check(config.RateLimitRPS >= 0, "RATE_LIMIT_RPS must not be negative, got %g", config.RateLimitRPS)
+	check(config.RateLimitRPS == 0 || config.RateLimitBurst >= 1,
+		"RATE_LIMIT_BURST must be at least 1 when RATE_LIMIT_RPS is set, got %d", config.RateLimitBurst)
+	for _, proxy := range config.TrustedProxies {
+		check(validProxy(proxy), "TRUSTED_PROXIES entry %q must be an IP address or CIDR", proxy)
+	}
+	for _, origin := range config.CORSAllowedOrigins {
+		check(validOrigin(origin), "CORS_ALLOWED_ORIGINS entry %q must be * or scheme://host[:port]", origin)
+	}
+
+	check(config.MediaDir != "", "MEDIA_DIR is required")
+	check(config.MediaMaxUploadBytes > 0, "MEDIA_MAX_UPLOAD_BYTES must be positive, got %d", config.MediaMaxUploadBytes)
+	check(len(config.MediaSigningKey) >= 32, "MEDIA_SIGNING_KEY must be at least 32 characters, got %d", len(config.MediaSigningKey))
+	check(config.Profile != "prod" || config.MediaSigningKey != DevMediaSigningKey, "MEDIA_SIGNING_KEY must not be the dev key in prod")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+func validProxy(proxy string) bool {
+	if _, _, err := net.ParseCIDR(proxy); err == nil {
+		return true
+	}
+	return net.ParseIP(proxy) != nil
+}
+
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.Path == "" && u.RawQuery == "" && u.User == nil
+}
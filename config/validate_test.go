@@ -0,0 +1,149 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() Config {
+	return Config{
+		Profile:             "dev",
+		DBDriver:            "postgres",
+		DBSource:            "postgres://localhost/frog",
+		ServerAddress:       "0.0.0.0:8080",
+		DBMaxOpenConns:      25,
+		DBMaxIdleConns:      25,
+		DBConnMaxLifetime:   30 * time.Minute,
+		DBStatementTimeout:  10 * time.Second,
+		HTTPReadTimeout:     10 * time.Second,
+		HTTPWriteTimeout:    30 * time.Second,
+		HTTPIdleTimeout:     2 * time.Minute,
+		ShutdownTimeout:     15 * time.Second,
+		TracingExporter:     "none",
+		TracingSampleRatio:  1,
+		LogFormat:           "json",
+		LogLevel:            "info",
+		MediaDir:            "./media",
+		MediaMaxUploadBytes: 32 << 20,
+		MediaSigningKey:     "0123456789abcdef0123456789abcdef",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(*Config)
+		errors []string
+	}{
+		{
+			name:   "valid",
+			modify: func(*Config) {},
+		},
+		{
+			name:   "statement timeout disabled",
+			modify: func(c *Config) { c.DBStatementTimeout = 0 },
+		},
+		{
+			name: "pool",
+			modify: func(c *Config) {
+				c.DBMaxOpenConns = 0
+				c.DBMaxIdleConns = 5
+			},
+			errors: []string{
+				"DB_MAX_OPEN_CONNS must be at least 1, got 0",
+				"DB_MAX_IDLE_CONNS (5) must not exceed DB_MAX_OPEN_CONNS (0)",
+			},
+		},
+		{
+			name:   "statement timeout below a millisecond",
+			modify: func(c *Config) { c.DBStatementTimeout = time.Microsecond },
+			errors: []string{"DB_STATEMENT_TIMEOUT must be 0 (disabled) or at least 1ms, got 1µs"},
+		},
+		{
+			name:   "statement timeout outlives the response",
+			modify: func(c *Config) { c.DBStatementTimeout = time.Minute },
+			errors: []string{"DB_STATEMENT_TIMEOUT (1m0s) must be shorter than HTTP_WRITE_TIMEOUT (30s)"},
+		},
+		{
+			name: "http timeouts",
+			modify: func(c *Config) {
+				c.HTTPReadTimeout = 0
+				c.HTTPIdleTimeout = -time.Second
+			},
+			errors: []string{
+				"HTTP_READ_TIMEOUT must be positive, got 0s",
+				"HTTP_IDLE_TIMEOUT must be positive, got -1s",
+			},
+		},
+		{
+			name:   "missing source",
+			modify: func(c *Config) { c.DBSource = "" },
+			errors: []string{"DB_SOURCE is required"},
+		},
+		{
+			name: "content cache",
+			modify: func(c *Config) {
+				c.ContentCacheTTL = time.Hour
+				c.ContentCacheSize = 0
+			},
+			errors: []string{"CONTENT_CACHE_SIZE must be at least 1 when CONTENT_CACHE_TTL is set, got 0"},
+		},
+		{
+			name:   "trusted proxies",
+			modify: func(c *Config) { c.TrustedProxies = []string{"10.0.0.1", "10.1.0.0/16", "::1"} },
+		},
+		{
+			name:   "trusted proxy hostname",
+			modify: func(c *Config) { c.TrustedProxies = []string{"proxy.internal"} },
+			errors: []string{`TRUSTED_PROXIES entry "proxy.internal" must be an IP address or CIDR`},
+		},
+		{
+			name: "media",
+			modify: func(c *Config) {
+				c.MediaDir = ""
+				c.MediaMaxUploadBytes = 0
+				c.MediaSigningKey = "short"
+			},
+			errors: []string{
+				"MEDIA_DIR is required",
+				"MEDIA_MAX_UPLOAD_BYTES must be positive, got 0",
+				"MEDIA_SIGNING_KEY must be at least 32 characters, got 5",
+			},
+		},
+		{
+			name:   "dev signing key in dev",
+			modify: func(c *Config) { c.MediaSigningKey = DevMediaSigningKey },
+		},
+		{
+			name: "dev signing key in prod",
+			modify: func(c *Config) {
+				c.Profile = "prod"
+				c.MediaSigningKey = DevMediaSigningKey
+			},
+			errors: []string{"MEDIA_SIGNING_KEY must not be the dev key in prod"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			config := validConfig()
+			tc.modify(&config)
+
+			// Act
+			err := config.Validate()
+
+			// Assert
+			if len(tc.errors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, msg := range tc.errors {
+				require.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+-- Only the first option of each page fits back into the old columns
+ALTER TABLE pages
+  ADD COLUMN option_id bigint NOT NULL DEFAULT 0,
+  ADD COLUMN option_name varchar(255) NOT NULL DEFAULT '',
+  ADD COLUMN option_value text NOT NULL DEFAULT '',
+  ADD COLUMN option_required boolean NOT NULL DEFAULT false;
+
+UPDATE pages
+SET option_id = o.id,
+    option_name = o.name,
+    option_value = o.value #>> '{}',
+    option_required = o.required
+FROM page_options o
+WHERE o.page_id = pages.id AND o.position = 0;
+
+ALTER TABLE pages
+  ALTER COLUMN option_id DROP DEFAULT,
+  ALTER COLUMN option_name DROP DEFAULT,
+  ALTER COLUMN option_value DROP DEFAULT,
+  ALTER COLUMN option_required DROP DEFAULT;
+
+DROP TABLE page_options;
+
+DROP TYPE option_type;
@@ -0,0 +1,27 @@
+-- Options move from columns on pages, one per row, to their own table so a
+-- page can have any number of them, ordered and with typed values
+CREATE TYPE option_type AS ENUM ('string', 'number', 'boolean', 'json');
+
+CREATE TABLE "page_options" (
+  "id" bigserial PRIMARY KEY,
+  "page_id" bigint NOT NULL REFERENCES "pages" ("id") ON DELETE CASCADE,
+  "position" integer NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "type" option_type NOT NULL,
+  "value" jsonb NOT NULL,
+  "required" boolean NOT NULL DEFAULT false,
+  UNIQUE ("page_id", "name"),
+  UNIQUE ("page_id", "position"),
+  CHECK ("type" = 'json' OR jsonb_typeof("value") = "type"::text)
+);
+
+INSERT INTO page_options (page_id, position, name, type, value, required)
+SELECT id, 0, option_name, 'string', to_jsonb(option_value), option_required
+FROM pages
+WHERE option_name <> '';
+
+ALTER TABLE pages
+  DROP COLUMN option_id,
+  DROP COLUMN option_name,
+  DROP COLUMN option_value,
+  DROP COLUMN option_required;
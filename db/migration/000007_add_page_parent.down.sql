@@ -0,0 +1,6 @@
+DROP TRIGGER pages_check_parent ON pages;
+
+DROP FUNCTION pages_check_parent();
+
+ALTER TABLE pages
+  DROP COLUMN parent_id;
@@ -0,0 +1,43 @@
+-- Pages form a tree per domain: parent_id points at the parent page, roots
+-- have none. Siblings are ordered by menu_order.
+ALTER TABLE pages
+  ADD COLUMN parent_id bigint REFERENCES pages ("id"),
+  ADD CONSTRAINT pages_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX ON "pages" ("parent_id", "menu_order");
+
+-- A page can't become its own ancestor and must share its parent's domain
+CREATE FUNCTION pages_check_parent() RETURNS trigger AS $$
+BEGIN
+  IF NEW.parent_id IS NOT NULL THEN
+    IF NOT EXISTS (SELECT 1 FROM pages WHERE id = NEW.parent_id AND domain = NEW.domain) THEN
+      RAISE EXCEPTION 'parent page % is not on domain %', NEW.parent_id, NEW.domain
+        USING ERRCODE = 'check_violation', CONSTRAINT = 'pages_parent_same_domain';
+    END IF;
+
+    IF EXISTS (
+      WITH RECURSIVE ancestors AS (
+        SELECT id, parent_id FROM pages WHERE id = NEW.parent_id
+        UNION
+        SELECT p.id, p.parent_id FROM pages p JOIN ancestors a ON p.id = a.parent_id
+      )
+      SELECT 1 FROM ancestors WHERE id = NEW.id
+    ) THEN
+      RAISE EXCEPTION 'page % can not be moved under its descendant %', NEW.id, NEW.parent_id
+        USING ERRCODE = 'check_violation', CONSTRAINT = 'pages_parent_no_cycle';
+    END IF;
+  END IF;
+
+  IF TG_OP = 'UPDATE' AND NEW.domain <> OLD.domain
+    AND EXISTS (SELECT 1 FROM pages WHERE parent_id = NEW.id) THEN
+    RAISE EXCEPTION 'page % has child pages and can not change domain', NEW.id
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'pages_parent_same_domain';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER pages_check_parent
+  BEFORE INSERT OR UPDATE OF parent_id, domain ON pages
+  FOR EACH ROW EXECUTE FUNCTION pages_check_parent();
@@ -0,0 +1,15 @@
+ALTER TABLE pages
+  ADD COLUMN component_type varchar(255) NOT NULL DEFAULT '',
+  ADD COLUMN component_value text NOT NULL DEFAULT '';
+
+UPDATE pages
+SET component_type = c.type,
+    component_value = COALESCE(c.value #>> '{}', c.value::text)
+FROM page_components c
+WHERE c.page_id = pages.id AND c.position = 0;
+
+ALTER TABLE pages
+  ALTER COLUMN component_type DROP DEFAULT,
+  ALTER COLUMN component_value DROP DEFAULT;
+
+DROP TABLE page_components;
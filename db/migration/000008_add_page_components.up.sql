@@ -0,0 +1,34 @@
+-- An ordered list of typed components per page. Values are validated
+-- against the JSON Schema of their type by the application, see
+-- internal/components.
+CREATE TABLE "page_components" (
+  "id" bigserial PRIMARY KEY,
+  "page_id" bigint NOT NULL REFERENCES "pages" ("id") ON DELETE CASCADE,
+  "position" integer NOT NULL CHECK ("position" >= 0),
+  "type" varchar(64) NOT NULL,
+  "value" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  -- deferred so a move can renumber siblings one row at a time
+  CONSTRAINT page_components_page_id_position_key
+    UNIQUE ("page_id", "position") DEFERRABLE INITIALLY DEFERRED
+);
+
+-- The one opaque component a page had becomes its first. Values that are
+-- JSON are kept as such, anything else as a JSON string.
+CREATE FUNCTION pg_temp.to_component_value(value text) RETURNS jsonb AS $$
+BEGIN
+  RETURN value::jsonb;
+EXCEPTION WHEN invalid_text_representation THEN
+  RETURN to_jsonb(value);
+END
+$$ LANGUAGE plpgsql;
+
+INSERT INTO page_components (page_id, position, type, value)
+SELECT id, 0, left(component_type, 64), pg_temp.to_component_value(component_value)
+FROM pages
+WHERE component_type <> '';
+
+ALTER TABLE pages
+  DROP COLUMN component_type,
+  DROP COLUMN component_value;
@@ -0,0 +1,20 @@
+ALTER TABLE meta
+  ADD COLUMN page_amount bigint NOT NULL DEFAULT 0,
+  ADD COLUMN site_language varchar(255);
+
+UPDATE meta
+SET page_amount = COALESCE((
+      SELECT (s.value #>> '{}')::bigint FROM site_settings s
+      WHERE s.domain = p.domain AND s.key = 'page_amount'
+    ), 0),
+    site_language = (
+      SELECT s.value #>> '{}' FROM site_settings s
+      WHERE s.domain = p.domain AND s.key = 'site_language'
+    )
+FROM pages p
+WHERE p.id = meta.page_id;
+
+ALTER TABLE meta
+  ALTER COLUMN page_amount DROP DEFAULT;
+
+DROP TABLE site_settings;
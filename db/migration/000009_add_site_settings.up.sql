@@ -0,0 +1,37 @@
+-- Site-wide values move from every meta row to one row per domain and key.
+-- Values are JSON; their types, defaults and validation are defined in
+-- internal/sitesettings.
+CREATE TABLE "site_settings" (
+  "domain" varchar(255) NOT NULL,
+  "key" varchar(255) NOT NULL,
+  "value" jsonb NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("domain", "key")
+);
+
+-- The newest meta row of a domain's pages wins; meta of posts has no domain.
+-- Languages are written the way locale.Parse writes them, e.g. pt_br becomes
+-- pt-BR and zh-hant-tw zh-Hant-TW. Values that aren't a language with an
+-- optional script and region are skipped, so the site gets the default.
+INSERT INTO site_settings (domain, key, value)
+SELECT DISTINCT ON (p.domain) p.domain, 'site_language',
+  to_jsonb(lower(t.parts[1]) || COALESCE('-' || initcap(t.parts[2]), '') || COALESCE('-' || upper(t.parts[3]), ''))
+FROM meta m
+JOIN pages p ON p.id = m.page_id
+CROSS JOIN LATERAL (
+  SELECT regexp_match(replace(btrim(m.site_language), '_', '-'),
+    '^([a-z]{2,3})(?:-([a-z]{4}))?(?:-([a-z]{2}|[0-9]{3}))?$', 'i') AS parts
+) t
+WHERE t.parts IS NOT NULL AND lower(t.parts[1]) <> 'und'
+ORDER BY p.domain, m.id DESC;
+
+INSERT INTO site_settings (domain, key, value)
+SELECT DISTINCT ON (p.domain) p.domain, 'page_amount', to_jsonb(m.page_amount)
+FROM meta m
+JOIN pages p ON p.id = m.page_id
+WHERE m.page_amount > 0
+ORDER BY p.domain, m.id DESC;
+
+ALTER TABLE meta
+  DROP COLUMN page_amount,
+  DROP COLUMN site_language;
@@ -0,0 +1,57 @@
+DROP TRIGGER pages_check_parent ON pages;
+
+ALTER TABLE pages ADD COLUMN domain varchar(255);
+UPDATE pages SET domain = sites.domain FROM sites WHERE sites.id = pages.site_id;
+ALTER TABLE pages ALTER COLUMN domain SET NOT NULL;
+CREATE INDEX ON "pages" ("domain");
+
+CREATE OR REPLACE FUNCTION pages_check_parent() RETURNS trigger AS $$
+BEGIN
+  IF NEW.parent_id IS NOT NULL THEN
+    IF NOT EXISTS (SELECT 1 FROM pages WHERE id = NEW.parent_id AND domain = NEW.domain) THEN
+      RAISE EXCEPTION 'parent page % is not on domain %', NEW.parent_id, NEW.domain
+        USING ERRCODE = 'check_violation', CONSTRAINT = 'pages_parent_same_domain';
+    END IF;
+
+    IF EXISTS (
+      WITH RECURSIVE ancestors AS (
+        SELECT id, parent_id FROM pages WHERE id = NEW.parent_id
+        UNION
+        SELECT p.id, p.parent_id FROM pages p JOIN ancestors a ON p.id = a.parent_id
+      )
+      SELECT 1 FROM ancestors WHERE id = NEW.id
+    ) THEN
+      RAISE EXCEPTION 'page % can not be moved under its descendant %', NEW.id, NEW.parent_id
+        USING ERRCODE = 'check_violation', CONSTRAINT = 'pages_parent_no_cycle';
+    END IF;
+  END IF;
+
+  IF TG_OP = 'UPDATE' AND NEW.domain <> OLD.domain
+    AND EXISTS (SELECT 1 FROM pages WHERE parent_id = NEW.id) THEN
+    RAISE EXCEPTION 'page % has child pages and can not change domain', NEW.id
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'pages_parent_same_domain';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER pages_check_parent
+  BEFORE INSERT OR UPDATE OF parent_id, domain ON pages
+  FOR EACH ROW EXECUTE FUNCTION pages_check_parent();
+
+ALTER TABLE site_settings ADD COLUMN domain varchar(255);
+UPDATE site_settings SET domain = sites.domain FROM sites WHERE sites.id = site_settings.site_id;
+ALTER TABLE site_settings
+  DROP CONSTRAINT site_settings_pkey,
+  DROP COLUMN site_id,
+  ALTER COLUMN domain SET NOT NULL,
+  ADD PRIMARY KEY ("domain", "key");
+
+ALTER TABLE meta DROP COLUMN site_id;
+ALTER TABLE posts DROP COLUMN site_id;
+ALTER TABLE pages DROP COLUMN site_id;
+
+DROP TABLE site_members;
+DROP TABLE sites;
+DROP TYPE site_role;
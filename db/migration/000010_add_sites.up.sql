@@ -0,0 +1,112 @@
+-- One deployment hosts many sites, each answering on its own domain.
+-- Pages, posts and meta belong to a site; users are shared and join sites
+-- with a role.
+CREATE TYPE site_role AS ENUM ('owner', 'editor', 'viewer');
+
+CREATE TABLE "sites" (
+  "id" bigserial PRIMARY KEY,
+  "domain" varchar(255) UNIQUE NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "site_members" (
+  "site_id" bigint NOT NULL REFERENCES "sites" ("id") ON DELETE CASCADE,
+  "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+  "role" site_role NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("site_id", "user_id")
+);
+
+CREATE INDEX ON "site_members" ("user_id");
+
+-- Every domain in use becomes a site. Posts have no domain, so they and
+-- their meta go to the oldest site, which is localhost if there is none.
+INSERT INTO sites (domain, name)
+SELECT domain, domain FROM pages
+UNION
+SELECT domain, domain FROM site_settings;
+
+INSERT INTO sites (domain, name)
+SELECT 'localhost', 'localhost'
+WHERE NOT EXISTS (SELECT 1 FROM sites)
+  AND (EXISTS (SELECT 1 FROM posts) OR EXISTS (SELECT 1 FROM users));
+
+-- Existing users keep access to everything they could reach before
+INSERT INTO site_members (site_id, user_id, role)
+SELECT s.id, u.id, CASE WHEN u.role = 'admin' THEN 'owner'::site_role ELSE 'editor'::site_role END
+FROM sites s CROSS JOIN users u;
+
+ALTER TABLE pages ADD COLUMN site_id bigint REFERENCES sites ("id");
+UPDATE pages SET site_id = sites.id FROM sites WHERE sites.domain = pages.domain;
+ALTER TABLE pages ALTER COLUMN site_id SET NOT NULL;
+CREATE INDEX ON "pages" ("site_id", "parent_id", "menu_order");
+
+ALTER TABLE posts ADD COLUMN site_id bigint REFERENCES sites ("id");
+UPDATE posts SET site_id = (SELECT min(id) FROM sites);
+ALTER TABLE posts ALTER COLUMN site_id SET NOT NULL;
+CREATE INDEX ON "posts" ("site_id");
+
+ALTER TABLE meta ADD COLUMN site_id bigint REFERENCES sites ("id");
+UPDATE meta SET site_id = COALESCE(
+  (SELECT site_id FROM pages WHERE pages.id = meta.page_id),
+  (SELECT site_id FROM posts WHERE posts.id = meta.posts_id),
+  (SELECT min(id) FROM sites)
+);
+ALTER TABLE meta ALTER COLUMN site_id SET NOT NULL;
+CREATE INDEX ON "meta" ("site_id");
+
+-- Meta can only describe a page or post of its own site
+ALTER TABLE pages ADD CONSTRAINT pages_site_id_id_key UNIQUE (site_id, id);
+ALTER TABLE posts ADD CONSTRAINT posts_site_id_id_key UNIQUE (site_id, id);
+ALTER TABLE meta
+  ADD FOREIGN KEY (site_id, page_id) REFERENCES pages (site_id, id),
+  ADD FOREIGN KEY (site_id, posts_id) REFERENCES posts (site_id, id);
+
+ALTER TABLE site_settings ADD COLUMN site_id bigint REFERENCES sites ("id") ON DELETE CASCADE;
+UPDATE site_settings SET site_id = sites.id FROM sites WHERE sites.domain = site_settings.domain;
+ALTER TABLE site_settings
+  DROP CONSTRAINT site_settings_pkey,
+  DROP COLUMN domain,
+  ALTER COLUMN site_id SET NOT NULL,
+  ADD PRIMARY KEY ("site_id", "key");
+
+-- The page tree now stays within a site instead of a domain
+DROP TRIGGER pages_check_parent ON pages;
+
+ALTER TABLE pages DROP COLUMN domain;
+
+CREATE OR REPLACE FUNCTION pages_check_parent() RETURNS trigger AS $$
+BEGIN
+  IF NEW.parent_id IS NOT NULL THEN
+    IF NOT EXISTS (SELECT 1 FROM pages WHERE id = NEW.parent_id AND site_id = NEW.site_id) THEN
+      RAISE EXCEPTION 'parent page % is not on site %', NEW.parent_id, NEW.site_id
+        USING ERRCODE = 'check_violation', CONSTRAINT = 'pages_parent_same_site';
+    END IF;
+
+    IF EXISTS (
+      WITH RECURSIVE ancestors AS (
+        SELECT id, parent_id FROM pages WHERE id = NEW.parent_id
+        UNION
+        SELECT p.id, p.parent_id FROM pages p JOIN ancestors a ON p.id = a.parent_id
+      )
+      SELECT 1 FROM ancestors WHERE id = NEW.id
+    ) THEN
+      RAISE EXCEPTION 'page % can not be moved under its descendant %', NEW.id, NEW.parent_id
+        USING ERRCODE = 'check_violation', CONSTRAINT = 'pages_parent_no_cycle';
+    END IF;
+  END IF;
+
+  IF TG_OP = 'UPDATE' AND NEW.site_id <> OLD.site_id
+    AND EXISTS (SELECT 1 FROM pages WHERE parent_id = NEW.id) THEN
+    RAISE EXCEPTION 'page % has child pages and can not change site', NEW.id
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'pages_parent_same_site';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER pages_check_parent
+  BEFORE INSERT OR UPDATE OF parent_id, site_id ON pages
+  FOR EACH ROW EXECUTE FUNCTION pages_check_parent();
@@ -0,0 +1,30 @@
+DROP POLICY page_components_site ON page_components;
+ALTER TABLE page_components NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE page_components DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY page_options_site ON page_options;
+ALTER TABLE page_options NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE page_options DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY site_members_site ON site_members;
+ALTER TABLE site_members NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE site_members DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY site_settings_site ON site_settings;
+ALTER TABLE site_settings NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE site_settings DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY meta_site ON meta;
+ALTER TABLE meta NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE meta DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY posts_site ON posts;
+ALTER TABLE posts NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE posts DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY pages_site ON pages;
+ALTER TABLE pages NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE pages DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION site_visible(bigint);
+DROP FUNCTION current_site_id();
@@ -0,0 +1,52 @@
+-- Row-level security keeps every site's content to itself, even for a
+-- query that forgets its site_id filter. app.current_site holds the id of
+-- the site a transaction works on; the store sets it from db.WithSite.
+-- Unset or empty means unscoped, for migrations, fixtures and maintenance.
+-- FORCE applies the policies to the table owner too. Superusers and
+-- BYPASSRLS roles skip them regardless; the app connects as frog_app, see
+-- 000015_add_app_role.
+CREATE FUNCTION current_site_id() RETURNS bigint AS $$
+  SELECT NULLIF(current_setting('app.current_site', true), '')::bigint
+$$ LANGUAGE sql STABLE;
+
+CREATE FUNCTION site_visible(site_id bigint) RETURNS boolean AS $$
+  SELECT current_site_id() IS NULL OR site_id = current_site_id()
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE pages ENABLE ROW LEVEL SECURITY;
+ALTER TABLE pages FORCE ROW LEVEL SECURITY;
+CREATE POLICY pages_site ON pages
+  USING (site_visible(site_id)) WITH CHECK (site_visible(site_id));
+
+ALTER TABLE posts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE posts FORCE ROW LEVEL SECURITY;
+CREATE POLICY posts_site ON posts
+  USING (site_visible(site_id)) WITH CHECK (site_visible(site_id));
+
+ALTER TABLE meta ENABLE ROW LEVEL SECURITY;
+ALTER TABLE meta FORCE ROW LEVEL SECURITY;
+CREATE POLICY meta_site ON meta
+  USING (site_visible(site_id)) WITH CHECK (site_visible(site_id));
+
+ALTER TABLE site_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE site_settings FORCE ROW LEVEL SECURITY;
+CREATE POLICY site_settings_site ON site_settings
+  USING (site_visible(site_id)) WITH CHECK (site_visible(site_id));
+
+ALTER TABLE site_members ENABLE ROW LEVEL SECURITY;
+ALTER TABLE site_members FORCE ROW LEVEL SECURITY;
+CREATE POLICY site_members_site ON site_members
+  USING (site_visible(site_id)) WITH CHECK (site_visible(site_id));
+
+-- Rows of a page are visible with their page; pages is filtered itself
+ALTER TABLE page_options ENABLE ROW LEVEL SECURITY;
+ALTER TABLE page_options FORCE ROW LEVEL SECURITY;
+CREATE POLICY page_options_site ON page_options
+  USING (EXISTS (SELECT 1 FROM pages WHERE pages.id = page_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM pages WHERE pages.id = page_id));
+
+ALTER TABLE page_components ENABLE ROW LEVEL SECURITY;
+ALTER TABLE page_components FORCE ROW LEVEL SECURITY;
+CREATE POLICY page_components_site ON page_components
+  USING (EXISTS (SELECT 1 FROM pages WHERE pages.id = page_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM pages WHERE pages.id = page_id));
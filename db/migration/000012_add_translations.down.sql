@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS pages_site_id_url_idx;
+
+ALTER TABLE posts
+  DROP COLUMN translation_group_id,
+  DROP COLUMN locale;
+
+ALTER TABLE pages
+  DROP COLUMN translation_group_id,
+  DROP COLUMN locale;
+
+DROP TABLE IF EXISTS translation_groups;
@@ -0,0 +1,30 @@
+-- A translation group links the language variants of one page or post.
+-- Every member of a group has a locale, and no two share one. Content
+-- without a locale is in the site's default language.
+CREATE TABLE "translation_groups" (
+  "id" bigserial PRIMARY KEY,
+  "site_id" bigint NOT NULL REFERENCES "sites" ("id") ON DELETE CASCADE,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("site_id", "id")
+);
+
+ALTER TABLE pages
+  ADD COLUMN locale varchar(35),
+  ADD COLUMN translation_group_id bigint,
+  ADD FOREIGN KEY (site_id, translation_group_id) REFERENCES translation_groups (site_id, id),
+  ADD CONSTRAINT pages_translation_locale CHECK (translation_group_id IS NULL OR locale IS NOT NULL),
+  ADD CONSTRAINT pages_translation_group_id_locale_key UNIQUE (translation_group_id, locale);
+
+ALTER TABLE posts
+  ADD COLUMN locale varchar(35),
+  ADD COLUMN translation_group_id bigint,
+  ADD FOREIGN KEY (site_id, translation_group_id) REFERENCES translation_groups (site_id, id),
+  ADD CONSTRAINT posts_translation_locale CHECK (translation_group_id IS NULL OR locale IS NOT NULL),
+  ADD CONSTRAINT posts_translation_group_id_locale_key UNIQUE (translation_group_id, locale);
+
+CREATE INDEX ON "pages" ("site_id", "url");
+
+ALTER TABLE translation_groups ENABLE ROW LEVEL SECURITY;
+ALTER TABLE translation_groups FORCE ROW LEVEL SECURITY;
+CREATE POLICY translation_groups_site ON translation_groups
+  USING (site_visible(site_id)) WITH CHECK (site_visible(site_id));
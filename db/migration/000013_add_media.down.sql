@@ -0,0 +1,5 @@
+ALTER TABLE meta DROP COLUMN meta_og_image_id;
+
+ALTER TABLE posts DROP COLUMN featured_media_id;
+
+DROP TABLE IF EXISTS media;
@@ -0,0 +1,36 @@
+-- Media are files uploaded to a site. The file itself lives in storage
+-- under storage_key; checksum is its hex SHA-256, so uploading the same
+-- file to a site twice gives the one media item.
+CREATE TABLE "media" (
+  "id" bigserial PRIMARY KEY,
+  "site_id" bigint NOT NULL REFERENCES "sites" ("id") ON DELETE CASCADE,
+  "uploader_id" bigint NOT NULL REFERENCES "users" ("id"),
+  "file_name" varchar(255) NOT NULL,
+  "mime_type" varchar(255) NOT NULL,
+  "size" bigint NOT NULL CHECK ("size" >= 0),
+  "checksum" char(64) NOT NULL,
+  "storage_key" varchar(255) NOT NULL,
+  "width" integer,
+  "height" integer,
+  "alt_text" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("site_id", "checksum"),
+  UNIQUE ("site_id", "id")
+);
+
+-- Media in use can not be deleted
+ALTER TABLE posts
+  ADD COLUMN featured_media_id bigint,
+  ADD FOREIGN KEY (site_id, featured_media_id) REFERENCES media (site_id, id);
+
+ALTER TABLE meta
+  ADD COLUMN meta_og_image_id bigint,
+  ADD FOREIGN KEY (site_id, meta_og_image_id) REFERENCES media (site_id, id);
+
+CREATE INDEX ON "posts" ("featured_media_id");
+CREATE INDEX ON "meta" ("meta_og_image_id");
+
+ALTER TABLE media ENABLE ROW LEVEL SECURITY;
+ALTER TABLE media FORCE ROW LEVEL SECURITY;
+CREATE POLICY media_site ON media
+  USING (site_visible(site_id)) WITH CHECK (site_visible(site_id));
@@ -0,0 +1,3 @@
+CREATE OR REPLACE FUNCTION site_visible(site_id bigint) RETURNS boolean AS $$
+  SELECT current_site_id() IS NULL OR site_id = current_site_id()
+$$ LANGUAGE sql STABLE;
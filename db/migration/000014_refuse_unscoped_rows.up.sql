@@ -0,0 +1,7 @@
+-- Without app.current_site the app sees no rows of any site, so a query
+-- that lost its site can not read or write across sites. Migrations,
+-- fixtures and maintenance run as the owner, a superuser or BYPASSRLS
+-- role, instead of frog_app.
+CREATE OR REPLACE FUNCTION site_visible(site_id bigint) RETURNS boolean AS $$
+  SELECT current_site_id() IS NOT NULL AND site_id = current_site_id()
+$$ LANGUAGE sql STABLE;
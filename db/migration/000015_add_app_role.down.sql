@@ -0,0 +1,10 @@
+-- frog_app itself stays, other databases of the cluster may use it
+DO $$
+BEGIN
+  EXECUTE format('ALTER DEFAULT PRIVILEGES IN SCHEMA %I REVOKE ALL ON SEQUENCES FROM frog_app', current_schema());
+  EXECUTE format('ALTER DEFAULT PRIVILEGES IN SCHEMA %I REVOKE ALL ON TABLES FROM frog_app', current_schema());
+  EXECUTE format('REVOKE ALL ON ALL SEQUENCES IN SCHEMA %I FROM frog_app', current_schema());
+  EXECUTE format('REVOKE ALL ON ALL TABLES IN SCHEMA %I FROM frog_app', current_schema());
+  EXECUTE format('REVOKE USAGE ON SCHEMA %I FROM frog_app', current_schema());
+END
+$$;
@@ -0,0 +1,26 @@
+-- The app connects as a member of frog_app. Superusers and BYPASSRLS roles
+-- skip row-level security even where it is FORCEd, so the role is neither;
+-- the owner that runs these migrations keeps both. Roles belong to the
+-- cluster, not the database, so frog_app is only created if it is missing.
+DO $$
+BEGIN
+  CREATE ROLE frog_app NOLOGIN NOSUPERUSER NOBYPASSRLS NOCREATEDB NOCREATEROLE;
+EXCEPTION
+  WHEN duplicate_object OR unique_violation THEN NULL;
+END
+$$;
+
+-- Reading and writing content, also of tables added by later migrations,
+-- but not the schema itself
+DO $$
+BEGIN
+  EXECUTE format('GRANT USAGE ON SCHEMA %I TO frog_app', current_schema());
+  EXECUTE format('GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA %I TO frog_app', current_schema());
+  EXECUTE format('GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA %I TO frog_app', current_schema());
+  EXECUTE format('ALTER DEFAULT PRIVILEGES IN SCHEMA %I GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO frog_app', current_schema());
+  EXECUTE format('ALTER DEFAULT PRIVILEGES IN SCHEMA %I GRANT USAGE, SELECT ON SEQUENCES TO frog_app', current_schema());
+END
+$$;
+
+-- The migrations health check reads the version; only migrate writes it
+REVOKE INSERT, UPDATE, DELETE ON schema_migrations FROM frog_app;
@@ -0,0 +1,6 @@
+CREATE TYPE level AS ENUM ('draft', 'pending', 'private', 'publish');
+
+ALTER TABLE posts
+  ALTER COLUMN status TYPE access USING 'user'::access;
+
+DROP TYPE post_status;
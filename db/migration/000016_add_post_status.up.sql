@@ -0,0 +1,10 @@
+-- 000004 gave posts.status the access enum of users.role. A post goes
+-- through the labels of the level enum, which nothing used, so they
+-- become post_status. Stored statuses are roles, not statuses, and every
+-- post is made a draft rather than guessing which ones were published.
+CREATE TYPE post_status AS ENUM ('draft', 'pending', 'private', 'publish');
+
+ALTER TABLE posts
+  ALTER COLUMN status TYPE post_status USING 'draft'::post_status;
+
+DROP TYPE IF EXISTS level;
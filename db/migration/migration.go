@@ -0,0 +1,82 @@
+// Package migration embeds the schema migrations so they can be applied
+// from Go code (tests, startup checks) without the migrate CLI.
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/lib/pq"
+)
+
+// FS holds the up/down migration files in golang-migrate naming format
+//
+//go:embed *.sql
+var FS embed.FS
+
+// Up applies every pending migration to the database at url
+func Up(url string) error {
+	source, err := iofs.New(FS, ".")
+	if err != nil {
+		return err
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", source, url)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Latest returns the highest migration version shipped with the binary
+func Latest() (uint, error) {
+	entries, err := fs.ReadDir(FS, ".")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok || !strings.HasSuffix(entry.Name(), ".up.sql") {
+			continue
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+	return latest, nil
+}
+
+// Version reads the migration version applied to the database from the
+// schema_migrations table maintained by golang-migrate. A database that
+// was never migrated reports version 0.
+func Version(ctx context.Context, conn *sql.DB) (version uint, dirty bool, err error) {
+	err = conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, false, nil
+	case errors.As(err, &pqErr) && pqErr.Code == "42P01": // undefined_table
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatest(t *testing.T) {
+	latest, err := Latest()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, latest, uint(5))
+
+	matches, err := fs.Glob(FS, fmt.Sprintf("%06d_*.up.sql", latest))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+
+	newer, err := fs.Glob(FS, fmt.Sprintf("%06d_*.up.sql", latest+1))
+	require.NoError(t, err)
+	require.Empty(t, newer)
+}
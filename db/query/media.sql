@@ -0,0 +1,44 @@
+-- name: CreateMedia :one
+-- A file the site already has is not inserted again; see GetMediaByChecksum
+INSERT INTO media (
+  site_id,
+  uploader_id,
+  file_name,
+  mime_type,
+  size,
+  checksum,
+  storage_key,
+  width,
+  height,
+  alt_text
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (site_id, checksum) DO NOTHING
+RETURNING *;
+
+-- name: GetMedia :one
+SELECT * FROM media
+WHERE site_id = $1 AND id = $2 LIMIT 1;
+
+-- name: GetMediaByChecksum :one
+SELECT * FROM media
+WHERE site_id = $1 AND checksum = $2 LIMIT 1;
+
+-- name: ListMedia :many
+SELECT * FROM media
+WHERE site_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateMediaAltText :one
+UPDATE media
+  SET alt_text = $3
+WHERE site_id = $1 AND id = $2
+RETURNING *;
+
+-- name: DeleteMedia :one
+DELETE FROM media
+WHERE site_id = $1 AND id = $2
+RETURNING *;
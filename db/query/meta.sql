@@ -1,5 +1,6 @@
 -- name: CreateMeta :one
 INSERT INTO meta (
+  site_id,
   page_id,
   posts_id,
   meta_title,
@@ -7,56 +8,55 @@ INSERT INTO meta (
   meta_robots,
   meta_og_image,
   locale,
-  page_amount,
-  site_language,
   meta_key,
-  meta_value
+  meta_value,
+  meta_og_image_id
 ) VALUES (
   $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 ) RETURNING *;
 
 -- name: GetMeta :one
 SELECT * FROM meta
-WHERE id = $1 LIMIT 1;
+WHERE site_id = $1 AND id = $2 LIMIT 1;
 
 -- name: GetMetaByPageIDForUpdate :one
 SELECT * FROM meta
-WHERE page_id = $1 LIMIT 1
+WHERE site_id = $1 AND page_id = $2 LIMIT 1
 FOR NO KEY UPDATE;
 
 -- name: GetMetaByPostsIDForUpdate :one
 SELECT * FROM meta
-WHERE posts_id = $1 LIMIT 1
+WHERE site_id = $1 AND posts_id = $2 LIMIT 1
 FOR NO KEY UPDATE;
 
 -- name: ListMeta :many
 SELECT * FROM meta
+WHERE site_id = $1
 ORDER BY id
-LIMIT $1
-OFFSET $2;
+LIMIT $2
+OFFSET $3;
 
 -- name: UpdateMeta :one
 UPDATE meta
-  SET page_id = $2,
-posts_id = $3,
-meta_title = $4,
-meta_description = $5,
-meta_robots = $6,
-meta_og_image = $7,
-locale = $8,
-page_amount = $9,
-site_language = $10,
-meta_key = $11,
-meta_value = $12
-WHERE id = $1
+  SET page_id = $3,
+posts_id = $4,
+meta_title = $5,
+meta_description = $6,
+meta_robots = $7,
+meta_og_image = $8,
+locale = $9,
+meta_key = $10,
+meta_value = $11,
+meta_og_image_id = $12
+WHERE site_id = $1 AND id = $2
 RETURNING *;
 
 -- name: DeleteMeta :exec
 DELETE FROM meta
-WHERE id = $1;
+WHERE site_id = $1 AND id = $2;
 
 -- name: DeleteMetaByPostId :exec
-DELETE FROM meta WHERE posts_id = $1;
+DELETE FROM meta WHERE site_id = $1 AND posts_id = $2;
 
 -- name: DeleteMetaByPageId :exec
-DELETE FROM meta WHERE page_id = $1;
+DELETE FROM meta WHERE site_id = $1 AND page_id = $2;
@@ -0,0 +1,25 @@
+-- name: CreatePageComponent :one
+INSERT INTO page_components (
+  page_id,
+  position,
+  type,
+  value
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListPageComponents :many
+SELECT * FROM page_components
+WHERE page_id = $1
+ORDER BY position;
+
+-- name: UpdatePageComponentPosition :one
+UPDATE page_components
+  SET position = $2,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeletePageComponent :exec
+DELETE FROM page_components
+WHERE id = $1;
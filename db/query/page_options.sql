@@ -0,0 +1,20 @@
+-- name: CreatePageOption :one
+INSERT INTO page_options (
+  page_id,
+  position,
+  name,
+  type,
+  value,
+  required
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListPageOptions :many
+SELECT * FROM page_options
+WHERE page_id = $1
+ORDER BY position;
+
+-- name: DeletePageOptions :exec
+DELETE FROM page_options
+WHERE page_id = $1;
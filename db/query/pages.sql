@@ -1,50 +1,87 @@
 -- name: CreatePages :one
 INSERT INTO pages (
-  domain,
+  site_id,
   author_id,
   page_author,
   title,
   url,
   menu_order,
-  component_type,
-  component_value,
   page_identifier,
-  option_id,
-  option_name,
-  option_value,
-  option_required
+  parent_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+  $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetPages :one
 SELECT * FROM pages
-WHERE id = $1 LIMIT 1;
+WHERE site_id = $1 AND id = $2 LIMIT 1;
 
 -- name: ListPages :many
 SELECT * FROM pages
+WHERE site_id = $1
 ORDER BY id
-LIMIT $1
-OFFSET $2;
+LIMIT $2
+OFFSET $3;
 
 -- name: UpdatePages :one
 UPDATE pages
-  SET domain = $2,
-  author_id = $3,
+  SET author_id = $3,
   page_author = $4,
   title = $5,
   url = $6,
   menu_order = $7,
-  component_type = $8,
-  component_value = $9,
-  page_identifier = $10,
-  option_id = $11,
-  option_name = $12,
-  option_value = $13,
-  option_required = $14
-WHERE id = $1
+  page_identifier = $8
+WHERE site_id = $1 AND id = $2
 RETURNING *;
 
 -- name: DeletePages :exec
 DELETE FROM pages
-WHERE id = $1;
+WHERE site_id = $1 AND id = $2;
+
+-- name: ListSitePages :many
+SELECT * FROM pages
+WHERE site_id = $1
+ORDER BY parent_id NULLS FIRST, menu_order, id;
+
+-- name: GetPageSubtree :many
+WITH RECURSIVE subtree AS (
+  SELECT pages.id FROM pages
+  WHERE pages.site_id = $1 AND pages.id = $2
+  UNION
+  SELECT child.id FROM pages child
+  JOIN subtree ON child.parent_id = subtree.id
+)
+SELECT pages.* FROM pages
+JOIN subtree ON pages.id = subtree.id
+ORDER BY pages.menu_order, pages.id;
+
+-- name: ListPageSiblings :many
+SELECT * FROM pages
+WHERE site_id = @site_id AND parent_id IS NOT DISTINCT FROM sqlc.narg(parent_id)
+ORDER BY menu_order, id
+FOR UPDATE;
+
+-- name: CountPageChildren :one
+SELECT count(*) FROM pages
+WHERE site_id = $1 AND parent_id = $2;
+
+-- name: MovePage :one
+UPDATE pages
+  SET parent_id = sqlc.narg(parent_id),
+  menu_order = @menu_order
+WHERE site_id = @site_id AND id = @id
+RETURNING *;
+
+-- name: UpdatePageMenuOrder :exec
+UPDATE pages
+  SET menu_order = $3
+WHERE site_id = $1 AND id = $2;
+
+-- name: LockPageTree :exec
+-- Serializes moves within a site so two concurrent moves can't form a cycle
+SELECT pg_advisory_xact_lock(hashtext('pages:' || @site_id::bigint));
+
+-- name: GetPagesForUpdate :one
+SELECT * FROM pages
+WHERE site_id = $1 AND id = $2 LIMIT 1
+FOR UPDATE;
@@ -1,5 +1,6 @@
 -- name: CreatePosts :one
 INSERT INTO posts (
+  site_id,
   title,
   content,
   author_id,
@@ -12,39 +13,47 @@ INSERT INTO posts (
   post_author,
   post_mime_type,
   published_by,
-  updated_by
+  updated_by,
+  featured_media_id
 ) VALUES (
-  $1, $2, $3, $4, DEFAULT, $5, $6, $7, $8, $9, $10, $11, $12
+  $1, $2, $3, $4, $5, DEFAULT, $6, $7, $8, $9, $10, $11, $12, $13, $14
 ) RETURNING *;
 
 
 -- name: GetPosts :one
 SELECT * FROM posts
-WHERE id = $1 LIMIT 1;
+WHERE site_id = $1 AND id = $2 LIMIT 1;
 
 -- name: ListPosts :many
 SELECT * FROM posts
+WHERE site_id = $1
 ORDER BY id
-LIMIT $1
-OFFSET $2;
+LIMIT $2
+OFFSET $3;
 
 -- name: UpdatePosts :one
 UPDATE posts
-  SET title = $2,
-  content = $3,
-  author_id = $4,
-  url = $5,
-  updated_at = $6,
-  status = $7,
-  published_at = $8,
-  edited_at = $9,
-  post_author = $10,
-  post_mime_type = $11,
-  published_by = $12,
-  updated_by = $13
-WHERE id = $1
+  SET title = $3,
+  content = $4,
+  author_id = $5,
+  url = $6,
+  updated_at = $7,
+  status = $8,
+  published_at = $9,
+  edited_at = $10,
+  post_author = $11,
+  post_mime_type = $12,
+  published_by = $13,
+  updated_by = $14,
+  featured_media_id = $15
+WHERE site_id = $1 AND id = $2
 RETURNING *;
 
 -- name: DeletePosts :exec
 DELETE FROM posts
-WHERE id = $1;
+WHERE site_id = $1 AND id = $2;
+
+-- name: GetPostsForUpdate :one
+SELECT * FROM posts
+WHERE site_id = $1 AND id = $2 LIMIT 1
+FOR UPDATE;
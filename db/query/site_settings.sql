@@ -0,0 +1,21 @@
+-- name: ListSiteSettings :many
+SELECT * FROM site_settings
+WHERE site_id = $1
+ORDER BY key;
+
+-- name: UpsertSiteSetting :one
+INSERT INTO site_settings (
+  site_id,
+  key,
+  value
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (site_id, key) DO UPDATE
+  SET value = EXCLUDED.value,
+  updated_at = now()
+RETURNING *;
+
+-- name: DeleteSiteSetting :exec
+DELETE FROM site_settings
+WHERE site_id = $1 AND key = $2;
@@ -0,0 +1,47 @@
+-- name: CreateSite :one
+INSERT INTO sites (
+  domain,
+  name
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetSite :one
+SELECT * FROM sites
+WHERE id = $1 LIMIT 1;
+
+-- name: GetSiteByDomain :one
+SELECT * FROM sites
+WHERE domain = $1 LIMIT 1;
+
+-- name: ListSites :many
+SELECT * FROM sites
+ORDER BY id
+LIMIT $1
+OFFSET $2;
+
+-- name: UpsertSiteMember :one
+INSERT INTO site_members (
+  site_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (site_id, user_id) DO UPDATE
+  SET role = EXCLUDED.role
+RETURNING *;
+
+-- name: GetSiteMember :one
+SELECT * FROM site_members
+WHERE site_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListSiteMembers :many
+SELECT * FROM site_members
+WHERE site_id = $1
+ORDER BY user_id;
+
+-- name: DeleteSiteMember :one
+DELETE FROM site_members
+WHERE site_id = $1 AND user_id = $2
+RETURNING *;
@@ -0,0 +1,74 @@
+-- name: CreateTranslationGroup :one
+INSERT INTO translation_groups (site_id) VALUES ($1)
+RETURNING *;
+
+-- name: SetPageTranslation :one
+UPDATE pages
+  SET locale = sqlc.narg(locale),
+  translation_group_id = sqlc.narg(translation_group_id)
+WHERE site_id = @site_id AND id = @id
+RETURNING *;
+
+-- name: ListPageTranslations :many
+SELECT * FROM pages
+WHERE site_id = $1 AND translation_group_id = $2
+ORDER BY locale;
+
+-- name: GetPageInLocale :one
+-- The variant of the page in the first of @locales it is translated to, or
+-- the page itself if there is none
+SELECT p.* FROM pages p
+JOIN pages source ON p.site_id = source.site_id
+  AND (p.id = source.id OR p.translation_group_id = source.translation_group_id)
+WHERE source.site_id = @site_id AND source.id = @id
+ORDER BY array_position(@locales::text[], p.locale::text) NULLS LAST, p.id = source.id DESC
+LIMIT 1;
+
+-- name: GetPageByUrlInLocale :one
+-- Like GetPageInLocale for the page at @url. When pages of several locales
+-- share the url, the one first in @locales is the source.
+WITH source AS (
+  SELECT pages.id, pages.site_id, pages.translation_group_id FROM pages
+  WHERE pages.site_id = @site_id AND pages.url = @url
+  ORDER BY array_position(@locales::text[], pages.locale::text) NULLS LAST, pages.id
+  LIMIT 1
+)
+SELECT p.* FROM pages p
+JOIN source ON p.site_id = source.site_id
+  AND (p.id = source.id OR p.translation_group_id = source.translation_group_id)
+ORDER BY array_position(@locales::text[], p.locale::text) NULLS LAST, p.id = source.id DESC
+LIMIT 1;
+
+-- name: SetPostTranslation :one
+UPDATE posts
+  SET locale = sqlc.narg(locale),
+  translation_group_id = sqlc.narg(translation_group_id)
+WHERE site_id = @site_id AND id = @id
+RETURNING *;
+
+-- name: ListPostTranslations :many
+SELECT * FROM posts
+WHERE site_id = $1 AND translation_group_id = $2
+ORDER BY locale;
+
+-- name: GetPostInLocale :one
+-- The variant of the post in the first of @locales it is translated to, or
+-- the post itself if there is none
+SELECT p.* FROM posts p
+JOIN posts source ON p.site_id = source.site_id
+  AND (p.id = source.id OR p.translation_group_id = source.translation_group_id)
+WHERE source.site_id = @site_id AND source.id = @id
+ORDER BY array_position(@locales::text[], p.locale::text) NULLS LAST, p.id = source.id DESC
+LIMIT 1;
+
+-- name: LeavePageTranslationGroup :one
+UPDATE pages
+  SET translation_group_id = NULL
+WHERE site_id = $1 AND id = $2
+RETURNING *;
+
+-- name: LeavePostTranslationGroup :one
+UPDATE posts
+  SET translation_group_id = NULL
+WHERE site_id = $1 AND id = $2
+RETURNING *;
@@ -42,3 +42,8 @@ RETURNING *;
 -- name: DeleteUsers :exec
 DELETE FROM users
 WHERE id = $1;
+
+-- name: GetSiteUser :one
+SELECT users.* FROM users
+JOIN site_members ON site_members.user_id = users.id
+WHERE site_members.site_id = $1 AND users.id = $2 LIMIT 1;
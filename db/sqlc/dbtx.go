@@ -0,0 +1,20 @@
+package frog_blossom_db
+
+import (
+	"regexp"
+)
+
+// DBTXMiddleware wraps a DBTX, e.g. to instrument every query. Middlewares
+// passed to NewStore are applied to the pool and to every transaction.
+type DBTXMiddleware func(DBTX) DBTX
+
+var queryNamePattern = regexp.MustCompile(`^-- name: (\w+) :\w+`)
+
+// QueryName returns the sqlc query name a generated statement starts with,
+// e.g. "GetPosts", or "unknown" for hand-written SQL
+func QueryName(query string) string {
+	if m := queryNamePattern.FindStringSubmatch(query); m != nil {
+		return m[1]
+	}
+	return "unknown"
+}
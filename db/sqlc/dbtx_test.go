@@ -0,0 +1,14 @@
+package frog_blossom_db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryName(t *testing.T) {
+	require.Equal(t, "GetPosts", QueryName(getPosts))
+	require.Equal(t, "CreateMeta", QueryName(createMeta))
+	require.Equal(t, "DeleteMetaByPostId", QueryName(deleteMetaByPostId))
+	require.Equal(t, "unknown", QueryName("SELECT 1"))
+}
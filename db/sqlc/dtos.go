@@ -1,6 +1,9 @@
 package frog_blossom_db
 
+import "encoding/json"
+
 type InitSetupConfigTxParams struct {
+	SiteID       int64               `json:"site_id"`
 	UserId       int64               `json:"user_id"`
 	Username     string              `json:"username"`
 	Email        string              `json:"email"`
@@ -17,7 +20,10 @@ type InitSetupConfigTxResult struct {
 	Metas []Meta `json:"meta_id"`
 }
 
+// Content Tx params are scoped to SiteID: rows are written to it and the
+// user must be a member allowed to write there
 type CreateContentTxParams struct {
+	SiteID   int64               `json:"site_id"`
 	UserId   int64               `json:"user_id"`
 	Username string              `json:"username"`
 	PageId   *int64              `json:"page_id"`
@@ -25,18 +31,22 @@ type CreateContentTxParams struct {
 	Pages    []CreatePagesParams `json:"pages"`
 	Posts    []CreatePostsParams `json:"posts"`
 	Metas    []CreateMetaParams  `json:"meta"`
+	// PageOptions[i] are the options of Pages[i]
+	PageOptions [][]PageOptionParams `json:"page_options"`
 }
 
 type CreateContentTxResult struct {
-	User   User   `json:"user"`
-	PageId *Page  `json:"pages_id"`
-	PostId *Post  `json:"post_id"`
-	Posts  []Post `json:"post"`
-	Metas  []Meta `json:"meta"`
-	Pages  []Page `json:"page"`
+	User        User           `json:"user"`
+	PageId      *Page          `json:"pages_id"`
+	PostId      *Post          `json:"post_id"`
+	Posts       []Post         `json:"post"`
+	Metas       []Meta         `json:"meta"`
+	Pages       []Page         `json:"page"`
+	PageOptions [][]PageOption `json:"page_options"`
 }
 
 type UpdateContentTxParams struct {
+	SiteID     int64               `json:"site_id"`
 	UserId     int64               `json:"user_id"`
 	Username   string              `json:"username"`
 	PageId     *int64              `json:"page_id"`
@@ -46,6 +56,9 @@ type UpdateContentTxParams struct {
 	Pages      []UpdatePagesParams `json:"pages"`
 	Posts      []UpdatePostsParams `json:"posts"`
 	Metas      []UpdateMetaParams  `json:"meta"`
+	// PageOptions[i] replaces the options of Pages[i]; a nil entry, or
+	// none at i, keeps the page's options as they are
+	PageOptions [][]PageOptionParams `json:"page_options"`
 }
 
 type UpdateContentTxResult struct {
@@ -57,9 +70,12 @@ type UpdateContentTxResult struct {
 	Pages      []Page `json:"page"`
 	Posts      []Post `json:"post"`
 	Metas      []Meta `json:"meta"`
+	// PageOptions[i] are the options of Pages[i] after the update
+	PageOptions [][]PageOption `json:"page_options"`
 }
 
 type DeleteContentTxParams struct {
+	SiteID int64  `json:"site_id"`
 	PageId *int64 `json:"page_id"`
 	PostId *int64 `json:"post_id"`
 }
@@ -69,3 +85,107 @@ type DeleteContentTxResult struct {
 	DeletedPage bool `json:"deleted_page"`
 	DeletedMeta bool `json:"deleted_meta"`
 }
+
+// PageOptionParams is one option of a page, stored at the position it has
+// in its list
+type PageOptionParams struct {
+	Name     string          `json:"name"`
+	Type     OptionType      `json:"type"`
+	Value    json.RawMessage `json:"value"`
+	Required bool            `json:"required"`
+}
+
+// MovePageTxParams puts a page under ParentID, or at the top level when it
+// is nil, with MenuOrder among its new siblings
+type MovePageTxParams struct {
+	SiteID    int64  `json:"site_id"`
+	PageID    int64  `json:"page_id"`
+	ParentID  *int64 `json:"parent_id"`
+	MenuOrder int64  `json:"menu_order"`
+}
+
+type MovePageTxResult struct {
+	Page Page `json:"page"`
+}
+
+// ReorderPagesTxParams lists every child of ParentID, or every top-level
+// page of the site when it is nil, in their new menu order
+type ReorderPagesTxParams struct {
+	SiteID   int64   `json:"site_id"`
+	ParentID *int64  `json:"parent_id"`
+	PageIDs  []int64 `json:"page_ids"`
+}
+
+type ReorderPagesTxResult struct {
+	Pages []Page `json:"pages"`
+}
+
+// AddPageComponentTxParams inserts a component at Position among the
+// page's components, or after the last one when Position is nil
+type AddPageComponentTxParams struct {
+	SiteID   int64           `json:"site_id"`
+	PageID   int64           `json:"page_id"`
+	Type     string          `json:"type"`
+	Value    json.RawMessage `json:"value"`
+	Position *int32          `json:"position"`
+}
+
+type MovePageComponentTxParams struct {
+	SiteID      int64 `json:"site_id"`
+	PageID      int64 `json:"page_id"`
+	ComponentID int64 `json:"component_id"`
+	Position    int32 `json:"position"`
+}
+
+type RemovePageComponentTxParams struct {
+	SiteID      int64 `json:"site_id"`
+	PageID      int64 `json:"page_id"`
+	ComponentID int64 `json:"component_id"`
+}
+
+// PageComponentsTxResult holds the component that was added, moved or
+// removed and the page's components afterwards
+type PageComponentsTxResult struct {
+	Component  PageComponent   `json:"component"`
+	Components []PageComponent `json:"components"`
+}
+
+// CreateSiteUserTxParams creates a user who joins SiteID with Role
+type CreateSiteUserTxParams struct {
+	SiteID int64             `json:"site_id"`
+	User   CreateUsersParams `json:"user"`
+	Role   SiteRole          `json:"role"`
+}
+
+type CreateSiteUserTxResult struct {
+	User   User       `json:"user"`
+	Member SiteMember `json:"member"`
+}
+
+// AddTranslationTxParams links ID to the translation group of SourceID as
+// its Locale variant, starting a group when SourceID has none. SourceID
+// takes SourceLocale if it has no locale yet. ID leaves any group it was in.
+type AddTranslationTxParams struct {
+	SiteID       int64  `json:"site_id"`
+	SourceID     int64  `json:"source_id"`
+	SourceLocale string `json:"source_locale"`
+	ID           int64  `json:"id"`
+	Locale       string `json:"locale"`
+}
+
+// PageTranslationsTxResult holds every page of the group, ordered by locale
+type PageTranslationsTxResult struct {
+	Pages []Page `json:"pages"`
+}
+
+// PostTranslationsTxResult holds every post of the group, ordered by locale
+type PostTranslationsTxResult struct {
+	Posts []Post `json:"posts"`
+}
+
+// CreateMediaTxResult holds the site's media item for the file. Created is
+// false when the site already had the file, which then keeps its row.
+type CreateMediaTxResult struct {
+	Media   Media `json:"media"`
+	Created bool  `json:"created"`
+}
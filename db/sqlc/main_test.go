@@ -1,30 +1,21 @@
-package frog_blossom_db
+package frog_blossom_db_test
 
 import (
 	"database/sql"
-	"log"
-	"os"
 	"testing"
 
-	_ "github.com/lib/pq"
-	"github.com/reflection/frog_blossom_db/config"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/testutil"
 )
 
-var testQueries *Queries
+var testQueries db.Store
 var testDB *sql.DB
+var testURL string
 
 func TestMain(m *testing.M) {
-	config, err := config.LoadConfig("../..")
-	if err != nil {
-		log.Fatal("cannot load config:", err)
-	}
-
-	testDB, err = sql.Open(config.DBDriver, config.DBSource)
-	if err != nil {
-		log.Fatal("cannot connect to database:", err)
-	}
-
-	testQueries = New(testDB)
-
-	os.Exit(m.Run())
+	testutil.Main(m, "db", func(schema *testutil.Schema) {
+		testDB = schema.DB
+		testURL = schema.URL
+		testQueries = schema.Store
+	})
 }
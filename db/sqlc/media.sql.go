@@ -0,0 +1,249 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: media.sql
+
+package frog_blossom_db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createMedia = `-- name: CreateMedia :one
+INSERT INTO media (
+  site_id,
+  uploader_id,
+  file_name,
+  mime_type,
+  size,
+  checksum,
+  storage_key,
+  width,
+  height,
+  alt_text
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (site_id, checksum) DO NOTHING
+RETURNING id, site_id, uploader_id, file_name, mime_type, size, checksum, storage_key, width, height, alt_text, created_at
+`
+
+type CreateMediaParams struct {
+	SiteID     int64         `json:"site_id"`
+	UploaderID int64         `json:"uploader_id"`
+	FileName   string        `json:"file_name"`
+	MimeType   string        `json:"mime_type"`
+	Size       int64         `json:"size"`
+	Checksum   string        `json:"checksum"`
+	StorageKey string        `json:"storage_key"`
+	Width      sql.NullInt32 `json:"width"`
+	Height     sql.NullInt32 `json:"height"`
+	AltText    string        `json:"alt_text"`
+}
+
+// A file the site already has is not inserted again; see GetMediaByChecksum
+func (q *Queries) CreateMedia(ctx context.Context, arg CreateMediaParams) (Media, error) {
+	row := q.db.QueryRowContext(ctx, createMedia,
+		arg.SiteID,
+		arg.UploaderID,
+		arg.FileName,
+		arg.MimeType,
+		arg.Size,
+		arg.Checksum,
+		arg.StorageKey,
+		arg.Width,
+		arg.Height,
+		arg.AltText,
+	)
+	var i Media
+	err := row.Scan(
+		&i.ID,
+		&i.SiteID,
+		&i.UploaderID,
+		&i.FileName,
+		&i.MimeType,
+		&i.Size,
+		&i.Checksum,
+		&i.StorageKey,
+		&i.Width,
+		&i.Height,
+		&i.AltText,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteMedia = `-- name: DeleteMedia :one
+DELETE FROM media
+WHERE site_id = $1 AND id = $2
+RETURNING id, site_id, uploader_id, file_name, mime_type, size, checksum, storage_key, width, height, alt_text, created_at
+`
+
+type DeleteMediaParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) DeleteMedia(ctx context.Context, arg DeleteMediaParams) (Media, error) {
+	row := q.db.QueryRowContext(ctx, deleteMedia, arg.SiteID, arg.ID)
+	var i Media
+	err := row.Scan(
+		&i.ID,
+		&i.SiteID,
+		&i.UploaderID,
+		&i.FileName,
+		&i.MimeType,
+		&i.Size,
+		&i.Checksum,
+		&i.StorageKey,
+		&i.Width,
+		&i.Height,
+		&i.AltText,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMedia = `-- name: GetMedia :one
+SELECT id, site_id, uploader_id, file_name, mime_type, size, checksum, storage_key, width, height, alt_text, created_at FROM media
+WHERE site_id = $1 AND id = $2 LIMIT 1
+`
+
+type GetMediaParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetMedia(ctx context.Context, arg GetMediaParams) (Media, error) {
+	row := q.db.QueryRowContext(ctx, getMedia, arg.SiteID, arg.ID)
+	var i Media
+	err := row.Scan(
+		&i.ID,
+		&i.SiteID,
+		&i.UploaderID,
+		&i.FileName,
+		&i.MimeType,
+		&i.Size,
+		&i.Checksum,
+		&i.StorageKey,
+		&i.Width,
+		&i.Height,
+		&i.AltText,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMediaByChecksum = `-- name: GetMediaByChecksum :one
+SELECT id, site_id, uploader_id, file_name, mime_type, size, checksum, storage_key, width, height, alt_text, created_at FROM media
+WHERE site_id = $1 AND checksum = $2 LIMIT 1
+`
+
+type GetMediaByChecksumParams struct {
+	SiteID   int64  `json:"site_id"`
+	Checksum string `json:"checksum"`
+}
+
+func (q *Queries) GetMediaByChecksum(ctx context.Context, arg GetMediaByChecksumParams) (Media, error) {
+	row := q.db.QueryRowContext(ctx, getMediaByChecksum, arg.SiteID, arg.Checksum)
+	var i Media
+	err := row.Scan(
+		&i.ID,
+		&i.SiteID,
+		&i.UploaderID,
+		&i.FileName,
+		&i.MimeType,
+		&i.Size,
+		&i.Checksum,
+		&i.StorageKey,
+		&i.Width,
+		&i.Height,
+		&i.AltText,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listMedia = `-- name: ListMedia :many
+SELECT id, site_id, uploader_id, file_name, mime_type, size, checksum, storage_key, width, height, alt_text, created_at FROM media
+WHERE site_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListMediaParams struct {
+	SiteID int64 `json:"site_id"`
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListMedia(ctx context.Context, arg ListMediaParams) ([]Media, error) {
+	rows, err := q.db.QueryContext(ctx, listMedia, arg.SiteID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Media
+	for rows.Next() {
+		var i Media
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UploaderID,
+			&i.FileName,
+			&i.MimeType,
+			&i.Size,
+			&i.Checksum,
+			&i.StorageKey,
+			&i.Width,
+			&i.Height,
+			&i.AltText,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateMediaAltText = `-- name: UpdateMediaAltText :one
+UPDATE media
+  SET alt_text = $3
+WHERE site_id = $1 AND id = $2
+RETURNING id, site_id, uploader_id, file_name, mime_type, size, checksum, storage_key, width, height, alt_text, created_at
+`
+
+type UpdateMediaAltTextParams struct {
+	SiteID  int64  `json:"site_id"`
+	ID      int64  `json:"id"`
+	AltText string `json:"alt_text"`
+}
+
+func (q *Queries) UpdateMediaAltText(ctx context.Context, arg UpdateMediaAltTextParams) (Media, error) {
+	row := q.db.QueryRowContext(ctx, updateMediaAltText, arg.SiteID, arg.ID, arg.AltText)
+	var i Media
+	err := row.Scan(
+		&i.ID,
+		&i.SiteID,
+		&i.UploaderID,
+		&i.FileName,
+		&i.MimeType,
+		&i.Size,
+		&i.Checksum,
+		&i.StorageKey,
+		&i.Width,
+		&i.Height,
+		&i.AltText,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,146 @@
+package frog_blossom_db_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+// mediaParams describes content uploaded to the site by uploaderID
+func mediaParams(siteID, uploaderID int64, content string) db.CreateMediaParams {
+	sum := sha256.Sum256([]byte(content))
+	checksum := hex.EncodeToString(sum[:])
+	return db.CreateMediaParams{
+		SiteID:     siteID,
+		UploaderID: uploaderID,
+		FileName:   "pond.png",
+		MimeType:   "image/png",
+		Size:       int64(len(content)),
+		Checksum:   checksum,
+		StorageKey: "sites/test/" + checksum,
+		Width:      sql.NullInt32{Int32: 640, Valid: true},
+		Height:     sql.NullInt32{Int32: 480, Valid: true},
+	}
+}
+
+func createMedia(t testing.TB, siteID, uploaderID int64, content string) db.Media {
+	t.Helper()
+
+	result, err := db.NewStore(testDB).CreateMediaTx(context.Background(), mediaParams(siteID, uploaderID, content))
+	require.NoError(t, err)
+	return result.Media
+}
+
+func TestCreateMediaTx(t *testing.T) {
+	// Arrange
+	store := db.NewStore(testDB)
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+	editor := f.SiteUser(site.ID)
+	args := mediaParams(site.ID, editor.ID, f.String(32))
+
+	// Act
+	first, err := store.CreateMediaTx(context.Background(), args)
+	require.NoError(t, err)
+	again, err := store.CreateMediaTx(context.Background(), args)
+	require.NoError(t, err)
+
+	// Assert
+	require.True(t, first.Created)
+	require.Equal(t, args.Checksum, first.Media.Checksum)
+	require.Equal(t, int32(640), first.Media.Width.Int32)
+	require.Empty(t, first.Media.AltText)
+
+	require.False(t, again.Created)
+	require.Equal(t, first.Media, again.Media)
+
+	// another site gets its own copy
+	other := f.Site()
+	args.SiteID = other.ID
+	args.UploaderID = f.SiteUser(other.ID).ID
+	elsewhere, err := store.CreateMediaTx(context.Background(), args)
+	require.NoError(t, err)
+	require.True(t, elsewhere.Created)
+	require.NotEqual(t, first.Media.ID, elsewhere.Media.ID)
+}
+
+func TestCreateMediaTxRequiresAuthor(t *testing.T) {
+	store := db.NewStore(testDB)
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+
+	viewer := f.User()
+	f.Member(site.ID, viewer.ID, db.SiteRoleViewer)
+	_, err := store.CreateMediaTx(context.Background(), mediaParams(site.ID, viewer.ID, f.String(32)))
+	require.ErrorIs(t, err, apperr.ErrForbidden)
+
+	_, err = store.CreateMediaTx(context.Background(), mediaParams(site.ID, f.User().ID, f.String(32)))
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func TestUpdateMediaAltText(t *testing.T) {
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+	item := createMedia(t, site.ID, f.SiteUser(site.ID).ID, f.String(32))
+
+	updated, err := testQueries.UpdateMediaAltText(context.Background(), db.UpdateMediaAltTextParams{
+		SiteID:  site.ID,
+		ID:      item.ID,
+		AltText: "Lily pads on a pond",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Lily pads on a pond", updated.AltText)
+	require.Equal(t, item.Checksum, updated.Checksum)
+}
+
+func TestDeleteMediaTxInUse(t *testing.T) {
+	// Arrange
+	store := db.NewStore(testDB)
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+	item := createMedia(t, site.ID, f.SiteUser(site.ID).ID, f.String(32))
+	post := f.Post(func(p *db.CreatePostsParams) {
+		p.SiteID = site.ID
+		p.FeaturedMediaID = sql.NullInt64{Int64: item.ID, Valid: true}
+	})
+	meta := f.Meta(func(m *db.CreateMetaParams) {
+		m.SiteID = site.ID
+		m.MetaOgImageID = sql.NullInt64{Int64: item.ID, Valid: true}
+	})
+	args := db.DeleteMediaParams{SiteID: site.ID, ID: item.ID}
+
+	// Act, Assert
+	_, err := store.DeleteMediaTx(context.Background(), args)
+	require.ErrorIs(t, err, apperr.ErrConflict)
+
+	require.NoError(t, testQueries.DeleteMeta(context.Background(), db.DeleteMetaParams{SiteID: site.ID, ID: meta.ID}))
+	_, err = store.DeleteMediaTx(context.Background(), args)
+	require.ErrorIs(t, err, apperr.ErrConflict)
+
+	require.NoError(t, testQueries.DeletePosts(context.Background(), db.DeletePostsParams{SiteID: site.ID, ID: post.ID}))
+	deleted, err := store.DeleteMediaTx(context.Background(), args)
+	require.NoError(t, err)
+	require.Equal(t, item.StorageKey, deleted.StorageKey)
+
+	_, err = store.DeleteMediaTx(context.Background(), args)
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func TestMediaOfAnotherSiteCanNotBeReferenced(t *testing.T) {
+	f := fixtures.For(t, testQueries)
+	site, other := f.Site(), f.Site()
+	item := createMedia(t, other.ID, f.SiteUser(other.ID).ID, f.String(32))
+
+	_, err := testQueries.CreatePosts(context.Background(), f.PostParams(func(p *db.CreatePostsParams) {
+		p.SiteID = site.ID
+		p.FeaturedMediaID = sql.NullInt64{Int64: item.ID, Valid: true}
+	}))
+	require.ErrorIs(t, apperr.FromDB(err), apperr.ErrValidation)
+}
@@ -12,6 +12,7 @@ import (
 
 const createMeta = `-- name: CreateMeta :one
 INSERT INTO meta (
+  site_id,
   page_id,
   posts_id,
   meta_title,
@@ -19,16 +20,16 @@ INSERT INTO meta (
   meta_robots,
   meta_og_image,
   locale,
-  page_amount,
-  site_language,
   meta_key,
-  meta_value
+  meta_value,
+  meta_og_image_id
 ) VALUES (
   $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-) RETURNING id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, page_amount, site_language, meta_key, meta_value
+) RETURNING id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, meta_key, meta_value, site_id, meta_og_image_id
 `
 
 type CreateMetaParams struct {
+	SiteID          int64          `json:"site_id"`
 	PageID          sql.NullInt64  `json:"page_id"`
 	PostsID         sql.NullInt64  `json:"posts_id"`
 	MetaTitle       sql.NullString `json:"meta_title"`
@@ -36,14 +37,14 @@ type CreateMetaParams struct {
 	MetaRobots      sql.NullString `json:"meta_robots"`
 	MetaOgImage     sql.NullString `json:"meta_og_image"`
 	Locale          sql.NullString `json:"locale"`
-	PageAmount      int64          `json:"page_amount"`
-	SiteLanguage    sql.NullString `json:"site_language"`
 	MetaKey         string         `json:"meta_key"`
 	MetaValue       string         `json:"meta_value"`
+	MetaOgImageID   sql.NullInt64  `json:"meta_og_image_id"`
 }
 
 func (q *Queries) CreateMeta(ctx context.Context, arg CreateMetaParams) (Meta, error) {
 	row := q.db.QueryRowContext(ctx, createMeta,
+		arg.SiteID,
 		arg.PageID,
 		arg.PostsID,
 		arg.MetaTitle,
@@ -51,10 +52,9 @@ func (q *Queries) CreateMeta(ctx context.Context, arg CreateMetaParams) (Meta, e
 		arg.MetaRobots,
 		arg.MetaOgImage,
 		arg.Locale,
-		arg.PageAmount,
-		arg.SiteLanguage,
 		arg.MetaKey,
 		arg.MetaValue,
+		arg.MetaOgImageID,
 	)
 	var i Meta
 	err := row.Scan(
@@ -66,49 +66,69 @@ func (q *Queries) CreateMeta(ctx context.Context, arg CreateMetaParams) (Meta, e
 		&i.MetaRobots,
 		&i.MetaOgImage,
 		&i.Locale,
-		&i.PageAmount,
-		&i.SiteLanguage,
 		&i.MetaKey,
 		&i.MetaValue,
+		&i.SiteID,
+		&i.MetaOgImageID,
 	)
 	return i, err
 }
 
 const deleteMeta = `-- name: DeleteMeta :exec
 DELETE FROM meta
-WHERE id = $1
+WHERE site_id = $1 AND id = $2
 `
 
-func (q *Queries) DeleteMeta(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteMeta, id)
+type DeleteMetaParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) DeleteMeta(ctx context.Context, arg DeleteMetaParams) error {
+	_, err := q.db.ExecContext(ctx, deleteMeta, arg.SiteID, arg.ID)
 	return err
 }
 
 const deleteMetaByPageId = `-- name: DeleteMetaByPageId :exec
-DELETE FROM meta WHERE page_id = $1
+DELETE FROM meta WHERE site_id = $1 AND page_id = $2
 `
 
-func (q *Queries) DeleteMetaByPageId(ctx context.Context, pageID sql.NullInt64) error {
-	_, err := q.db.ExecContext(ctx, deleteMetaByPageId, pageID)
+type DeleteMetaByPageIdParams struct {
+	SiteID int64         `json:"site_id"`
+	PageID sql.NullInt64 `json:"page_id"`
+}
+
+func (q *Queries) DeleteMetaByPageId(ctx context.Context, arg DeleteMetaByPageIdParams) error {
+	_, err := q.db.ExecContext(ctx, deleteMetaByPageId, arg.SiteID, arg.PageID)
 	return err
 }
 
 const deleteMetaByPostId = `-- name: DeleteMetaByPostId :exec
-DELETE FROM meta WHERE posts_id = $1
+DELETE FROM meta WHERE site_id = $1 AND posts_id = $2
 `
 
-func (q *Queries) DeleteMetaByPostId(ctx context.Context, postsID sql.NullInt64) error {
-	_, err := q.db.ExecContext(ctx, deleteMetaByPostId, postsID)
+type DeleteMetaByPostIdParams struct {
+	SiteID  int64         `json:"site_id"`
+	PostsID sql.NullInt64 `json:"posts_id"`
+}
+
+func (q *Queries) DeleteMetaByPostId(ctx context.Context, arg DeleteMetaByPostIdParams) error {
+	_, err := q.db.ExecContext(ctx, deleteMetaByPostId, arg.SiteID, arg.PostsID)
 	return err
 }
 
 const getMeta = `-- name: GetMeta :one
-SELECT id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, page_amount, site_language, meta_key, meta_value FROM meta
-WHERE id = $1 LIMIT 1
+SELECT id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, meta_key, meta_value, site_id, meta_og_image_id FROM meta
+WHERE site_id = $1 AND id = $2 LIMIT 1
 `
 
-func (q *Queries) GetMeta(ctx context.Context, id int64) (Meta, error) {
-	row := q.db.QueryRowContext(ctx, getMeta, id)
+type GetMetaParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetMeta(ctx context.Context, arg GetMetaParams) (Meta, error) {
+	row := q.db.QueryRowContext(ctx, getMeta, arg.SiteID, arg.ID)
 	var i Meta
 	err := row.Scan(
 		&i.ID,
@@ -119,22 +139,27 @@ func (q *Queries) GetMeta(ctx context.Context, id int64) (Meta, error) {
 		&i.MetaRobots,
 		&i.MetaOgImage,
 		&i.Locale,
-		&i.PageAmount,
-		&i.SiteLanguage,
 		&i.MetaKey,
 		&i.MetaValue,
+		&i.SiteID,
+		&i.MetaOgImageID,
 	)
 	return i, err
 }
 
 const getMetaByPageIDForUpdate = `-- name: GetMetaByPageIDForUpdate :one
-SELECT id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, page_amount, site_language, meta_key, meta_value FROM meta
-WHERE page_id = $1 LIMIT 1
+SELECT id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, meta_key, meta_value, site_id, meta_og_image_id FROM meta
+WHERE site_id = $1 AND page_id = $2 LIMIT 1
 FOR NO KEY UPDATE
 `
 
-func (q *Queries) GetMetaByPageIDForUpdate(ctx context.Context, pageID sql.NullInt64) (Meta, error) {
-	row := q.db.QueryRowContext(ctx, getMetaByPageIDForUpdate, pageID)
+type GetMetaByPageIDForUpdateParams struct {
+	SiteID int64         `json:"site_id"`
+	PageID sql.NullInt64 `json:"page_id"`
+}
+
+func (q *Queries) GetMetaByPageIDForUpdate(ctx context.Context, arg GetMetaByPageIDForUpdateParams) (Meta, error) {
+	row := q.db.QueryRowContext(ctx, getMetaByPageIDForUpdate, arg.SiteID, arg.PageID)
 	var i Meta
 	err := row.Scan(
 		&i.ID,
@@ -145,22 +170,27 @@ func (q *Queries) GetMetaByPageIDForUpdate(ctx context.Context, pageID sql.NullI
 		&i.MetaRobots,
 		&i.MetaOgImage,
 		&i.Locale,
-		&i.PageAmount,
-		&i.SiteLanguage,
 		&i.MetaKey,
 		&i.MetaValue,
+		&i.SiteID,
+		&i.MetaOgImageID,
 	)
 	return i, err
 }
 
 const getMetaByPostsIDForUpdate = `-- name: GetMetaByPostsIDForUpdate :one
-SELECT id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, page_amount, site_language, meta_key, meta_value FROM meta
-WHERE posts_id = $1 LIMIT 1
+SELECT id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, meta_key, meta_value, site_id, meta_og_image_id FROM meta
+WHERE site_id = $1 AND posts_id = $2 LIMIT 1
 FOR NO KEY UPDATE
 `
 
-func (q *Queries) GetMetaByPostsIDForUpdate(ctx context.Context, postsID sql.NullInt64) (Meta, error) {
-	row := q.db.QueryRowContext(ctx, getMetaByPostsIDForUpdate, postsID)
+type GetMetaByPostsIDForUpdateParams struct {
+	SiteID  int64         `json:"site_id"`
+	PostsID sql.NullInt64 `json:"posts_id"`
+}
+
+func (q *Queries) GetMetaByPostsIDForUpdate(ctx context.Context, arg GetMetaByPostsIDForUpdateParams) (Meta, error) {
+	row := q.db.QueryRowContext(ctx, getMetaByPostsIDForUpdate, arg.SiteID, arg.PostsID)
 	var i Meta
 	err := row.Scan(
 		&i.ID,
@@ -171,28 +201,30 @@ func (q *Queries) GetMetaByPostsIDForUpdate(ctx context.Context, postsID sql.Nul
 		&i.MetaRobots,
 		&i.MetaOgImage,
 		&i.Locale,
-		&i.PageAmount,
-		&i.SiteLanguage,
 		&i.MetaKey,
 		&i.MetaValue,
+		&i.SiteID,
+		&i.MetaOgImageID,
 	)
 	return i, err
 }
 
 const listMeta = `-- name: ListMeta :many
-SELECT id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, page_amount, site_language, meta_key, meta_value FROM meta
+SELECT id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, meta_key, meta_value, site_id, meta_og_image_id FROM meta
+WHERE site_id = $1
 ORDER BY id
-LIMIT $1
-OFFSET $2
+LIMIT $2
+OFFSET $3
 `
 
 type ListMetaParams struct {
+	SiteID int64 `json:"site_id"`
 	Limit  int32 `json:"limit"`
 	Offset int32 `json:"offset"`
 }
 
 func (q *Queries) ListMeta(ctx context.Context, arg ListMetaParams) ([]Meta, error) {
-	rows, err := q.db.QueryContext(ctx, listMeta, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listMeta, arg.SiteID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -209,10 +241,10 @@ func (q *Queries) ListMeta(ctx context.Context, arg ListMetaParams) ([]Meta, err
 			&i.MetaRobots,
 			&i.MetaOgImage,
 			&i.Locale,
-			&i.PageAmount,
-			&i.SiteLanguage,
 			&i.MetaKey,
 			&i.MetaValue,
+			&i.SiteID,
+			&i.MetaOgImageID,
 		); err != nil {
 			return nil, err
 		}
@@ -229,22 +261,22 @@ func (q *Queries) ListMeta(ctx context.Context, arg ListMetaParams) ([]Meta, err
 
 const updateMeta = `-- name: UpdateMeta :one
 UPDATE meta
-  SET page_id = $2,
-posts_id = $3,
-meta_title = $4,
-meta_description = $5,
-meta_robots = $6,
-meta_og_image = $7,
-locale = $8,
-page_amount = $9,
-site_language = $10,
-meta_key = $11,
-meta_value = $12
-WHERE id = $1
-RETURNING id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, page_amount, site_language, meta_key, meta_value
+  SET page_id = $3,
+posts_id = $4,
+meta_title = $5,
+meta_description = $6,
+meta_robots = $7,
+meta_og_image = $8,
+locale = $9,
+meta_key = $10,
+meta_value = $11,
+meta_og_image_id = $12
+WHERE site_id = $1 AND id = $2
+RETURNING id, page_id, posts_id, meta_title, meta_description, meta_robots, meta_og_image, locale, meta_key, meta_value, site_id, meta_og_image_id
 `
 
 type UpdateMetaParams struct {
+	SiteID          int64          `json:"site_id"`
 	ID              int64          `json:"id"`
 	PageID          sql.NullInt64  `json:"page_id"`
 	PostsID         sql.NullInt64  `json:"posts_id"`
@@ -253,14 +285,14 @@ type UpdateMetaParams struct {
 	MetaRobots      sql.NullString `json:"meta_robots"`
 	MetaOgImage     sql.NullString `json:"meta_og_image"`
 	Locale          sql.NullString `json:"locale"`
-	PageAmount      int64          `json:"page_amount"`
-	SiteLanguage    sql.NullString `json:"site_language"`
 	MetaKey         string         `json:"meta_key"`
 	MetaValue       string         `json:"meta_value"`
+	MetaOgImageID   sql.NullInt64  `json:"meta_og_image_id"`
 }
 
 func (q *Queries) UpdateMeta(ctx context.Context, arg UpdateMetaParams) (Meta, error) {
 	row := q.db.QueryRowContext(ctx, updateMeta,
+		arg.SiteID,
 		arg.ID,
 		arg.PageID,
 		arg.PostsID,
@@ -269,10 +301,9 @@ func (q *Queries) UpdateMeta(ctx context.Context, arg UpdateMetaParams) (Meta, e
 		arg.MetaRobots,
 		arg.MetaOgImage,
 		arg.Locale,
-		arg.PageAmount,
-		arg.SiteLanguage,
 		arg.MetaKey,
 		arg.MetaValue,
+		arg.MetaOgImageID,
 	)
 	var i Meta
 	err := row.Scan(
@@ -284,10 +315,10 @@ func (q *Queries) UpdateMeta(ctx context.Context, arg UpdateMetaParams) (Meta, e
 		&i.MetaRobots,
 		&i.MetaOgImage,
 		&i.Locale,
-		&i.PageAmount,
-		&i.SiteLanguage,
 		&i.MetaKey,
 		&i.MetaValue,
+		&i.SiteID,
+		&i.MetaOgImageID,
 	)
 	return i, err
 }
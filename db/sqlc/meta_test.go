@@ -1,32 +1,18 @@
-package frog_blossom_db
+package frog_blossom_db_test
 
 import (
 	"context"
 	"database/sql"
-	"github.com/stretchr/testify/require"
 	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
 )
 
-func createRandomMeta(t *testing.T) Meta {
+func createRandomMeta(t *testing.T) db.Meta {
 	// Arrange
-	randomPage := createRandomPage(t)
-	randomPosts := createRandomPosts(t)
-
-	args := CreateMetaParams{
-		PageID:          sql.NullInt64{Int64: randomPage.ID, Valid: true},
-		PostsID:         sql.NullInt64{Int64: randomPosts.ID, Valid: true},
-		MetaTitle:       sql.NullString{String: "Sample Meta Title", Valid: true},
-		MetaDescription: sql.NullString{String: "Sample Meta Description", Valid: true},
-		MetaRobots:      sql.NullString{String: "index, follow", Valid: true},
-		MetaOgImage:     sql.NullString{String: "https://example.com/image.jpg", Valid: true},
-		Locale:          sql.NullString{String: "ja_JP", Valid: true},
-		PageAmount:      3,
-		SiteLanguage: sql.NullString{
-			String: "ja", Valid: true,
-		},
-		MetaKey:   "_thumbnail_id",
-		MetaValue: "12345",
-	}
+	args := fixtures.For(t, testQueries).MetaParams()
 
 	// Act
 	meta, err := testQueries.CreateMeta(context.Background(), args)
@@ -34,6 +20,7 @@ func createRandomMeta(t *testing.T) Meta {
 	require.NotEmpty(t, meta)
 
 	// Assert
+	require.Equal(t, args.SiteID, meta.SiteID)
 	require.Equal(t, args.PageID, meta.PageID)
 	require.Equal(t, args.PostsID, meta.PostsID)
 	require.Equal(t, args.MetaTitle, meta.MetaTitle)
@@ -41,8 +28,6 @@ func createRandomMeta(t *testing.T) Meta {
 	require.Equal(t, args.MetaRobots, meta.MetaRobots)
 	require.Equal(t, args.MetaOgImage, meta.MetaOgImage)
 	require.Equal(t, args.Locale, meta.Locale)
-	require.Equal(t, args.PageAmount, meta.PageAmount)
-	require.Equal(t, args.SiteLanguage, meta.SiteLanguage)
 	require.Equal(t, args.MetaKey, meta.MetaKey)
 	require.Equal(t, args.MetaValue, meta.MetaValue)
 
@@ -58,7 +43,7 @@ func TestGetMeta(t *testing.T) {
 	randomMeta := createRandomMeta(t)
 
 	// Act
-	meta, err := testQueries.GetMeta(context.Background(), randomMeta.ID)
+	meta, err := testQueries.GetMeta(context.Background(), db.GetMetaParams{SiteID: randomMeta.SiteID, ID: randomMeta.ID})
 	require.NoError(t, err)
 	require.NotEmpty(t, meta)
 
@@ -69,31 +54,24 @@ func TestGetMeta(t *testing.T) {
 	require.Equal(t, randomMeta.MetaRobots, meta.MetaRobots)
 	require.Equal(t, randomMeta.MetaOgImage, meta.MetaOgImage)
 	require.Equal(t, randomMeta.Locale, meta.Locale)
-	require.Equal(t, randomMeta.PageAmount, meta.PageAmount)
-	require.Equal(t, randomMeta.SiteLanguage, meta.SiteLanguage)
 	require.Equal(t, randomMeta.MetaKey, meta.MetaKey)
 	require.Equal(t, randomMeta.MetaValue, meta.MetaValue)
 }
 
 func TestUpdateMeta(t *testing.T) {
 	// Arrange
-	randomPage := createRandomPage(t)
-	randomPosts := createRandomPosts(t)
+	f := fixtures.For(t, testQueries)
 	randomMeta := createRandomMeta(t)
+	// meta may only point at content of its own site
+	randomPage := f.Page(func(p *db.CreatePagesParams) { p.SiteID = randomMeta.SiteID })
+	randomPosts := f.Post(func(p *db.CreatePostsParams) { p.SiteID = randomMeta.SiteID })
 
-	pages, err := testQueries.GetPages(context.Background(), randomPage.ID)
-	require.NoError(t, err)
-	require.NotEmpty(t, pages)
-
-	posts, err := testQueries.GetPosts(context.Background(), randomPosts.ID)
-	require.NoError(t, err)
-	require.NotEmpty(t, posts)
-
-	metas, err := testQueries.GetMeta(context.Background(), randomMeta.ID)
+	metas, err := testQueries.GetMeta(context.Background(), db.GetMetaParams{SiteID: randomMeta.SiteID, ID: randomMeta.ID})
 	require.NoError(t, err)
 	require.NotEmpty(t, metas)
 
-	args := UpdateMetaParams{
+	args := db.UpdateMetaParams{
+		SiteID:          metas.SiteID,
 		ID:              metas.ID,
 		PageID:          sql.NullInt64{Int64: randomPage.ID, Valid: true},
 		PostsID:         sql.NullInt64{Int64: randomPosts.ID, Valid: true},
@@ -102,12 +80,8 @@ func TestUpdateMeta(t *testing.T) {
 		MetaRobots:      sql.NullString{String: "index, follow", Valid: true},
 		MetaOgImage:     sql.NullString{String: "https://example.com/image.jpg", Valid: true},
 		Locale:          sql.NullString{String: "ja_JP", Valid: true},
-		PageAmount:      3,
-		SiteLanguage: sql.NullString{
-			String: "ja", Valid: true,
-		},
-		MetaKey:   "_thumbnail_id",
-		MetaValue: "12345",
+		MetaKey:         "_thumbnail_id",
+		MetaValue:       "12345",
 	}
 
 	// Act
@@ -124,8 +98,6 @@ func TestUpdateMeta(t *testing.T) {
 	require.Equal(t, args.MetaRobots, meta.MetaRobots)
 	require.Equal(t, args.MetaOgImage, meta.MetaOgImage)
 	require.Equal(t, args.Locale, meta.Locale)
-	require.Equal(t, args.PageAmount, meta.PageAmount)
-	require.Equal(t, args.SiteLanguage, meta.SiteLanguage)
 	require.Equal(t, args.MetaKey, meta.MetaKey)
 	require.Equal(t, args.MetaValue, meta.MetaValue)
 }
@@ -134,11 +106,11 @@ func TestDeleteMeta(t *testing.T) {
 	// Arrange
 	randomMeta := createRandomMeta(t)
 
-	err := testQueries.DeleteMeta(context.Background(), randomMeta.ID)
+	err := testQueries.DeleteMeta(context.Background(), db.DeleteMetaParams{SiteID: randomMeta.SiteID, ID: randomMeta.ID})
 	require.NoError(t, err)
 
 	// Act
-	meta, err := testQueries.GetMeta(context.Background(), randomMeta.ID)
+	meta, err := testQueries.GetMeta(context.Background(), db.GetMetaParams{SiteID: randomMeta.SiteID, ID: randomMeta.ID})
 
 	// Assert
 	require.Error(t, err)
@@ -148,12 +120,16 @@ func TestDeleteMeta(t *testing.T) {
 
 func TestListMeta(t *testing.T) {
 	// Arrange
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
 	for i := 0; i < 10; i++ {
-		createRandomMeta(t)
+		f.Meta(func(m *db.CreateMetaParams) { m.SiteID = site.ID })
 	}
+	createRandomMeta(t)
 
-	args := ListMetaParams{
-		Limit:  5,
+	args := db.ListMetaParams{
+		SiteID: site.ID,
+		Limit:  10,
 		Offset: 5,
 	}
 
@@ -165,6 +141,6 @@ func TestListMeta(t *testing.T) {
 	require.Len(t, meta, 5)
 
 	for _, meta := range meta {
-		require.NotEmpty(t, meta)
+		require.Equal(t, site.ID, meta.SiteID)
 	}
 }
@@ -6,9 +6,158 @@ package frog_blossom_db
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
+type OptionType string
+
+const (
+	OptionTypeString  OptionType = "string"
+	OptionTypeNumber  OptionType = "number"
+	OptionTypeBoolean OptionType = "boolean"
+	OptionTypeJson    OptionType = "json"
+)
+
+func (e *OptionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OptionType(s)
+	case string:
+		*e = OptionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OptionType: %T", src)
+	}
+	return nil
+}
+
+type NullOptionType struct {
+	OptionType OptionType `json:"option_type"`
+	Valid      bool       `json:"valid"` // Valid is true if OptionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOptionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.OptionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OptionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOptionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OptionType), nil
+}
+
+type PostStatus string
+
+const (
+	PostStatusDraft   PostStatus = "draft"
+	PostStatusPending PostStatus = "pending"
+	PostStatusPrivate PostStatus = "private"
+	PostStatusPublish PostStatus = "publish"
+)
+
+func (e *PostStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PostStatus(s)
+	case string:
+		*e = PostStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PostStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPostStatus struct {
+	PostStatus PostStatus `json:"post_status"`
+	Valid      bool       `json:"valid"` // Valid is true if PostStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPostStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PostStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PostStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPostStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PostStatus), nil
+}
+
+type SiteRole string
+
+const (
+	SiteRoleOwner  SiteRole = "owner"
+	SiteRoleEditor SiteRole = "editor"
+	SiteRoleViewer SiteRole = "viewer"
+)
+
+func (e *SiteRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SiteRole(s)
+	case string:
+		*e = SiteRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SiteRole: %T", src)
+	}
+	return nil
+}
+
+type NullSiteRole struct {
+	SiteRole SiteRole `json:"site_role"`
+	Valid    bool     `json:"valid"` // Valid is true if SiteRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSiteRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.SiteRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SiteRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSiteRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SiteRole), nil
+}
+
+type Media struct {
+	ID         int64         `json:"id"`
+	SiteID     int64         `json:"site_id"`
+	UploaderID int64         `json:"uploader_id"`
+	FileName   string        `json:"file_name"`
+	MimeType   string        `json:"mime_type"`
+	Size       int64         `json:"size"`
+	Checksum   string        `json:"checksum"`
+	StorageKey string        `json:"storage_key"`
+	Width      sql.NullInt32 `json:"width"`
+	Height     sql.NullInt32 `json:"height"`
+	AltText    string        `json:"alt_text"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
 type Meta struct {
 	ID              int64          `json:"id"`
 	PageID          sql.NullInt64  `json:"page_id"`
@@ -18,44 +167,92 @@ type Meta struct {
 	MetaRobots      sql.NullString `json:"meta_robots"`
 	MetaOgImage     sql.NullString `json:"meta_og_image"`
 	Locale          sql.NullString `json:"locale"`
-	PageAmount      int64          `json:"page_amount"`
-	SiteLanguage    sql.NullString `json:"site_language"`
 	MetaKey         string         `json:"meta_key"`
 	MetaValue       string         `json:"meta_value"`
+	SiteID          int64          `json:"site_id"`
+	MetaOgImageID   sql.NullInt64  `json:"meta_og_image_id"`
 }
 
 type Page struct {
-	ID             int64  `json:"id"`
-	Domain         string `json:"domain"`
-	AuthorID       int64  `json:"author_id"`
-	PageAuthor     string `json:"page_author"`
-	Title          string `json:"title"`
-	Url            string `json:"url"`
-	MenuOrder      int64  `json:"menu_order"`
-	ComponentType  string `json:"component_type"`
-	ComponentValue string `json:"component_value"`
-	PageIdentifier string `json:"page_identifier"`
-	OptionID       int64  `json:"option_id"`
-	OptionName     string `json:"option_name"`
-	OptionValue    string `json:"option_value"`
-	OptionRequired bool   `json:"option_required"`
+	ID                 int64          `json:"id"`
+	AuthorID           int64          `json:"author_id"`
+	PageAuthor         string         `json:"page_author"`
+	Title              string         `json:"title"`
+	Url                string         `json:"url"`
+	MenuOrder          int64          `json:"menu_order"`
+	PageIdentifier     string         `json:"page_identifier"`
+	ParentID           sql.NullInt64  `json:"parent_id"`
+	SiteID             int64          `json:"site_id"`
+	Locale             sql.NullString `json:"locale"`
+	TranslationGroupID sql.NullInt64  `json:"translation_group_id"`
+}
+
+type PageComponent struct {
+	ID        int64           `json:"id"`
+	PageID    int64           `json:"page_id"`
+	Position  int32           `json:"position"`
+	Type      string          `json:"type"`
+	Value     json.RawMessage `json:"value"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type PageOption struct {
+	ID       int64           `json:"id"`
+	PageID   int64           `json:"page_id"`
+	Position int32           `json:"position"`
+	Name     string          `json:"name"`
+	Type     OptionType      `json:"type"`
+	Value    json.RawMessage `json:"value"`
+	Required bool            `json:"required"`
 }
 
 type Post struct {
-	ID           int64     `json:"id"`
-	Title        string    `json:"title"`
-	Content      string    `json:"content"`
-	AuthorID     int64     `json:"author_id"`
-	Url          string    `json:"url"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	Status       string    `json:"status"`
-	PublishedAt  time.Time `json:"published_at"`
-	EditedAt     time.Time `json:"edited_at"`
-	PostAuthor   string    `json:"post_author"`
-	PostMimeType string    `json:"post_mime_type"`
-	PublishedBy  string    `json:"published_by"`
-	UpdatedBy    string    `json:"updated_by"`
+	ID                 int64          `json:"id"`
+	Title              string         `json:"title"`
+	Content            string         `json:"content"`
+	AuthorID           int64          `json:"author_id"`
+	Url                string         `json:"url"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	Status             PostStatus     `json:"status"`
+	PublishedAt        time.Time      `json:"published_at"`
+	EditedAt           time.Time      `json:"edited_at"`
+	PostAuthor         string         `json:"post_author"`
+	PostMimeType       string         `json:"post_mime_type"`
+	PublishedBy        string         `json:"published_by"`
+	UpdatedBy          string         `json:"updated_by"`
+	SiteID             int64          `json:"site_id"`
+	Locale             sql.NullString `json:"locale"`
+	TranslationGroupID sql.NullInt64  `json:"translation_group_id"`
+	FeaturedMediaID    sql.NullInt64  `json:"featured_media_id"`
+}
+
+type Site struct {
+	ID        int64     `json:"id"`
+	Domain    string    `json:"domain"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SiteMember struct {
+	SiteID    int64     `json:"site_id"`
+	UserID    int64     `json:"user_id"`
+	Role      SiteRole  `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SiteSetting struct {
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	SiteID    int64           `json:"site_id"`
+}
+
+type TranslationGroup struct {
+	ID        int64     `json:"id"`
+	SiteID    int64     `json:"site_id"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type User struct {
@@ -0,0 +1,124 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: page_components.sql
+
+package frog_blossom_db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const createPageComponent = `-- name: CreatePageComponent :one
+INSERT INTO page_components (
+  page_id,
+  position,
+  type,
+  value
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, page_id, position, type, value, created_at, updated_at
+`
+
+type CreatePageComponentParams struct {
+	PageID   int64           `json:"page_id"`
+	Position int32           `json:"position"`
+	Type     string          `json:"type"`
+	Value    json.RawMessage `json:"value"`
+}
+
+func (q *Queries) CreatePageComponent(ctx context.Context, arg CreatePageComponentParams) (PageComponent, error) {
+	row := q.db.QueryRowContext(ctx, createPageComponent,
+		arg.PageID,
+		arg.Position,
+		arg.Type,
+		arg.Value,
+	)
+	var i PageComponent
+	err := row.Scan(
+		&i.ID,
+		&i.PageID,
+		&i.Position,
+		&i.Type,
+		&i.Value,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deletePageComponent = `-- name: DeletePageComponent :exec
+DELETE FROM page_components
+WHERE id = $1
+`
+
+func (q *Queries) DeletePageComponent(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePageComponent, id)
+	return err
+}
+
+const listPageComponents = `-- name: ListPageComponents :many
+SELECT id, page_id, position, type, value, created_at, updated_at FROM page_components
+WHERE page_id = $1
+ORDER BY position
+`
+
+func (q *Queries) ListPageComponents(ctx context.Context, pageID int64) ([]PageComponent, error) {
+	rows, err := q.db.QueryContext(ctx, listPageComponents, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PageComponent
+	for rows.Next() {
+		var i PageComponent
+		if err := rows.Scan(
+			&i.ID,
+			&i.PageID,
+			&i.Position,
+			&i.Type,
+			&i.Value,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePageComponentPosition = `-- name: UpdatePageComponentPosition :one
+UPDATE page_components
+  SET position = $2,
+  updated_at = now()
+WHERE id = $1
+RETURNING id, page_id, position, type, value, created_at, updated_at
+`
+
+type UpdatePageComponentPositionParams struct {
+	ID       int64 `json:"id"`
+	Position int32 `json:"position"`
+}
+
+func (q *Queries) UpdatePageComponentPosition(ctx context.Context, arg UpdatePageComponentPositionParams) (PageComponent, error) {
+	row := q.db.QueryRowContext(ctx, updatePageComponentPosition, arg.ID, arg.Position)
+	var i PageComponent
+	err := row.Scan(
+		&i.ID,
+		&i.PageID,
+		&i.Position,
+		&i.Type,
+		&i.Value,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,103 @@
+package frog_blossom_db_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+// componentIDs checks components are numbered from 0 and returns their ids
+func componentIDs(t *testing.T, components []db.PageComponent) []int64 {
+	t.Helper()
+
+	ids := make([]int64, 0, len(components))
+	for i, component := range components {
+		require.Equal(t, int32(i), component.Position)
+		ids = append(ids, component.ID)
+	}
+	return ids
+}
+
+func TestPageComponentsTx(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewStore(testDB)
+	page := fixtures.For(t, testQueries).Page()
+
+	add := func(position *int32) db.PageComponent {
+		result, err := store.AddPageComponentTx(ctx, db.AddPageComponentTxParams{
+			SiteID:   page.SiteID,
+			PageID:   page.ID,
+			Type:     "hero",
+			Value:    json.RawMessage(`{"title": "Welcome"}`),
+			Position: position,
+		})
+		require.NoError(t, err)
+		require.Equal(t, page.ID, result.Component.PageID)
+		return result.Component
+	}
+	first := add(nil)
+	second := add(nil)
+	zero := int32(0)
+	front := add(&zero)
+
+	components, err := store.ListPageComponents(ctx, page.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{front.ID, first.ID, second.ID}, componentIDs(t, components))
+
+	moved, err := store.MovePageComponentTx(ctx, db.MovePageComponentTxParams{
+		SiteID:      page.SiteID,
+		PageID:      page.ID,
+		ComponentID: front.ID,
+		Position:    10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), moved.Component.Position)
+	require.Equal(t, []int64{first.ID, second.ID, front.ID}, componentIDs(t, moved.Components))
+
+	removed, err := store.RemovePageComponentTx(ctx, db.RemovePageComponentTxParams{
+		SiteID:      page.SiteID,
+		PageID:      page.ID,
+		ComponentID: first.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, first.ID, removed.Component.ID)
+	require.Equal(t, []int64{second.ID, front.ID}, componentIDs(t, removed.Components))
+
+	components, err = store.ListPageComponents(ctx, page.ID)
+	require.NoError(t, err)
+	require.Equal(t, removed.Components, components)
+}
+
+func TestPageComponentsTxNotFound(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewStore(testDB)
+	f := fixtures.For(t, testQueries)
+	page := f.Page()
+	other := f.Page(func(p *db.CreatePagesParams) { p.SiteID = page.SiteID })
+
+	result, err := store.AddPageComponentTx(ctx, db.AddPageComponentTxParams{
+		SiteID: other.SiteID,
+		PageID: other.ID,
+		Type:   "rich-text",
+		Value:  json.RawMessage(`{"format": "html", "body": ""}`),
+	})
+	require.NoError(t, err)
+
+	_, err = store.MovePageComponentTx(ctx, db.MovePageComponentTxParams{SiteID: page.SiteID, PageID: page.ID, ComponentID: result.Component.ID})
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+
+	_, err = store.RemovePageComponentTx(ctx, db.RemovePageComponentTxParams{SiteID: page.SiteID, PageID: page.ID, ComponentID: result.Component.ID})
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+
+	_, err = store.AddPageComponentTx(ctx, db.AddPageComponentTxParams{SiteID: page.SiteID, PageID: -1, Type: "hero", Value: json.RawMessage(`{}`)})
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+
+	otherSite := f.Site()
+	_, err = store.AddPageComponentTx(ctx, db.AddPageComponentTxParams{SiteID: otherSite.ID, PageID: page.ID, Type: "hero", Value: json.RawMessage(`{}`)})
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+}
@@ -0,0 +1,102 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: page_options.sql
+
+package frog_blossom_db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const createPageOption = `-- name: CreatePageOption :one
+INSERT INTO page_options (
+  page_id,
+  position,
+  name,
+  type,
+  value,
+  required
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, page_id, position, name, type, value, required
+`
+
+type CreatePageOptionParams struct {
+	PageID   int64           `json:"page_id"`
+	Position int32           `json:"position"`
+	Name     string          `json:"name"`
+	Type     OptionType      `json:"type"`
+	Value    json.RawMessage `json:"value"`
+	Required bool            `json:"required"`
+}
+
+func (q *Queries) CreatePageOption(ctx context.Context, arg CreatePageOptionParams) (PageOption, error) {
+	row := q.db.QueryRowContext(ctx, createPageOption,
+		arg.PageID,
+		arg.Position,
+		arg.Name,
+		arg.Type,
+		arg.Value,
+		arg.Required,
+	)
+	var i PageOption
+	err := row.Scan(
+		&i.ID,
+		&i.PageID,
+		&i.Position,
+		&i.Name,
+		&i.Type,
+		&i.Value,
+		&i.Required,
+	)
+	return i, err
+}
+
+const deletePageOptions = `-- name: DeletePageOptions :exec
+DELETE FROM page_options
+WHERE page_id = $1
+`
+
+func (q *Queries) DeletePageOptions(ctx context.Context, pageID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePageOptions, pageID)
+	return err
+}
+
+const listPageOptions = `-- name: ListPageOptions :many
+SELECT id, page_id, position, name, type, value, required FROM page_options
+WHERE page_id = $1
+ORDER BY position
+`
+
+func (q *Queries) ListPageOptions(ctx context.Context, pageID int64) ([]PageOption, error) {
+	rows, err := q.db.QueryContext(ctx, listPageOptions, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PageOption
+	for rows.Next() {
+		var i PageOption
+		if err := rows.Scan(
+			&i.ID,
+			&i.PageID,
+			&i.Position,
+			&i.Name,
+			&i.Type,
+			&i.Value,
+			&i.Required,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
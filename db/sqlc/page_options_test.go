@@ -0,0 +1,167 @@
+package frog_blossom_db_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+func samplePageOptions() []db.PageOptionParams {
+	return []db.PageOptionParams{
+		{Name: "site_title", Type: db.OptionTypeString, Value: json.RawMessage(`"My Website"`), Required: true},
+		{Name: "posts_per_page", Type: db.OptionTypeNumber, Value: json.RawMessage(`10`)},
+		{Name: "show_sidebar", Type: db.OptionTypeBoolean, Value: json.RawMessage(`false`)},
+		{Name: "theme", Type: db.OptionTypeJson, Value: json.RawMessage(`{"color":"green"}`)},
+	}
+}
+
+func requireOptions(t *testing.T, expected []db.PageOptionParams, pageID int64, actual []db.PageOption) {
+	t.Helper()
+
+	require.Len(t, actual, len(expected))
+	for i, option := range actual {
+		require.Equal(t, pageID, option.PageID)
+		require.Equal(t, int32(i), option.Position)
+		require.Equal(t, expected[i].Name, option.Name)
+		require.Equal(t, expected[i].Type, option.Type)
+		require.JSONEq(t, string(expected[i].Value), string(option.Value))
+		require.Equal(t, expected[i].Required, option.Required)
+	}
+}
+
+func createPageWithOptions(t *testing.T, store db.Store, options []db.PageOptionParams) db.CreateContentTxResult {
+	t.Helper()
+
+	page := fixtures.For(t, testQueries).PageParams()
+	result, err := store.CreatePageTx(context.Background(), db.CreateContentTxParams{
+		SiteID:      page.SiteID,
+		UserId:      page.AuthorID,
+		Username:    page.PageAuthor,
+		Pages:       []db.CreatePagesParams{page},
+		PageOptions: [][]db.PageOptionParams{options},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Pages, 1)
+	require.Len(t, result.PageOptions, 1)
+	return result
+}
+
+func TestCreatePageTxWithOptions(t *testing.T) {
+	// Arrange
+	store := db.NewStore(testDB)
+	options := samplePageOptions()
+
+	// Act
+	result := createPageWithOptions(t, store, options)
+
+	// Assert
+	pageID := result.Pages[0].ID
+	requireOptions(t, options, pageID, result.PageOptions[0])
+
+	stored, err := store.ListPageOptions(context.Background(), pageID)
+	require.NoError(t, err)
+	requireOptions(t, options, pageID, stored)
+}
+
+func TestUpdatePageTxReplacesOptions(t *testing.T) {
+	// Arrange
+	store := db.NewStore(testDB)
+	created := createPageWithOptions(t, store, samplePageOptions())
+	page := created.Pages[0]
+
+	update := func(options [][]db.PageOptionParams) db.UpdateContentTxResult {
+		result, err := store.UpdatePageTx(context.Background(), db.UpdateContentTxParams{
+			SiteID:   page.SiteID,
+			UserId:   page.AuthorID,
+			Username: page.PageAuthor,
+			PageId:   &page.ID,
+			Pages: []db.UpdatePagesParams{{
+				ID:             page.ID,
+				AuthorID:       page.AuthorID,
+				PageAuthor:     page.PageAuthor,
+				Title:          "Updated",
+				Url:            page.Url,
+				MenuOrder:      page.MenuOrder,
+				PageIdentifier: page.PageIdentifier,
+			}},
+			PageOptions: options,
+		})
+		require.NoError(t, err)
+		return result
+	}
+	replacement := []db.PageOptionParams{
+		{Name: "posts_per_page", Type: db.OptionTypeNumber, Value: json.RawMessage(`25`), Required: true},
+		{Name: "site_title", Type: db.OptionTypeString, Value: json.RawMessage(`"Renamed"`)},
+	}
+
+	// Act
+	replaced := update([][]db.PageOptionParams{replacement})
+	kept := update(nil)
+
+	// Assert
+	requireOptions(t, replacement, page.ID, replaced.PageOptions[0])
+	requireOptions(t, replacement, page.ID, kept.PageOptions[0])
+
+	cleared := update([][]db.PageOptionParams{{}})
+	require.Empty(t, cleared.PageOptions[0])
+	stored, err := store.ListPageOptions(context.Background(), page.ID)
+	require.NoError(t, err)
+	require.Empty(t, stored)
+}
+
+func TestCreatePageTxRejectsBadOptions(t *testing.T) {
+	store := db.NewStore(testDB)
+	page := fixtures.For(t, testQueries).PageParams()
+
+	testCases := []struct {
+		name     string
+		options  []db.PageOptionParams
+		expected error
+	}{
+		{
+			name: "ValueDoesNotMatchType",
+			options: []db.PageOptionParams{
+				{Name: "posts_per_page", Type: db.OptionTypeNumber, Value: json.RawMessage(`"ten"`)},
+			},
+			expected: apperr.ErrValidation,
+		},
+		{
+			name: "DuplicateName",
+			options: []db.PageOptionParams{
+				{Name: "theme", Type: db.OptionTypeString, Value: json.RawMessage(`"light"`)},
+				{Name: "theme", Type: db.OptionTypeString, Value: json.RawMessage(`"dark"`)},
+			},
+			expected: apperr.ErrConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := store.CreatePageTx(context.Background(), db.CreateContentTxParams{
+				SiteID:      page.SiteID,
+				UserId:      page.AuthorID,
+				Pages:       []db.CreatePagesParams{page},
+				PageOptions: [][]db.PageOptionParams{tc.options},
+			})
+			require.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestDeletePagesCascadesToOptions(t *testing.T) {
+	store := db.NewStore(testDB)
+	created := createPageWithOptions(t, store, samplePageOptions())
+	page := created.Pages[0]
+	pageID := page.ID
+
+	require.NoError(t, store.DeletePages(context.Background(), db.DeletePagesParams{SiteID: page.SiteID, ID: pageID}))
+
+	options, err := store.ListPageOptions(context.Background(), pageID)
+	require.NoError(t, err)
+	require.Empty(t, options)
+}
@@ -0,0 +1,157 @@
+package frog_blossom_db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+// createPageTree creates root > [a > [a1], b] on one site and returns
+// the pages by name
+func createPageTree(t *testing.T) map[string]db.Page {
+	t.Helper()
+
+	f := fixtures.For(t, testQueries)
+	root := f.Page()
+	child := func(parent db.Page, menuOrder int64) db.Page {
+		return f.Page(func(args *db.CreatePagesParams) {
+			args.SiteID = root.SiteID
+			args.AuthorID = root.AuthorID
+			args.PageAuthor = root.PageAuthor
+			args.MenuOrder = menuOrder
+			args.ParentID = sql.NullInt64{Int64: parent.ID, Valid: true}
+		})
+	}
+	a := child(root, 0)
+	b := child(root, 1)
+	a1 := child(a, 0)
+	return map[string]db.Page{"root": root, "a": a, "b": b, "a1": a1}
+}
+
+func pageIDs(pages []db.Page) []int64 {
+	ids := make([]int64, 0, len(pages))
+	for _, page := range pages {
+		ids = append(ids, page.ID)
+	}
+	return ids
+}
+
+func TestGetPageSubtree(t *testing.T) {
+	tree := createPageTree(t)
+
+	root := tree["root"]
+	pages, err := testQueries.GetPageSubtree(context.Background(), db.GetPageSubtreeParams{SiteID: root.SiteID, ID: tree["a"].ID})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{tree["a"].ID, tree["a1"].ID}, pageIDs(pages))
+
+	pages, err = testQueries.GetPageSubtree(context.Background(), db.GetPageSubtreeParams{SiteID: root.SiteID, ID: root.ID})
+	require.NoError(t, err)
+	require.Len(t, pages, 4)
+
+	other := fixtures.For(t, testQueries).Site()
+	pages, err = testQueries.GetPageSubtree(context.Background(), db.GetPageSubtreeParams{SiteID: other.ID, ID: root.ID})
+	require.NoError(t, err)
+	require.Empty(t, pages)
+
+	pages, err = testQueries.ListSitePages(context.Background(), root.SiteID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{tree["root"].ID, tree["a"].ID, tree["b"].ID, tree["a1"].ID}, pageIDs(pages))
+}
+
+func TestMovePageTx(t *testing.T) {
+	store := db.NewStore(testDB)
+	tree := createPageTree(t)
+	move := func(page db.Page, parent *db.Page) (db.MovePageTxResult, error) {
+		args := db.MovePageTxParams{SiteID: tree["root"].SiteID, PageID: page.ID, MenuOrder: 5}
+		if parent != nil {
+			args.ParentID = &parent.ID
+		}
+		return store.MovePageTx(context.Background(), args)
+	}
+
+	t.Run("UnderSibling", func(t *testing.T) {
+		b := tree["b"]
+		result, err := move(tree["a1"], &b)
+		require.NoError(t, err)
+		require.Equal(t, sql.NullInt64{Int64: b.ID, Valid: true}, result.Page.ParentID)
+		require.Equal(t, int64(5), result.Page.MenuOrder)
+	})
+
+	t.Run("ToTopLevel", func(t *testing.T) {
+		result, err := move(tree["b"], nil)
+		require.NoError(t, err)
+		require.False(t, result.Page.ParentID.Valid)
+
+		b := tree["b"]
+		_, err = move(tree["b"], &b)
+		require.ErrorIs(t, err, apperr.ErrValidation)
+	})
+
+	t.Run("UnderDescendant", func(t *testing.T) {
+		a1 := tree["a1"]
+		_, err := move(tree["root"], &a1)
+		require.ErrorIs(t, err, apperr.ErrValidation)
+	})
+
+	t.Run("OtherSite", func(t *testing.T) {
+		other := fixtures.For(t, testQueries).Page()
+		_, err := move(tree["a"], &other)
+		require.ErrorIs(t, err, apperr.ErrValidation)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := move(db.Page{ID: -1}, nil)
+		require.ErrorIs(t, err, apperr.ErrNotFound)
+	})
+}
+
+func TestReorderPagesTx(t *testing.T) {
+	store := db.NewStore(testDB)
+	tree := createPageTree(t)
+	root := tree["root"]
+	reorder := func(ids ...int64) (db.ReorderPagesTxResult, error) {
+		return store.ReorderPagesTx(context.Background(), db.ReorderPagesTxParams{
+			SiteID:   root.SiteID,
+			ParentID: &root.ID,
+			PageIDs:  ids,
+		})
+	}
+
+	result, err := reorder(tree["b"].ID, tree["a"].ID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{tree["b"].ID, tree["a"].ID}, pageIDs(result.Pages))
+
+	children, err := testQueries.ListPageSiblings(context.Background(), db.ListPageSiblingsParams{
+		SiteID:   root.SiteID,
+		ParentID: sql.NullInt64{Int64: root.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{tree["b"].ID, tree["a"].ID}, pageIDs(children))
+	require.Equal(t, []int64{0, 1}, []int64{children[0].MenuOrder, children[1].MenuOrder})
+
+	for _, ids := range [][]int64{
+		{tree["a"].ID},
+		{tree["a"].ID, tree["a"].ID},
+		{tree["a"].ID, tree["a1"].ID},
+	} {
+		_, err := reorder(ids...)
+		require.ErrorIs(t, err, apperr.ErrValidation)
+	}
+}
+
+func TestDeletePageTxWithChildren(t *testing.T) {
+	store := db.NewStore(testDB)
+	tree := createPageTree(t)
+
+	siteID, a, a1 := tree["root"].SiteID, tree["a"].ID, tree["a1"].ID
+	_, err := store.DeletePageTx(context.Background(), db.DeleteContentTxParams{SiteID: siteID, PageId: &a})
+	require.ErrorIs(t, err, apperr.ErrConflict)
+
+	_, err = store.DeletePageTx(context.Background(), db.DeleteContentTxParams{SiteID: siteID, PageId: &a1})
+	require.NoError(t, err)
+}
@@ -7,131 +7,311 @@ package frog_blossom_db
 
 import (
 	"context"
+	"database/sql"
 )
 
+const countPageChildren = `-- name: CountPageChildren :one
+SELECT count(*) FROM pages
+WHERE site_id = $1 AND parent_id = $2
+`
+
+type CountPageChildrenParams struct {
+	SiteID   int64         `json:"site_id"`
+	ParentID sql.NullInt64 `json:"parent_id"`
+}
+
+func (q *Queries) CountPageChildren(ctx context.Context, arg CountPageChildrenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPageChildren, arg.SiteID, arg.ParentID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPages = `-- name: CreatePages :one
 INSERT INTO pages (
-  domain,
+  site_id,
   author_id,
   page_author,
   title,
   url,
   menu_order,
-  component_type,
-  component_value,
   page_identifier,
-  option_id,
-  option_name,
-  option_value,
-  option_required
+  parent_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
-) RETURNING id, domain, author_id, page_author, title, url, menu_order, component_type, component_value, page_identifier, option_id, option_name, option_value, option_required
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id
 `
 
 type CreatePagesParams struct {
-	Domain         string `json:"domain"`
-	AuthorID       int64  `json:"author_id"`
-	PageAuthor     string `json:"page_author"`
-	Title          string `json:"title"`
-	Url            string `json:"url"`
-	MenuOrder      int64  `json:"menu_order"`
-	ComponentType  string `json:"component_type"`
-	ComponentValue string `json:"component_value"`
-	PageIdentifier string `json:"page_identifier"`
-	OptionID       int64  `json:"option_id"`
-	OptionName     string `json:"option_name"`
-	OptionValue    string `json:"option_value"`
-	OptionRequired bool   `json:"option_required"`
+	SiteID         int64         `json:"site_id"`
+	AuthorID       int64         `json:"author_id"`
+	PageAuthor     string        `json:"page_author"`
+	Title          string        `json:"title"`
+	Url            string        `json:"url"`
+	MenuOrder      int64         `json:"menu_order"`
+	PageIdentifier string        `json:"page_identifier"`
+	ParentID       sql.NullInt64 `json:"parent_id"`
 }
 
 func (q *Queries) CreatePages(ctx context.Context, arg CreatePagesParams) (Page, error) {
 	row := q.db.QueryRowContext(ctx, createPages,
-		arg.Domain,
+		arg.SiteID,
 		arg.AuthorID,
 		arg.PageAuthor,
 		arg.Title,
 		arg.Url,
 		arg.MenuOrder,
-		arg.ComponentType,
-		arg.ComponentValue,
 		arg.PageIdentifier,
-		arg.OptionID,
-		arg.OptionName,
-		arg.OptionValue,
-		arg.OptionRequired,
+		arg.ParentID,
 	)
 	var i Page
 	err := row.Scan(
 		&i.ID,
-		&i.Domain,
 		&i.AuthorID,
 		&i.PageAuthor,
 		&i.Title,
 		&i.Url,
 		&i.MenuOrder,
-		&i.ComponentType,
-		&i.ComponentValue,
 		&i.PageIdentifier,
-		&i.OptionID,
-		&i.OptionName,
-		&i.OptionValue,
-		&i.OptionRequired,
+		&i.ParentID,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
 	)
 	return i, err
 }
 
 const deletePages = `-- name: DeletePages :exec
 DELETE FROM pages
-WHERE id = $1
+WHERE site_id = $1 AND id = $2
 `
 
-func (q *Queries) DeletePages(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deletePages, id)
+type DeletePagesParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) DeletePages(ctx context.Context, arg DeletePagesParams) error {
+	_, err := q.db.ExecContext(ctx, deletePages, arg.SiteID, arg.ID)
 	return err
 }
 
+const getPageSubtree = `-- name: GetPageSubtree :many
+WITH RECURSIVE subtree AS (
+  SELECT pages.id FROM pages
+  WHERE pages.site_id = $1 AND pages.id = $2
+  UNION
+  SELECT child.id FROM pages child
+  JOIN subtree ON child.parent_id = subtree.id
+)
+SELECT pages.id, pages.author_id, pages.page_author, pages.title, pages.url, pages.menu_order, pages.page_identifier, pages.parent_id, pages.site_id, pages.locale, pages.translation_group_id FROM pages
+JOIN subtree ON pages.id = subtree.id
+ORDER BY pages.menu_order, pages.id
+`
+
+type GetPageSubtreeParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetPageSubtree(ctx context.Context, arg GetPageSubtreeParams) ([]Page, error) {
+	rows, err := q.db.QueryContext(ctx, getPageSubtree, arg.SiteID, arg.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Page
+	for rows.Next() {
+		var i Page
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.PageAuthor,
+			&i.Title,
+			&i.Url,
+			&i.MenuOrder,
+			&i.PageIdentifier,
+			&i.ParentID,
+			&i.SiteID,
+			&i.Locale,
+			&i.TranslationGroupID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPages = `-- name: GetPages :one
-SELECT id, domain, author_id, page_author, title, url, menu_order, component_type, component_value, page_identifier, option_id, option_name, option_value, option_required FROM pages
-WHERE id = $1 LIMIT 1
+SELECT id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id FROM pages
+WHERE site_id = $1 AND id = $2 LIMIT 1
 `
 
-func (q *Queries) GetPages(ctx context.Context, id int64) (Page, error) {
-	row := q.db.QueryRowContext(ctx, getPages, id)
+type GetPagesParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetPages(ctx context.Context, arg GetPagesParams) (Page, error) {
+	row := q.db.QueryRowContext(ctx, getPages, arg.SiteID, arg.ID)
 	var i Page
 	err := row.Scan(
 		&i.ID,
-		&i.Domain,
 		&i.AuthorID,
 		&i.PageAuthor,
 		&i.Title,
 		&i.Url,
 		&i.MenuOrder,
-		&i.ComponentType,
-		&i.ComponentValue,
 		&i.PageIdentifier,
-		&i.OptionID,
-		&i.OptionName,
-		&i.OptionValue,
-		&i.OptionRequired,
+		&i.ParentID,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
 	)
 	return i, err
 }
 
+const getPagesForUpdate = `-- name: GetPagesForUpdate :one
+SELECT id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id FROM pages
+WHERE site_id = $1 AND id = $2 LIMIT 1
+FOR UPDATE
+`
+
+type GetPagesForUpdateParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetPagesForUpdate(ctx context.Context, arg GetPagesForUpdateParams) (Page, error) {
+	row := q.db.QueryRowContext(ctx, getPagesForUpdate, arg.SiteID, arg.ID)
+	var i Page
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.PageAuthor,
+		&i.Title,
+		&i.Url,
+		&i.MenuOrder,
+		&i.PageIdentifier,
+		&i.ParentID,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+	)
+	return i, err
+}
+
+const listPageSiblings = `-- name: ListPageSiblings :many
+SELECT id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id FROM pages
+WHERE site_id = $1 AND parent_id IS NOT DISTINCT FROM $2
+ORDER BY menu_order, id
+FOR UPDATE
+`
+
+type ListPageSiblingsParams struct {
+	SiteID   int64         `json:"site_id"`
+	ParentID sql.NullInt64 `json:"parent_id"`
+}
+
+func (q *Queries) ListPageSiblings(ctx context.Context, arg ListPageSiblingsParams) ([]Page, error) {
+	rows, err := q.db.QueryContext(ctx, listPageSiblings, arg.SiteID, arg.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Page
+	for rows.Next() {
+		var i Page
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.PageAuthor,
+			&i.Title,
+			&i.Url,
+			&i.MenuOrder,
+			&i.PageIdentifier,
+			&i.ParentID,
+			&i.SiteID,
+			&i.Locale,
+			&i.TranslationGroupID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPages = `-- name: ListPages :many
-SELECT id, domain, author_id, page_author, title, url, menu_order, component_type, component_value, page_identifier, option_id, option_name, option_value, option_required FROM pages
+SELECT id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id FROM pages
+WHERE site_id = $1
 ORDER BY id
-LIMIT $1
-OFFSET $2
+LIMIT $2
+OFFSET $3
 `
 
 type ListPagesParams struct {
+	SiteID int64 `json:"site_id"`
 	Limit  int32 `json:"limit"`
 	Offset int32 `json:"offset"`
 }
 
 func (q *Queries) ListPages(ctx context.Context, arg ListPagesParams) ([]Page, error) {
-	rows, err := q.db.QueryContext(ctx, listPages, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listPages, arg.SiteID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Page
+	for rows.Next() {
+		var i Page
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.PageAuthor,
+			&i.Title,
+			&i.Url,
+			&i.MenuOrder,
+			&i.PageIdentifier,
+			&i.ParentID,
+			&i.SiteID,
+			&i.Locale,
+			&i.TranslationGroupID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSitePages = `-- name: ListSitePages :many
+SELECT id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id FROM pages
+WHERE site_id = $1
+ORDER BY parent_id NULLS FIRST, menu_order, id
+`
+
+func (q *Queries) ListSitePages(ctx context.Context, siteID int64) ([]Page, error) {
+	rows, err := q.db.QueryContext(ctx, listSitePages, siteID)
 	if err != nil {
 		return nil, err
 	}
@@ -141,19 +321,16 @@ func (q *Queries) ListPages(ctx context.Context, arg ListPagesParams) ([]Page, e
 		var i Page
 		if err := rows.Scan(
 			&i.ID,
-			&i.Domain,
 			&i.AuthorID,
 			&i.PageAuthor,
 			&i.Title,
 			&i.Url,
 			&i.MenuOrder,
-			&i.ComponentType,
-			&i.ComponentValue,
 			&i.PageIdentifier,
-			&i.OptionID,
-			&i.OptionName,
-			&i.OptionValue,
-			&i.OptionRequired,
+			&i.ParentID,
+			&i.SiteID,
+			&i.Locale,
+			&i.TranslationGroupID,
 		); err != nil {
 			return nil, err
 		}
@@ -168,75 +345,119 @@ func (q *Queries) ListPages(ctx context.Context, arg ListPagesParams) ([]Page, e
 	return items, nil
 }
 
+const lockPageTree = `-- name: LockPageTree :exec
+SELECT pg_advisory_xact_lock(hashtext('pages:' || $1::bigint))
+`
+
+// Serializes moves within a site so two concurrent moves can't form a cycle
+func (q *Queries) LockPageTree(ctx context.Context, siteID int64) error {
+	_, err := q.db.ExecContext(ctx, lockPageTree, siteID)
+	return err
+}
+
+const movePage = `-- name: MovePage :one
+UPDATE pages
+  SET parent_id = $1,
+  menu_order = $2
+WHERE site_id = $3 AND id = $4
+RETURNING id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id
+`
+
+type MovePageParams struct {
+	ParentID  sql.NullInt64 `json:"parent_id"`
+	MenuOrder int64         `json:"menu_order"`
+	SiteID    int64         `json:"site_id"`
+	ID        int64         `json:"id"`
+}
+
+func (q *Queries) MovePage(ctx context.Context, arg MovePageParams) (Page, error) {
+	row := q.db.QueryRowContext(ctx, movePage,
+		arg.ParentID,
+		arg.MenuOrder,
+		arg.SiteID,
+		arg.ID,
+	)
+	var i Page
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.PageAuthor,
+		&i.Title,
+		&i.Url,
+		&i.MenuOrder,
+		&i.PageIdentifier,
+		&i.ParentID,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+	)
+	return i, err
+}
+
+const updatePageMenuOrder = `-- name: UpdatePageMenuOrder :exec
+UPDATE pages
+  SET menu_order = $3
+WHERE site_id = $1 AND id = $2
+`
+
+type UpdatePageMenuOrderParams struct {
+	SiteID    int64 `json:"site_id"`
+	ID        int64 `json:"id"`
+	MenuOrder int64 `json:"menu_order"`
+}
+
+func (q *Queries) UpdatePageMenuOrder(ctx context.Context, arg UpdatePageMenuOrderParams) error {
+	_, err := q.db.ExecContext(ctx, updatePageMenuOrder, arg.SiteID, arg.ID, arg.MenuOrder)
+	return err
+}
+
 const updatePages = `-- name: UpdatePages :one
 UPDATE pages
-  SET domain = $2,
-  author_id = $3,
+  SET author_id = $3,
   page_author = $4,
   title = $5,
   url = $6,
   menu_order = $7,
-  component_type = $8,
-  component_value = $9,
-  page_identifier = $10,
-  option_id = $11,
-  option_name = $12,
-  option_value = $13,
-  option_required = $14
-WHERE id = $1
-RETURNING id, domain, author_id, page_author, title, url, menu_order, component_type, component_value, page_identifier, option_id, option_name, option_value, option_required
+  page_identifier = $8
+WHERE site_id = $1 AND id = $2
+RETURNING id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id
 `
 
 type UpdatePagesParams struct {
+	SiteID         int64  `json:"site_id"`
 	ID             int64  `json:"id"`
-	Domain         string `json:"domain"`
 	AuthorID       int64  `json:"author_id"`
 	PageAuthor     string `json:"page_author"`
 	Title          string `json:"title"`
 	Url            string `json:"url"`
 	MenuOrder      int64  `json:"menu_order"`
-	ComponentType  string `json:"component_type"`
-	ComponentValue string `json:"component_value"`
 	PageIdentifier string `json:"page_identifier"`
-	OptionID       int64  `json:"option_id"`
-	OptionName     string `json:"option_name"`
-	OptionValue    string `json:"option_value"`
-	OptionRequired bool   `json:"option_required"`
 }
 
 func (q *Queries) UpdatePages(ctx context.Context, arg UpdatePagesParams) (Page, error) {
 	row := q.db.QueryRowContext(ctx, updatePages,
+		arg.SiteID,
 		arg.ID,
-		arg.Domain,
 		arg.AuthorID,
 		arg.PageAuthor,
 		arg.Title,
 		arg.Url,
 		arg.MenuOrder,
-		arg.ComponentType,
-		arg.ComponentValue,
 		arg.PageIdentifier,
-		arg.OptionID,
-		arg.OptionName,
-		arg.OptionValue,
-		arg.OptionRequired,
 	)
 	var i Page
 	err := row.Scan(
 		&i.ID,
-		&i.Domain,
 		&i.AuthorID,
 		&i.PageAuthor,
 		&i.Title,
 		&i.Url,
 		&i.MenuOrder,
-		&i.ComponentType,
-		&i.ComponentValue,
 		&i.PageIdentifier,
-		&i.OptionID,
-		&i.OptionName,
-		&i.OptionValue,
-		&i.OptionRequired,
+		&i.ParentID,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
 	)
 	return i, err
 }
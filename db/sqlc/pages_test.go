@@ -1,32 +1,18 @@
-package frog_blossom_db
+package frog_blossom_db_test
 
 import (
 	"context"
 	"database/sql"
 	"testing"
 
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
 	"github.com/stretchr/testify/require"
 )
 
-func createRandomPage(t *testing.T) Page {
+func createRandomPage(t *testing.T) db.Page {
 	// Arrange
-	randomUser := createRandomUser(t)
-
-	args := CreatePagesParams{
-		Domain:         "example.com",
-		AuthorID:       randomUser.ID,
-		PageAuthor:     randomUser.Username,
-		Title:          "Homepage",
-		Url:            "/home",
-		MenuOrder:      1,
-		ComponentType:  "Text",
-		ComponentValue: "Welcome to our website!",
-		PageIdentifier: "home",
-		OptionID:       98765,
-		OptionName:     "site_title",
-		OptionValue:    "My Website",
-		OptionRequired: true,
-	}
+	args := fixtures.For(t, testQueries).PageParams()
 
 	// Act
 	page, err := testQueries.CreatePages(context.Background(), args)
@@ -35,19 +21,13 @@ func createRandomPage(t *testing.T) Page {
 
 	// Assert
 
-	require.Equal(t, args.Domain, page.Domain)
+	require.Equal(t, args.SiteID, page.SiteID)
 	require.Equal(t, args.AuthorID, page.AuthorID)
 	require.Equal(t, args.PageAuthor, page.PageAuthor)
 	require.Equal(t, args.Title, page.Title)
 	require.Equal(t, args.Url, page.Url)
 	require.Equal(t, args.MenuOrder, page.MenuOrder)
-	require.Equal(t, args.ComponentType, page.ComponentType)
-	require.Equal(t, args.ComponentValue, page.ComponentValue)
 	require.Equal(t, args.PageIdentifier, page.PageIdentifier)
-	require.Equal(t, args.OptionID, page.OptionID)
-	require.Equal(t, args.OptionName, page.OptionName)
-	require.Equal(t, args.OptionValue, page.OptionValue)
-	require.Equal(t, args.OptionRequired, page.OptionRequired)
 
 	return page
 }
@@ -61,25 +41,31 @@ func TestGetPages(t *testing.T) {
 	randomPage := createRandomPage(t)
 
 	// Act
-	page, err := testQueries.GetPages(context.Background(), randomPage.ID)
+	page, err := testQueries.GetPages(context.Background(), db.GetPagesParams{SiteID: randomPage.SiteID, ID: randomPage.ID})
 	require.NoError(t, err)
 	require.NotEmpty(t, page)
 
 	// Assert
 	require.Equal(t, randomPage.ID, page.ID)
-	require.Equal(t, randomPage.Domain, page.Domain)
+	require.Equal(t, randomPage.SiteID, page.SiteID)
 	require.Equal(t, randomPage.AuthorID, page.AuthorID)
 	require.Equal(t, randomPage.PageAuthor, page.PageAuthor)
 	require.Equal(t, randomPage.Title, page.Title)
 	require.Equal(t, randomPage.Url, page.Url)
 	require.Equal(t, randomPage.MenuOrder, page.MenuOrder)
-	require.Equal(t, randomPage.ComponentType, page.ComponentType)
-	require.Equal(t, randomPage.ComponentValue, page.ComponentValue)
 	require.Equal(t, randomPage.PageIdentifier, page.PageIdentifier)
-	require.Equal(t, randomPage.OptionID, page.OptionID)
-	require.Equal(t, randomPage.OptionName, page.OptionName)
-	require.Equal(t, randomPage.OptionValue, page.OptionValue)
-	require.Equal(t, randomPage.OptionRequired, page.OptionRequired)
+}
+
+func TestGetPagesOfOtherSite(t *testing.T) {
+	// Arrange
+	randomPage := createRandomPage(t)
+	otherSite := fixtures.For(t, testQueries).Site()
+
+	// Act
+	_, err := testQueries.GetPages(context.Background(), db.GetPagesParams{SiteID: otherSite.ID, ID: randomPage.ID})
+
+	// Assert
+	require.ErrorIs(t, err, sql.ErrNoRows)
 }
 
 func TestUpdatePages(t *testing.T) {
@@ -91,25 +77,19 @@ func TestUpdatePages(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, user)
 
-	pages, err := testQueries.GetPages(context.Background(), randomPage.ID)
+	pages, err := testQueries.GetPages(context.Background(), db.GetPagesParams{SiteID: randomPage.SiteID, ID: randomPage.ID})
 	require.NoError(t, err)
 	require.NotEmpty(t, pages)
 
-	args := UpdatePagesParams{
+	args := db.UpdatePagesParams{
+		SiteID:         pages.SiteID,
 		ID:             pages.ID,
-		Domain:         "example.com",
 		AuthorID:       user.ID,
 		PageAuthor:     user.Username,
 		Title:          "Homepage",
 		Url:            "/home",
 		MenuOrder:      1,
-		ComponentType:  "Text",
-		ComponentValue: "Welcome to our website!",
 		PageIdentifier: "contact",
-		OptionID:       98765,
-		OptionName:     "site_title",
-		OptionValue:    "My Website",
-		OptionRequired: true,
 	}
 
 	// Act
@@ -119,30 +99,24 @@ func TestUpdatePages(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, page)
 	require.Equal(t, args.ID, page.ID)
-	require.Equal(t, args.Domain, page.Domain)
+	require.Equal(t, args.SiteID, page.SiteID)
 	require.Equal(t, args.AuthorID, page.AuthorID)
 	require.Equal(t, args.PageAuthor, page.PageAuthor)
 	require.Equal(t, args.Title, page.Title)
 	require.Equal(t, args.Url, page.Url)
 	require.Equal(t, args.MenuOrder, page.MenuOrder)
-	require.Equal(t, args.ComponentType, page.ComponentType)
-	require.Equal(t, args.ComponentValue, page.ComponentValue)
 	require.Equal(t, args.PageIdentifier, page.PageIdentifier)
-	require.Equal(t, args.OptionID, page.OptionID)
-	require.Equal(t, args.OptionName, page.OptionName)
-	require.Equal(t, args.OptionValue, page.OptionValue)
-	require.Equal(t, args.OptionRequired, page.OptionRequired)
 }
 
 func TestDeletePages(t *testing.T) {
 	// Arrange
 	randomPage := createRandomPage(t)
 
-	err := testQueries.DeletePages(context.Background(), randomPage.ID)
+	err := testQueries.DeletePages(context.Background(), db.DeletePagesParams{SiteID: randomPage.SiteID, ID: randomPage.ID})
 	require.NoError(t, err)
 
 	// Act
-	page, err := testQueries.GetPages(context.Background(), randomPage.ID)
+	page, err := testQueries.GetPages(context.Background(), db.GetPagesParams{SiteID: randomPage.SiteID, ID: randomPage.ID})
 
 	// Assert
 	require.Error(t, err)
@@ -152,12 +126,16 @@ func TestDeletePages(t *testing.T) {
 
 func TestListPages(t *testing.T) {
 	// Arrange
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
 	for i := 0; i < 10; i++ {
-		createRandomPage(t)
+		f.Page(func(p *db.CreatePagesParams) { p.SiteID = site.ID })
 	}
+	createRandomPage(t)
 
-	args := ListPagesParams{
-		Limit:  5,
+	args := db.ListPagesParams{
+		SiteID: site.ID,
+		Limit:  10,
 		Offset: 5,
 	}
 
@@ -169,6 +147,6 @@ func TestListPages(t *testing.T) {
 	require.Len(t, page, 5)
 
 	for _, page := range page {
-		require.NotEmpty(t, page)
+		require.Equal(t, site.ID, page.SiteID)
 	}
 }
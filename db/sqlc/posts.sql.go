@@ -7,11 +7,13 @@ package frog_blossom_db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
 const createPosts = `-- name: CreatePosts :one
 INSERT INTO posts (
+  site_id,
   title,
   content,
   author_id,
@@ -24,29 +26,33 @@ INSERT INTO posts (
   post_author,
   post_mime_type,
   published_by,
-  updated_by
+  updated_by,
+  featured_media_id
 ) VALUES (
-  $1, $2, $3, $4, DEFAULT, $5, $6, $7, $8, $9, $10, $11, $12
-) RETURNING id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by
+  $1, $2, $3, $4, $5, DEFAULT, $6, $7, $8, $9, $10, $11, $12, $13, $14
+) RETURNING id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by, site_id, locale, translation_group_id, featured_media_id
 `
 
 type CreatePostsParams struct {
-	Title        string    `json:"title"`
-	Content      string    `json:"content"`
-	AuthorID     int64     `json:"author_id"`
-	Url          string    `json:"url"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	Status       string    `json:"status"`
-	PublishedAt  time.Time `json:"published_at"`
-	EditedAt     time.Time `json:"edited_at"`
-	PostAuthor   string    `json:"post_author"`
-	PostMimeType string    `json:"post_mime_type"`
-	PublishedBy  string    `json:"published_by"`
-	UpdatedBy    string    `json:"updated_by"`
+	SiteID          int64         `json:"site_id"`
+	Title           string        `json:"title"`
+	Content         string        `json:"content"`
+	AuthorID        int64         `json:"author_id"`
+	Url             string        `json:"url"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	Status          PostStatus    `json:"status"`
+	PublishedAt     time.Time     `json:"published_at"`
+	EditedAt        time.Time     `json:"edited_at"`
+	PostAuthor      string        `json:"post_author"`
+	PostMimeType    string        `json:"post_mime_type"`
+	PublishedBy     string        `json:"published_by"`
+	UpdatedBy       string        `json:"updated_by"`
+	FeaturedMediaID sql.NullInt64 `json:"featured_media_id"`
 }
 
 func (q *Queries) CreatePosts(ctx context.Context, arg CreatePostsParams) (Post, error) {
 	row := q.db.QueryRowContext(ctx, createPosts,
+		arg.SiteID,
 		arg.Title,
 		arg.Content,
 		arg.AuthorID,
@@ -59,6 +65,7 @@ func (q *Queries) CreatePosts(ctx context.Context, arg CreatePostsParams) (Post,
 		arg.PostMimeType,
 		arg.PublishedBy,
 		arg.UpdatedBy,
+		arg.FeaturedMediaID,
 	)
 	var i Post
 	err := row.Scan(
@@ -76,27 +83,78 @@ func (q *Queries) CreatePosts(ctx context.Context, arg CreatePostsParams) (Post,
 		&i.PostMimeType,
 		&i.PublishedBy,
 		&i.UpdatedBy,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+		&i.FeaturedMediaID,
 	)
 	return i, err
 }
 
 const deletePosts = `-- name: DeletePosts :exec
 DELETE FROM posts
-WHERE id = $1
+WHERE site_id = $1 AND id = $2
 `
 
-func (q *Queries) DeletePosts(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deletePosts, id)
+type DeletePostsParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) DeletePosts(ctx context.Context, arg DeletePostsParams) error {
+	_, err := q.db.ExecContext(ctx, deletePosts, arg.SiteID, arg.ID)
 	return err
 }
 
 const getPosts = `-- name: GetPosts :one
-SELECT id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by FROM posts
-WHERE id = $1 LIMIT 1
+SELECT id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by, site_id, locale, translation_group_id, featured_media_id FROM posts
+WHERE site_id = $1 AND id = $2 LIMIT 1
+`
+
+type GetPostsParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetPosts(ctx context.Context, arg GetPostsParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPosts, arg.SiteID, arg.ID)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Content,
+		&i.AuthorID,
+		&i.Url,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishedAt,
+		&i.EditedAt,
+		&i.PostAuthor,
+		&i.PostMimeType,
+		&i.PublishedBy,
+		&i.UpdatedBy,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+		&i.FeaturedMediaID,
+	)
+	return i, err
+}
+
+const getPostsForUpdate = `-- name: GetPostsForUpdate :one
+SELECT id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by, site_id, locale, translation_group_id, featured_media_id FROM posts
+WHERE site_id = $1 AND id = $2 LIMIT 1
+FOR UPDATE
 `
 
-func (q *Queries) GetPosts(ctx context.Context, id int64) (Post, error) {
-	row := q.db.QueryRowContext(ctx, getPosts, id)
+type GetPostsForUpdateParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetPostsForUpdate(ctx context.Context, arg GetPostsForUpdateParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostsForUpdate, arg.SiteID, arg.ID)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -113,24 +171,30 @@ func (q *Queries) GetPosts(ctx context.Context, id int64) (Post, error) {
 		&i.PostMimeType,
 		&i.PublishedBy,
 		&i.UpdatedBy,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+		&i.FeaturedMediaID,
 	)
 	return i, err
 }
 
 const listPosts = `-- name: ListPosts :many
-SELECT id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by FROM posts
+SELECT id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by, site_id, locale, translation_group_id, featured_media_id FROM posts
+WHERE site_id = $1
 ORDER BY id
-LIMIT $1
-OFFSET $2
+LIMIT $2
+OFFSET $3
 `
 
 type ListPostsParams struct {
+	SiteID int64 `json:"site_id"`
 	Limit  int32 `json:"limit"`
 	Offset int32 `json:"offset"`
 }
 
 func (q *Queries) ListPosts(ctx context.Context, arg ListPostsParams) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, listPosts, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listPosts, arg.SiteID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -153,6 +217,10 @@ func (q *Queries) ListPosts(ctx context.Context, arg ListPostsParams) ([]Post, e
 			&i.PostMimeType,
 			&i.PublishedBy,
 			&i.UpdatedBy,
+			&i.SiteID,
+			&i.Locale,
+			&i.TranslationGroupID,
+			&i.FeaturedMediaID,
 		); err != nil {
 			return nil, err
 		}
@@ -169,40 +237,44 @@ func (q *Queries) ListPosts(ctx context.Context, arg ListPostsParams) ([]Post, e
 
 const updatePosts = `-- name: UpdatePosts :one
 UPDATE posts
-  SET title = $2,
-  content = $3,
-  author_id = $4,
-  url = $5,
-  updated_at = $6,
-  status = $7,
-  published_at = $8,
-  edited_at = $9,
-  post_author = $10,
-  post_mime_type = $11,
-  published_by = $12,
-  updated_by = $13
-WHERE id = $1
-RETURNING id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by
+  SET title = $3,
+  content = $4,
+  author_id = $5,
+  url = $6,
+  updated_at = $7,
+  status = $8,
+  published_at = $9,
+  edited_at = $10,
+  post_author = $11,
+  post_mime_type = $12,
+  published_by = $13,
+  updated_by = $14,
+  featured_media_id = $15
+WHERE site_id = $1 AND id = $2
+RETURNING id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by, site_id, locale, translation_group_id, featured_media_id
 `
 
 type UpdatePostsParams struct {
-	ID           int64     `json:"id"`
-	Title        string    `json:"title"`
-	Content      string    `json:"content"`
-	AuthorID     int64     `json:"author_id"`
-	Url          string    `json:"url"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	Status       string    `json:"status"`
-	PublishedAt  time.Time `json:"published_at"`
-	EditedAt     time.Time `json:"edited_at"`
-	PostAuthor   string    `json:"post_author"`
-	PostMimeType string    `json:"post_mime_type"`
-	PublishedBy  string    `json:"published_by"`
-	UpdatedBy    string    `json:"updated_by"`
+	SiteID          int64         `json:"site_id"`
+	ID              int64         `json:"id"`
+	Title           string        `json:"title"`
+	Content         string        `json:"content"`
+	AuthorID        int64         `json:"author_id"`
+	Url             string        `json:"url"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	Status          PostStatus    `json:"status"`
+	PublishedAt     time.Time     `json:"published_at"`
+	EditedAt        time.Time     `json:"edited_at"`
+	PostAuthor      string        `json:"post_author"`
+	PostMimeType    string        `json:"post_mime_type"`
+	PublishedBy     string        `json:"published_by"`
+	UpdatedBy       string        `json:"updated_by"`
+	FeaturedMediaID sql.NullInt64 `json:"featured_media_id"`
 }
 
 func (q *Queries) UpdatePosts(ctx context.Context, arg UpdatePostsParams) (Post, error) {
 	row := q.db.QueryRowContext(ctx, updatePosts,
+		arg.SiteID,
 		arg.ID,
 		arg.Title,
 		arg.Content,
@@ -216,6 +288,7 @@ func (q *Queries) UpdatePosts(ctx context.Context, arg UpdatePostsParams) (Post,
 		arg.PostMimeType,
 		arg.PublishedBy,
 		arg.UpdatedBy,
+		arg.FeaturedMediaID,
 	)
 	var i Post
 	err := row.Scan(
@@ -233,6 +306,10 @@ func (q *Queries) UpdatePosts(ctx context.Context, arg UpdatePostsParams) (Post,
 		&i.PostMimeType,
 		&i.PublishedBy,
 		&i.UpdatedBy,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+		&i.FeaturedMediaID,
 	)
 	return i, err
 }
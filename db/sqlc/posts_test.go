@@ -1,4 +1,4 @@
-package frog_blossom_db
+package frog_blossom_db_test
 
 import (
 	"context"
@@ -6,28 +6,14 @@ import (
 	"testing"
 	"time"
 
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
 	"github.com/stretchr/testify/require"
 )
 
-func createRandomPosts(t *testing.T) Post {
+func createRandomPosts(t *testing.T) db.Post {
 	// Arrange
-	now := time.Now().UTC()
-	randomUser := createRandomUser(t)
-
-	args := CreatePostsParams{
-		Title:        "Lorem ipsum dolor sit amet",
-		Content:      "Lorem ipsum dolor sit amet, consectetur adipiscing elit.",
-		AuthorID:     randomUser.ID,
-		Url:          "https://example.com",
-		UpdatedAt:    now,
-		Status:       "admin",
-		PublishedAt:  now,
-		EditedAt:     now,
-		PostAuthor:   randomUser.Username,
-		PostMimeType: "text/plain",
-		PublishedBy:  randomUser.Username,
-		UpdatedBy:    randomUser.Username,
-	}
+	args := fixtures.For(t, testQueries).PostParams()
 
 	// Act
 	posts, err := testQueries.CreatePosts(context.Background(), args)
@@ -60,7 +46,7 @@ func TestGetPosts(t *testing.T) {
 	randomPosts := createRandomPosts(t)
 
 	// Act
-	posts, err := testQueries.GetPosts(context.Background(), randomPosts.ID)
+	posts, err := testQueries.GetPosts(context.Background(), db.GetPostsParams{SiteID: randomPosts.SiteID, ID: randomPosts.ID})
 	require.NoError(t, err)
 	require.NotEmpty(t, posts)
 
@@ -86,18 +72,19 @@ func TestUpdatePosts(t *testing.T) {
 	randomPosts := createRandomPosts(t)
 	randomUser := createRandomUser(t)
 
-	posts, err := testQueries.GetPosts(context.Background(), randomPosts.ID)
+	posts, err := testQueries.GetPosts(context.Background(), db.GetPostsParams{SiteID: randomPosts.SiteID, ID: randomPosts.ID})
 	require.NoError(t, err)
 	require.NotEmpty(t, posts)
 
-	args := UpdatePostsParams{
+	args := db.UpdatePostsParams{
+		SiteID:       posts.SiteID,
 		ID:           posts.ID,
 		Title:        "Lorem ipsum dolor sit amet",
 		Content:      "Lorem ipsum dolor sit amet, consectetur adipiscing elit.",
 		AuthorID:     randomUser.ID,
 		Url:          "https://example.com",
 		UpdatedAt:    now,
-		Status:       "admin",
+		Status:       db.PostStatusPublish,
 		PublishedAt:  now,
 		EditedAt:     now,
 		PostAuthor:   randomUser.Username,
@@ -131,11 +118,11 @@ func TestDeletePosts(t *testing.T) {
 	// Arrange
 	randomPosts := createRandomPosts(t)
 
-	err := testQueries.DeletePosts(context.Background(), randomPosts.ID)
+	err := testQueries.DeletePosts(context.Background(), db.DeletePostsParams{SiteID: randomPosts.SiteID, ID: randomPosts.ID})
 	require.NoError(t, err)
 
 	// Act
-	posts, err := testQueries.GetPosts(context.Background(), randomPosts.ID)
+	posts, err := testQueries.GetPosts(context.Background(), db.GetPostsParams{SiteID: randomPosts.SiteID, ID: randomPosts.ID})
 
 	// Assert
 	require.Error(t, err)
@@ -145,12 +132,16 @@ func TestDeletePosts(t *testing.T) {
 
 func TestListPosts(t *testing.T) {
 	// Arrange
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
 	for i := 0; i < 10; i++ {
-		createRandomPosts(t)
+		f.Post(func(p *db.CreatePostsParams) { p.SiteID = site.ID })
 	}
+	createRandomPosts(t)
 
-	args := ListPostsParams{
-		Limit:  5,
+	args := db.ListPostsParams{
+		SiteID: site.ID,
+		Limit:  10,
 		Offset: 5,
 	}
 
@@ -162,6 +153,6 @@ func TestListPosts(t *testing.T) {
 	require.Len(t, posts, 5)
 
 	for _, post := range posts {
-		require.NotEmpty(t, post)
+		require.Equal(t, site.ID, post.SiteID)
 	}
 }
@@ -0,0 +1,90 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+
+package frog_blossom_db
+
+import (
+	"context"
+)
+
+type Querier interface {
+	CountPageChildren(ctx context.Context, arg CountPageChildrenParams) (int64, error)
+	// A file the site already has is not inserted again; see GetMediaByChecksum
+	CreateMedia(ctx context.Context, arg CreateMediaParams) (Media, error)
+	CreateMeta(ctx context.Context, arg CreateMetaParams) (Meta, error)
+	CreatePageComponent(ctx context.Context, arg CreatePageComponentParams) (PageComponent, error)
+	CreatePageOption(ctx context.Context, arg CreatePageOptionParams) (PageOption, error)
+	CreatePages(ctx context.Context, arg CreatePagesParams) (Page, error)
+	CreatePosts(ctx context.Context, arg CreatePostsParams) (Post, error)
+	CreateSite(ctx context.Context, arg CreateSiteParams) (Site, error)
+	CreateTranslationGroup(ctx context.Context, siteID int64) (TranslationGroup, error)
+	CreateUsers(ctx context.Context, arg CreateUsersParams) (User, error)
+	DeleteMedia(ctx context.Context, arg DeleteMediaParams) (Media, error)
+	DeleteMeta(ctx context.Context, arg DeleteMetaParams) error
+	DeleteMetaByPageId(ctx context.Context, arg DeleteMetaByPageIdParams) error
+	DeleteMetaByPostId(ctx context.Context, arg DeleteMetaByPostIdParams) error
+	DeletePageComponent(ctx context.Context, id int64) error
+	DeletePageOptions(ctx context.Context, pageID int64) error
+	DeletePages(ctx context.Context, arg DeletePagesParams) error
+	DeletePosts(ctx context.Context, arg DeletePostsParams) error
+	DeleteSiteMember(ctx context.Context, arg DeleteSiteMemberParams) (SiteMember, error)
+	DeleteSiteSetting(ctx context.Context, arg DeleteSiteSettingParams) error
+	DeleteUsers(ctx context.Context, id int64) error
+	GetMedia(ctx context.Context, arg GetMediaParams) (Media, error)
+	GetMediaByChecksum(ctx context.Context, arg GetMediaByChecksumParams) (Media, error)
+	GetMeta(ctx context.Context, arg GetMetaParams) (Meta, error)
+	GetMetaByPageIDForUpdate(ctx context.Context, arg GetMetaByPageIDForUpdateParams) (Meta, error)
+	GetMetaByPostsIDForUpdate(ctx context.Context, arg GetMetaByPostsIDForUpdateParams) (Meta, error)
+	// Like GetPageInLocale for the page at @url. When pages of several locales
+	// share the url, the one first in @locales is the source.
+	GetPageByUrlInLocale(ctx context.Context, arg GetPageByUrlInLocaleParams) (Page, error)
+	// The variant of the page in the first of @locales it is translated to, or
+	// the page itself if there is none
+	GetPageInLocale(ctx context.Context, arg GetPageInLocaleParams) (Page, error)
+	GetPageSubtree(ctx context.Context, arg GetPageSubtreeParams) ([]Page, error)
+	GetPages(ctx context.Context, arg GetPagesParams) (Page, error)
+	GetPagesForUpdate(ctx context.Context, arg GetPagesForUpdateParams) (Page, error)
+	// The variant of the post in the first of @locales it is translated to, or
+	// the post itself if there is none
+	GetPostInLocale(ctx context.Context, arg GetPostInLocaleParams) (Post, error)
+	GetPosts(ctx context.Context, arg GetPostsParams) (Post, error)
+	GetPostsForUpdate(ctx context.Context, arg GetPostsForUpdateParams) (Post, error)
+	GetSite(ctx context.Context, id int64) (Site, error)
+	GetSiteByDomain(ctx context.Context, domain string) (Site, error)
+	GetSiteMember(ctx context.Context, arg GetSiteMemberParams) (SiteMember, error)
+	GetSiteUser(ctx context.Context, arg GetSiteUserParams) (User, error)
+	GetUsers(ctx context.Context, id int64) (User, error)
+	LeavePageTranslationGroup(ctx context.Context, arg LeavePageTranslationGroupParams) (Page, error)
+	LeavePostTranslationGroup(ctx context.Context, arg LeavePostTranslationGroupParams) (Post, error)
+	ListMedia(ctx context.Context, arg ListMediaParams) ([]Media, error)
+	ListMeta(ctx context.Context, arg ListMetaParams) ([]Meta, error)
+	ListPageComponents(ctx context.Context, pageID int64) ([]PageComponent, error)
+	ListPageOptions(ctx context.Context, pageID int64) ([]PageOption, error)
+	ListPageSiblings(ctx context.Context, arg ListPageSiblingsParams) ([]Page, error)
+	ListPageTranslations(ctx context.Context, arg ListPageTranslationsParams) ([]Page, error)
+	ListPages(ctx context.Context, arg ListPagesParams) ([]Page, error)
+	ListPostTranslations(ctx context.Context, arg ListPostTranslationsParams) ([]Post, error)
+	ListPosts(ctx context.Context, arg ListPostsParams) ([]Post, error)
+	ListSiteMembers(ctx context.Context, siteID int64) ([]SiteMember, error)
+	ListSitePages(ctx context.Context, siteID int64) ([]Page, error)
+	ListSiteSettings(ctx context.Context, siteID int64) ([]SiteSetting, error)
+	ListSites(ctx context.Context, arg ListSitesParams) ([]Site, error)
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	// Serializes moves within a site so two concurrent moves can't form a cycle
+	LockPageTree(ctx context.Context, siteID int64) error
+	MovePage(ctx context.Context, arg MovePageParams) (Page, error)
+	SetPageTranslation(ctx context.Context, arg SetPageTranslationParams) (Page, error)
+	SetPostTranslation(ctx context.Context, arg SetPostTranslationParams) (Post, error)
+	UpdateMediaAltText(ctx context.Context, arg UpdateMediaAltTextParams) (Media, error)
+	UpdateMeta(ctx context.Context, arg UpdateMetaParams) (Meta, error)
+	UpdatePageComponentPosition(ctx context.Context, arg UpdatePageComponentPositionParams) (PageComponent, error)
+	UpdatePageMenuOrder(ctx context.Context, arg UpdatePageMenuOrderParams) error
+	UpdatePages(ctx context.Context, arg UpdatePagesParams) (Page, error)
+	UpdatePosts(ctx context.Context, arg UpdatePostsParams) (Post, error)
+	UpdateUsers(ctx context.Context, arg UpdateUsersParams) (User, error)
+	UpsertSiteMember(ctx context.Context, arg UpsertSiteMemberParams) (SiteMember, error)
+	UpsertSiteSetting(ctx context.Context, arg UpsertSiteSettingParams) (SiteSetting, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,234 @@
+package frog_blossom_db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log/slog"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Replica is a read-only database, usually a *sql.DB of a streaming replica
+type Replica interface {
+	DBTX
+	PingContext(ctx context.Context) error
+}
+
+// replicaPingTimeout bounds a single health check
+const replicaPingTimeout = 2 * time.Second
+
+// ReplicaSet balances read-only queries over the healthy replicas.
+// Replicas start out healthy; Check and Watch keep that up to date, and a
+// replica that fails with a connection error is taken out right away.
+type ReplicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	index   int // 1-based, as in DB_REPLICA_SOURCES
+	conn    Replica
+	healthy atomic.Bool
+}
+
+func NewReplicaSet(replicas ...Replica) *ReplicaSet {
+	set := &ReplicaSet{}
+	for i, conn := range replicas {
+		r := &replica{index: i + 1, conn: conn}
+		r.healthy.Store(true)
+		set.replicas = append(set.replicas, r)
+	}
+	return set
+}
+
+// Healthy returns how many replicas currently take reads
+func (set *ReplicaSet) Healthy() int {
+	n := 0
+	for _, r := range set.replicas {
+		if r.healthy.Load() {
+			n++
+		}
+	}
+	return n
+}
+
+// pick returns the next healthy replica round-robin, or nil
+func (set *ReplicaSet) pick() *replica {
+	n := len(set.replicas)
+	start := set.next.Add(1)
+	for i := 0; i < n; i++ {
+		r := set.replicas[(start+uint64(i))%uint64(n)]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+func (set *ReplicaSet) markDown(r *replica, err error) {
+	if r.healthy.Swap(false) {
+		slog.Warn("read replica unavailable, reading from primary", slog.Int("replica", r.index), slog.Any("error", err))
+	}
+}
+
+// Check pings every replica and updates which ones take reads
+func (set *ReplicaSet) Check(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, r := range set.replicas {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+			defer cancel()
+
+			if err := r.conn.PingContext(ctx); err != nil {
+				set.markDown(r, err)
+				return
+			}
+			if !r.healthy.Swap(true) {
+				slog.Info("read replica available again", slog.Int("replica", r.index))
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+// Watch returns a worker that runs Check every interval until ctx is done
+func (set *ReplicaSet) Watch(interval time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			set.Check(ctx)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+var (
+	leadingComments = regexp.MustCompile(`^(\s*--[^\n]*\n)*\s*`)
+	readStatement   = regexp.MustCompile(`(?i)^(SELECT|WITH)\b`)
+	writeClause     = regexp.MustCompile(`(?i)\b(INSERT|UPDATE|DELETE|MERGE|FOR\s+(NO\s+KEY\s+)?UPDATE|FOR\s+(KEY\s+)?SHARE|NEXTVAL|SETVAL)\b`)
+)
+
+// IsReadOnly reports whether a statement only reads and can be served by
+// a replica: a SELECT (or WITH) that neither writes nor locks rows
+func IsReadOnly(query string) bool {
+	statement := leadingComments.ReplaceAllString(query, "")
+	return readStatement.MatchString(statement) && !writeClause.MatchString(statement)
+}
+
+// routingDBTX sends read-only statements to a replica and everything else
+// to the primary
+type routingDBTX struct {
+	primary  DBTX
+	replicas *ReplicaSet
+}
+
+// replicaFor returns the replica to run query on, or nil for the primary.
+// Writes pin a read-your-writes context to the primary.
+func (d routingDBTX) replicaFor(ctx context.Context, query string) *replica {
+	if !IsReadOnly(query) {
+		markWritten(ctx)
+		return nil
+	}
+	if pinnedToPrimary(ctx) {
+		return nil
+	}
+	return d.replicas.pick()
+}
+
+func (d routingDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if r := d.replicaFor(ctx, query); r != nil {
+		result, err := r.conn.ExecContext(ctx, query, args...)
+		if !isConnError(err) {
+			return result, err
+		}
+		d.replicas.markDown(r, err)
+	}
+	return d.primary.ExecContext(ctx, query, args...)
+}
+
+func (d routingDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.primary.PrepareContext(ctx, query)
+}
+
+func (d routingDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if r := d.replicaFor(ctx, query); r != nil {
+		rows, err := r.conn.QueryContext(ctx, query, args...)
+		if !isConnError(err) {
+			return rows, err
+		}
+		d.replicas.markDown(r, err)
+	}
+	return d.primary.QueryContext(ctx, query, args...)
+}
+
+func (d routingDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if r := d.replicaFor(ctx, query); r != nil {
+		row := r.conn.QueryRowContext(ctx, query, args...)
+		if !isConnError(row.Err()) {
+			return row
+		}
+		d.replicas.markDown(r, row.Err())
+	}
+	return d.primary.QueryRowContext(ctx, query, args...)
+}
+
+// isConnError reports whether err means the server could not be reached or
+// is going away, as opposed to the query failing. Reads are safe to re-run
+// on the primary after such an error.
+func isConnError(err error) bool {
+	// context errors satisfy net.Error but are the caller giving up
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		code := string(pqErr.Code)
+		// class 08: connection exception; 57P01-57P03: shutdown, cannot connect now
+		return strings.HasPrefix(code, "08") || code == "57P01" || code == "57P02" || code == "57P03"
+	}
+	return false
+}
+
+type readYourWritesKey struct{}
+
+// WithReadYourWrites returns a context in which, once a statement that
+// writes has run (including any transaction), later reads go to the
+// primary instead of a replica that may not have replayed the write yet.
+// Use it per request.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, new(atomic.Bool))
+}
+
+func markWritten(ctx context.Context) {
+	if written, ok := ctx.Value(readYourWritesKey{}).(*atomic.Bool); ok {
+		written.Store(true)
+	}
+}
+
+func pinnedToPrimary(ctx context.Context) bool {
+	written, ok := ctx.Value(readYourWritesKey{}).(*atomic.Bool)
+	return ok && written.Load()
+}
@@ -0,0 +1,129 @@
+package frog_blossom_db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReadOnly(t *testing.T) {
+	require.True(t, IsReadOnly(getPosts))
+	require.True(t, IsReadOnly("WITH recent AS (SELECT 1) SELECT * FROM recent"))
+	require.False(t, IsReadOnly(createMeta))
+	require.False(t, IsReadOnly(deleteMetaByPostId))
+	require.False(t, IsReadOnly(getMetaByPostsIDForUpdate))
+	require.False(t, IsReadOnly("WITH gone AS (DELETE FROM posts RETURNING id) SELECT * FROM gone"))
+}
+
+// fakeConn records the queries it ran; err is returned by every query
+type fakeConn struct {
+	DBTX
+	name    string
+	err     error
+	queries *[]string
+	pingErr error
+}
+
+func (f fakeConn) ExecContext(_ context.Context, query string, _ ...interface{}) (sql.Result, error) {
+	*f.queries = append(*f.queries, f.name+" "+QueryName(query))
+	return nil, f.err
+}
+
+func (f fakeConn) QueryContext(_ context.Context, query string, _ ...interface{}) (*sql.Rows, error) {
+	*f.queries = append(*f.queries, f.name+" "+QueryName(query))
+	return nil, f.err
+}
+
+func (f fakeConn) PingContext(context.Context) error {
+	return f.pingErr
+}
+
+func TestRoutingDBTX(t *testing.T) {
+	var queries []string
+	primary := fakeConn{name: "primary", queries: &queries}
+	replicas := NewReplicaSet(
+		fakeConn{name: "replica1", queries: &queries},
+		fakeConn{name: "replica2", queries: &queries},
+	)
+	routing := routingDBTX{primary: primary, replicas: replicas}
+	ctx := context.Background()
+
+	// reads are balanced over the replicas, writes go to the primary
+	routing.QueryContext(ctx, listPosts)
+	routing.QueryContext(ctx, listPosts)
+	routing.ExecContext(ctx, deletePosts)
+	require.Equal(t, []string{"replica2 ListPosts", "replica1 ListPosts", "primary DeletePosts"}, queries)
+}
+
+func TestRoutingDBTXReadYourWrites(t *testing.T) {
+	var queries []string
+	routing := routingDBTX{
+		primary:  fakeConn{name: "primary", queries: &queries},
+		replicas: NewReplicaSet(fakeConn{name: "replica", queries: &queries}),
+	}
+	ctx := WithReadYourWrites(context.Background())
+
+	routing.QueryContext(ctx, listPosts)
+	routing.ExecContext(ctx, deletePosts)
+	routing.QueryContext(ctx, listPosts)
+
+	require.Equal(t, []string{"replica ListPosts", "primary DeletePosts", "primary ListPosts"}, queries)
+}
+
+func TestRoutingDBTXFallback(t *testing.T) {
+	var queries []string
+	down := fakeConn{name: "replica", queries: &queries, err: driver.ErrBadConn, pingErr: errors.New("refused")}
+	replicas := NewReplicaSet(down)
+	routing := routingDBTX{primary: fakeConn{name: "primary", queries: &queries}, replicas: replicas}
+	ctx := context.Background()
+
+	// a connection error takes the replica out and re-runs on the primary
+	_, err := routing.QueryContext(ctx, listPosts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"replica ListPosts", "primary ListPosts"}, queries)
+	require.Equal(t, 0, replicas.Healthy())
+
+	routing.QueryContext(ctx, listPosts)
+	require.Equal(t, "primary ListPosts", queries[len(queries)-1])
+
+	// the health check brings it back once it answers
+	replicas.replicas[0].conn = fakeConn{name: "replica", queries: &queries}
+	replicas.Check(ctx)
+	require.Equal(t, 1, replicas.Healthy())
+}
+
+func TestRoutingDBTXQueryErrorsAreNotRetried(t *testing.T) {
+	var queries []string
+	failing := fakeConn{name: "replica", queries: &queries, err: &pq.Error{Code: "42703"}}
+	replicas := NewReplicaSet(failing)
+	routing := routingDBTX{primary: fakeConn{name: "primary", queries: &queries}, replicas: replicas}
+
+	_, err := routing.QueryContext(context.Background(), listPosts)
+	require.Error(t, err)
+	require.Equal(t, []string{"replica ListPosts"}, queries)
+	require.Equal(t, 1, replicas.Healthy())
+}
+
+// TestRoutingDBTXFallbackFromUnreachablePool uses real pools, so the
+// connection error comes from database/sql and the driver rather than a fake
+func TestRoutingDBTXFallbackFromUnreachablePool(t *testing.T) {
+	open := func(port string) *sql.DB {
+		pool, err := OpenScoped("postgres", "postgres://frog@127.0.0.1:"+port+"/frog?sslmode=disable&connect_timeout=1")
+		require.NoError(t, err)
+		t.Cleanup(func() { pool.Close() })
+		return pool
+	}
+	replicas := NewReplicaSet(open("1"))
+	routing := routingDBTX{primary: open("2"), replicas: replicas}
+	ctx := WithSite(context.Background(), Site{ID: 1})
+
+	// the row fails on the primary, the replica is taken out
+	row := routing.QueryRowContext(ctx, getPosts, 1, 1)
+	require.ErrorContains(t, row.Err(), "127.0.0.1:2")
+	require.Equal(t, 0, replicas.Healthy())
+}
@@ -0,0 +1,284 @@
+package frog_blossom_db_test
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/database"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+// appDB connects to the test schema as a fresh login member of frog_app,
+// like the app does, so the row-level security policies apply to it
+func appDB(t testing.TB) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	suffix := make([]byte, 6)
+	_, err := rand.Read(suffix)
+	require.NoError(t, err)
+	role, password := "frog_app_"+hex.EncodeToString(suffix), hex.EncodeToString(suffix)
+
+	// the privileges come from the migrations, as for the app's login role
+	_, err = testDB.ExecContext(ctx, fmt.Sprintf(`CREATE ROLE %q LOGIN PASSWORD '%s' NOSUPERUSER NOBYPASSRLS IN ROLE frog_app`, role, password))
+	require.NoError(t, err)
+
+	u, err := url.Parse(testURL)
+	require.NoError(t, err)
+	u.User = url.UserPassword(role, password)
+	conn, err := db.OpenScoped("postgres", u.String())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		testDB.Exec(fmt.Sprintf(`DROP OWNED BY %q`, role))
+		testDB.Exec(fmt.Sprintf(`DROP ROLE %q`, role))
+	})
+	return conn
+}
+
+// tenant is a site with an editor, a page with options, a post, a media
+// item and a setting
+type tenant struct {
+	site   db.Site
+	editor db.User
+	page   db.Page
+	post   db.Post
+	media  db.Media
+}
+
+func createTenant(t testing.TB) tenant {
+	t.Helper()
+
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+	editor := f.SiteUser(site.ID)
+	page := f.Page(func(p *db.CreatePagesParams) {
+		p.SiteID = site.ID
+		p.AuthorID = editor.ID
+		p.PageAuthor = editor.Username
+	})
+	_, err := testQueries.CreatePageOption(context.Background(), db.CreatePageOptionParams{
+		PageID: page.ID,
+		Name:   "theme",
+		Type:   db.OptionTypeString,
+		Value:  json.RawMessage(`"dark"`),
+	})
+	require.NoError(t, err)
+	_, err = testQueries.UpsertSiteSetting(context.Background(), db.UpsertSiteSettingParams{
+		SiteID: site.ID,
+		Key:    "page_amount",
+		Value:  json.RawMessage(`25`),
+	})
+	require.NoError(t, err)
+
+	return tenant{
+		site:   site,
+		editor: editor,
+		page:   page,
+		post:   f.Post(func(p *db.CreatePostsParams) { p.SiteID = site.ID }),
+		media:  createMedia(t, site.ID, editor.ID, f.String(32)),
+	}
+}
+
+func TestRowLevelSecurityReads(t *testing.T) {
+	store := db.NewStore(appDB(t))
+	a, b := createTenant(t), createTenant(t)
+	ctx := db.WithSite(context.Background(), a.site)
+
+	page, err := store.GetPages(ctx, db.GetPagesParams{SiteID: a.site.ID, ID: a.page.ID})
+	require.NoError(t, err)
+	require.Equal(t, a.page.ID, page.ID)
+
+	// the queries below ask for the other site, as a buggy caller would
+	_, err = store.GetPages(ctx, db.GetPagesParams{SiteID: b.site.ID, ID: b.page.ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = store.GetPosts(ctx, db.GetPostsParams{SiteID: b.site.ID, ID: b.post.ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = store.GetMedia(ctx, db.GetMediaParams{SiteID: b.site.ID, ID: b.media.ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	pages, err := store.ListSitePages(ctx, b.site.ID)
+	require.NoError(t, err)
+	require.Empty(t, pages)
+
+	settings, err := store.ListSiteSettings(ctx, b.site.ID)
+	require.NoError(t, err)
+	require.Empty(t, settings)
+
+	members, err := store.ListSiteMembers(ctx, b.site.ID)
+	require.NoError(t, err)
+	require.Empty(t, members)
+
+	// ListPageOptions has no site filter at all
+	options, err := store.ListPageOptions(ctx, b.page.ID)
+	require.NoError(t, err)
+	require.Empty(t, options)
+	options, err = store.ListPageOptions(ctx, a.page.ID)
+	require.NoError(t, err)
+	require.Len(t, options, 1)
+}
+
+func TestRowLevelSecurityWrites(t *testing.T) {
+	store := db.NewStore(appDB(t))
+	a, b := createTenant(t), createTenant(t)
+	ctx := db.WithSite(context.Background(), a.site)
+
+	_, err := store.UpdatePages(ctx, db.UpdatePagesParams{
+		SiteID:         b.site.ID,
+		ID:             b.page.ID,
+		AuthorID:       b.page.AuthorID,
+		PageAuthor:     b.page.PageAuthor,
+		Title:          "defaced",
+		Url:            b.page.Url,
+		PageIdentifier: b.page.PageIdentifier,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, store.DeletePosts(ctx, db.DeletePostsParams{SiteID: b.site.ID, ID: b.post.ID}))
+	_, err = testQueries.GetPosts(context.Background(), db.GetPostsParams{SiteID: b.site.ID, ID: b.post.ID})
+	require.NoError(t, err, "the other site's post must survive")
+
+	_, err = store.CreatePosts(ctx, fixtures.For(t, testQueries).PostParams(func(p *db.CreatePostsParams) {
+		p.SiteID = b.site.ID
+		p.AuthorID = b.editor.ID
+	}))
+	require.ErrorIs(t, apperr.FromDB(err), apperr.ErrForbidden)
+
+	// a transaction for the other site can not even see its members
+	_, err = store.CreatePageTx(ctx, db.CreateContentTxParams{
+		SiteID: b.site.ID,
+		UserId: b.editor.ID,
+		Pages:  []db.CreatePagesParams{fixtures.For(t, testQueries).PageParams(func(p *db.CreatePagesParams) { p.SiteID = b.site.ID })},
+	})
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+
+	page, err := testQueries.GetPages(context.Background(), db.GetPagesParams{SiteID: b.site.ID, ID: b.page.ID})
+	require.NoError(t, err)
+	require.Equal(t, b.page.Title, page.Title)
+}
+
+func TestRowLevelSecuritySettingDoesNotLeak(t *testing.T) {
+	conn := appDB(t)
+	// every statement runs on the same connection
+	conn.SetMaxOpenConns(1)
+	store := db.NewStore(conn)
+	a, b := createTenant(t), createTenant(t)
+
+	_, err := store.GetPages(db.WithSite(context.Background(), a.site), db.GetPagesParams{SiteID: a.site.ID, ID: a.page.ID})
+	require.NoError(t, err)
+
+	_, err = store.GetPages(db.WithSite(context.Background(), b.site), db.GetPagesParams{SiteID: b.site.ID, ID: b.page.ID})
+	require.NoError(t, err)
+
+	_, err = store.MovePageTx(db.WithSite(context.Background(), a.site), db.MovePageTxParams{SiteID: a.site.ID, PageID: a.page.ID})
+	require.NoError(t, err)
+
+	// the transaction's site was local to it, the session is still on b
+	_, err = store.GetPages(db.WithSite(context.Background(), b.site), db.GetPagesParams{SiteID: b.site.ID, ID: b.page.ID})
+	require.NoError(t, err)
+
+	// statements without a site see none
+	pages, err := store.ListSitePages(context.Background(), b.site.ID)
+	require.NoError(t, err)
+	require.Empty(t, pages)
+}
+
+func TestRowLevelSecurityWithoutSite(t *testing.T) {
+	store := db.NewStore(appDB(t))
+	a := createTenant(t)
+
+	_, err := store.GetPages(context.Background(), db.GetPagesParams{SiteID: a.site.ID, ID: a.page.ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, store.DeletePosts(context.Background(), db.DeletePostsParams{SiteID: a.site.ID, ID: a.post.ID}))
+	_, err = testQueries.GetPosts(context.Background(), db.GetPostsParams{SiteID: a.site.ID, ID: a.post.ID})
+	require.NoError(t, err, "the post must survive")
+
+	_, err = store.CreatePosts(context.Background(), fixtures.For(t, testQueries).PostParams(func(p *db.CreatePostsParams) {
+		p.SiteID = a.site.ID
+		p.AuthorID = a.editor.ID
+	}))
+	require.ErrorIs(t, apperr.FromDB(err), apperr.ErrForbidden)
+}
+
+// TestRowLevelSecurityWithReplicas runs writes on the primary and reads on
+// a replica, here a second pool on the same database
+func TestRowLevelSecurityWithReplicas(t *testing.T) {
+	store := db.NewStore(appDB(t), db.WithReplicas(db.NewReplicaSet(appDB(t))))
+	a, b := createTenant(t), createTenant(t)
+	ctx := db.WithSite(context.Background(), a.site)
+
+	page, err := store.GetPages(ctx, db.GetPagesParams{SiteID: a.site.ID, ID: a.page.ID})
+	require.NoError(t, err)
+	require.Equal(t, a.page.ID, page.ID)
+	_, err = store.GetPages(ctx, db.GetPagesParams{SiteID: b.site.ID, ID: b.page.ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, store.DeletePosts(ctx, db.DeletePostsParams{SiteID: b.site.ID, ID: b.post.ID}))
+	_, err = testQueries.GetPosts(context.Background(), db.GetPostsParams{SiteID: b.site.ID, ID: b.post.ID})
+	require.NoError(t, err, "the other site's post must survive")
+
+	_, err = store.CreatePosts(ctx, fixtures.For(t, testQueries).PostParams(func(p *db.CreatePostsParams) {
+		p.SiteID = b.site.ID
+		p.AuthorID = b.editor.ID
+	}))
+	require.ErrorIs(t, apperr.FromDB(err), apperr.ErrForbidden)
+
+	// reads after a write are pinned to the primary, which is scoped too
+	pinned := db.WithReadYourWrites(ctx)
+	require.NoError(t, store.DeletePosts(pinned, db.DeletePostsParams{SiteID: a.site.ID, ID: 0}))
+	_, err = store.GetPosts(pinned, db.GetPostsParams{SiteID: b.site.ID, ID: b.post.ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = store.GetPosts(pinned, db.GetPostsParams{SiteID: a.site.ID, ID: a.post.ID})
+	require.NoError(t, err)
+}
+
+func TestCheckRole(t *testing.T) {
+	ctx := context.Background()
+
+	// the tests' own connection owns the schema and skips the policies
+	require.ErrorIs(t, database.CheckRole(ctx, testDB), database.ErrBypassesRLS)
+	require.NoError(t, database.CheckRole(ctx, appDB(t)))
+}
+
+// BenchmarkSiteScopedReads shows what scoping costs: reads for the site a
+// connection is already set to take one round trip, reads that switch
+// sites one more for set_config
+func BenchmarkSiteScopedReads(b *testing.B) {
+	conn := appDB(b)
+	conn.SetMaxOpenConns(1)
+	store := db.NewStore(conn)
+	x, y := createTenant(b), createTenant(b)
+	ctxX, ctxY := db.WithSite(context.Background(), x.site), db.WithSite(context.Background(), y.site)
+
+	b.Run("SameSite", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := store.GetPages(ctxX, db.GetPagesParams{SiteID: x.site.ID, ID: x.page.ID}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("AlternatingSites", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctx, t := ctxX, x
+			if i%2 == 1 {
+				ctx, t = ctxY, y
+			}
+			if _, err := store.GetPages(ctx, db.GetPagesParams{SiteID: t.site.ID, ID: t.page.ID}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
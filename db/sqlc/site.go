@@ -0,0 +1,216 @@
+package frog_blossom_db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+type siteKey struct{}
+
+// WithSite returns a context scoped to site. The API resolves it from the
+// Host header of each request. The store sets app.current_site from it, so
+// the row-level security policies of migration 000011 hide the rows of
+// every other site. Without a site, the app role sees none (000014).
+func WithSite(ctx context.Context, site Site) context.Context {
+	return context.WithValue(ctx, siteKey{}, site)
+}
+
+// SiteFrom returns the site ctx is scoped to
+func SiteFrom(ctx context.Context) (Site, bool) {
+	site, ok := ctx.Value(siteKey{}).(Site)
+	return site, ok
+}
+
+// setCurrentSite sets app.current_site for the transaction ($2 = true) or
+// the session. An empty id leaves the connection without a site, which
+// the policies give no rows.
+const setCurrentSite = `SELECT set_config('app.current_site', $1, $2)`
+
+// currentSite returns the app.current_site value for ctx
+func currentSite(ctx context.Context) string {
+	if site, ok := SiteFrom(ctx); ok {
+		return strconv.FormatInt(site.ID, 10)
+	}
+	return ""
+}
+
+// OpenScoped opens a pool like sql.Open whose connections set
+// app.current_site from the context of every statement run outside a
+// transaction; transactions set it themselves, see runTx. The store must
+// be given such a pool for the app role to see any rows.
+//
+// A connection remembers the site its session is set to, so setting it
+// costs a round trip only when a statement is for another site than the
+// previous one on that connection, and the query itself then runs as
+// usual. This relies on the session staying with the connection, so a
+// pooler in front of Postgres must pool sessions, not transactions.
+// Prepared statements are scoped when they are prepared, not run; sqlc is
+// configured not to prepare queries.
+func OpenScoped(driverName, dataSourceName string) (*sql.DB, error) {
+	pool, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	drv := pool.Driver()
+	pool.Close()
+
+	var connector driver.Connector = dsnConnector{dsn: dataSourceName, driver: drv}
+	if opener, ok := drv.(driver.DriverContext); ok {
+		if connector, err = opener.OpenConnector(dataSourceName); err != nil {
+			return nil, err
+		}
+	}
+	return sql.OpenDB(siteConnector{connector}), nil
+}
+
+// dsnConnector connects drivers without a connector of their own, such as
+// lib/pq, the way sql.Open does
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type siteConnector struct {
+	driver.Connector
+}
+
+func (c siteConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &siteConn{Conn: conn}, nil
+}
+
+// siteConn is a connection of an OpenScoped pool. database/sql uses a
+// connection from one goroutine at a time, so its fields need no lock.
+type siteConn struct {
+	driver.Conn
+	site  string // app.current_site of the session, if known
+	known bool
+	inTx  bool
+}
+
+// scope sets the session's app.current_site to the site of ctx unless it
+// already is. The value is an integer or empty, so it is sent inline as a
+// simple query, which takes one round trip where a parameter takes two.
+func (c *siteConn) scope(ctx context.Context) error {
+	site := currentSite(ctx)
+	if c.inTx || (c.known && c.site == site) {
+		return nil
+	}
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return errors.New("set current site err: driver can not run statements with a context")
+	}
+
+	c.known = false
+	query := fmt.Sprintf(`SELECT set_config('app.current_site', '%s', false)`, site)
+	if _, err := execer.ExecContext(ctx, query, nil); err != nil {
+		return fmt.Errorf("set current site err: %w", err)
+	}
+	c.site, c.known = site, true
+	return nil
+}
+
+func (c *siteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *siteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *siteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *siteConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	beginner, ok := c.Conn.(driver.ConnBeginTx)
+	if !ok {
+		return nil, errors.New("driver does not support transactions with a context")
+	}
+	tx, err := beginner.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.inTx = true
+	return siteTx{Tx: tx, conn: c}, nil
+}
+
+func (c *siteConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *siteConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *siteConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *siteConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+// siteTx ends the transaction of a siteConn. The site it set is local to
+// the transaction, so the session's is unchanged afterwards.
+type siteTx struct {
+	driver.Tx
+	conn *siteConn
+}
+
+func (tx siteTx) Commit() error {
+	tx.conn.inTx = false
+	return tx.Tx.Commit()
+}
+
+func (tx siteTx) Rollback() error {
+	tx.conn.inTx = false
+	return tx.Tx.Rollback()
+}
@@ -0,0 +1,92 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: site_settings.sql
+
+package frog_blossom_db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const deleteSiteSetting = `-- name: DeleteSiteSetting :exec
+DELETE FROM site_settings
+WHERE site_id = $1 AND key = $2
+`
+
+type DeleteSiteSettingParams struct {
+	SiteID int64  `json:"site_id"`
+	Key    string `json:"key"`
+}
+
+func (q *Queries) DeleteSiteSetting(ctx context.Context, arg DeleteSiteSettingParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSiteSetting, arg.SiteID, arg.Key)
+	return err
+}
+
+const listSiteSettings = `-- name: ListSiteSettings :many
+SELECT key, value, updated_at, site_id FROM site_settings
+WHERE site_id = $1
+ORDER BY key
+`
+
+func (q *Queries) ListSiteSettings(ctx context.Context, siteID int64) ([]SiteSetting, error) {
+	rows, err := q.db.QueryContext(ctx, listSiteSettings, siteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SiteSetting
+	for rows.Next() {
+		var i SiteSetting
+		if err := rows.Scan(
+			&i.Key,
+			&i.Value,
+			&i.UpdatedAt,
+			&i.SiteID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertSiteSetting = `-- name: UpsertSiteSetting :one
+INSERT INTO site_settings (
+  site_id,
+  key,
+  value
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (site_id, key) DO UPDATE
+  SET value = EXCLUDED.value,
+  updated_at = now()
+RETURNING key, value, updated_at, site_id
+`
+
+type UpsertSiteSettingParams struct {
+	SiteID int64           `json:"site_id"`
+	Key    string          `json:"key"`
+	Value  json.RawMessage `json:"value"`
+}
+
+func (q *Queries) UpsertSiteSetting(ctx context.Context, arg UpsertSiteSettingParams) (SiteSetting, error) {
+	row := q.db.QueryRowContext(ctx, upsertSiteSetting, arg.SiteID, arg.Key, arg.Value)
+	var i SiteSetting
+	err := row.Scan(
+		&i.Key,
+		&i.Value,
+		&i.UpdatedAt,
+		&i.SiteID,
+	)
+	return i, err
+}
@@ -0,0 +1,55 @@
+package frog_blossom_db_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertSiteSetting(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	siteID := fixtures.For(t, testQueries).Site().ID
+	args := db.UpsertSiteSettingParams{SiteID: siteID, Key: "page_amount", Value: json.RawMessage(`25`)}
+
+	// Act
+	created, err := testQueries.UpsertSiteSetting(ctx, args)
+	require.NoError(t, err)
+	args.Value = json.RawMessage(`50`)
+	updated, err := testQueries.UpsertSiteSetting(ctx, args)
+	require.NoError(t, err)
+
+	// Assert
+	require.JSONEq(t, `25`, string(created.Value))
+	require.JSONEq(t, `50`, string(updated.Value))
+	require.False(t, updated.UpdatedAt.Before(created.UpdatedAt))
+
+	settings, err := testQueries.ListSiteSettings(ctx, siteID)
+	require.NoError(t, err)
+	require.Len(t, settings, 1)
+	require.JSONEq(t, `50`, string(settings[0].Value))
+}
+
+func TestDeleteSiteSetting(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	siteID := fixtures.For(t, testQueries).Site().ID
+	for _, key := range []string{"page_amount", "site_language"} {
+		_, err := testQueries.UpsertSiteSetting(ctx, db.UpsertSiteSettingParams{SiteID: siteID, Key: key, Value: json.RawMessage(`"x"`)})
+		require.NoError(t, err)
+	}
+
+	// Act
+	err := testQueries.DeleteSiteSetting(ctx, db.DeleteSiteSettingParams{SiteID: siteID, Key: "page_amount"})
+
+	// Assert
+	require.NoError(t, err)
+	settings, err := testQueries.ListSiteSettings(ctx, siteID)
+	require.NoError(t, err)
+	require.Len(t, settings, 1)
+	require.Equal(t, "site_language", settings[0].Key)
+}
@@ -0,0 +1,118 @@
+package frog_blossom_db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDriver is a driver whose connections record the statements
+// they are sent and return no rows
+type recordingDriver struct {
+	statements *[]string
+}
+
+func (d recordingDriver) Open(string) (driver.Conn, error) {
+	return recordingConn{statements: d.statements}, nil
+}
+
+func (d recordingDriver) OpenConnector(string) (driver.Connector, error) {
+	return recordingConnector{d}, nil
+}
+
+type recordingConnector struct {
+	d recordingDriver
+}
+
+func (c recordingConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c recordingConnector) Driver() driver.Driver                        { return c.d }
+
+type recordingConn struct {
+	driver.Conn
+	statements *[]string
+}
+
+func (c recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	*c.statements = append(*c.statements, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	*c.statements = append(*c.statements, query)
+	return noRows{}, nil
+}
+
+func (c recordingConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	*c.statements = append(*c.statements, "BEGIN")
+	return recordingTx(c), nil
+}
+
+func (c recordingConn) Close() error { return nil }
+
+type recordingTx struct {
+	driver.Conn
+	statements *[]string
+}
+
+func (tx recordingTx) Commit() error {
+	*tx.statements = append(*tx.statements, "COMMIT")
+	return nil
+}
+
+func (tx recordingTx) Rollback() error {
+	*tx.statements = append(*tx.statements, "ROLLBACK")
+	return nil
+}
+
+type noRows struct{}
+
+func (noRows) Columns() []string         { return nil }
+func (noRows) Close() error              { return nil }
+func (noRows) Next([]driver.Value) error { return io.EOF }
+
+var recorded []string
+
+func init() {
+	sql.Register("recording", recordingDriver{statements: &recorded})
+}
+
+func TestOpenScopedSetsSiteOnChange(t *testing.T) {
+	// Arrange
+	recorded = nil
+	pool, err := OpenScoped("recording", "")
+	require.NoError(t, err)
+	defer pool.Close()
+	pool.SetMaxOpenConns(1)
+	siteA := WithSite(context.Background(), Site{ID: 1})
+	siteB := WithSite(context.Background(), Site{ID: 2})
+
+	// Act
+	pool.QueryRowContext(siteA, "SELECT 'a1'").Scan()
+	pool.QueryRowContext(siteA, "SELECT 'a2'").Scan()
+	pool.ExecContext(siteB, "UPDATE b")
+	tx, err := pool.BeginTx(siteA, nil)
+	require.NoError(t, err)
+	tx.ExecContext(siteA, "UPDATE a")
+	require.NoError(t, tx.Commit())
+	pool.QueryRowContext(siteB, "SELECT 'b'").Scan()
+	pool.QueryRowContext(context.Background(), "SELECT 'none'").Scan()
+
+	// Assert
+	require.Equal(t, []string{
+		`SELECT set_config('app.current_site', '1', false)`,
+		"SELECT 'a1'",
+		"SELECT 'a2'", // same site, no round trip
+		`SELECT set_config('app.current_site', '2', false)`,
+		"UPDATE b",
+		"BEGIN", // transactions set their site locally
+		"UPDATE a",
+		"COMMIT",
+		"SELECT 'b'", // the session kept site 2
+		`SELECT set_config('app.current_site', '', false)`,
+		"SELECT 'none'",
+	}, recorded)
+}
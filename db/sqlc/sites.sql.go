@@ -0,0 +1,220 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: sites.sql
+
+package frog_blossom_db
+
+import (
+	"context"
+)
+
+const createSite = `-- name: CreateSite :one
+INSERT INTO sites (
+  domain,
+  name
+) VALUES (
+  $1, $2
+) RETURNING id, domain, name, created_at
+`
+
+type CreateSiteParams struct {
+	Domain string `json:"domain"`
+	Name   string `json:"name"`
+}
+
+func (q *Queries) CreateSite(ctx context.Context, arg CreateSiteParams) (Site, error) {
+	row := q.db.QueryRowContext(ctx, createSite, arg.Domain, arg.Name)
+	var i Site
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteSiteMember = `-- name: DeleteSiteMember :one
+DELETE FROM site_members
+WHERE site_id = $1 AND user_id = $2
+RETURNING site_id, user_id, role, created_at
+`
+
+type DeleteSiteMemberParams struct {
+	SiteID int64 `json:"site_id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) DeleteSiteMember(ctx context.Context, arg DeleteSiteMemberParams) (SiteMember, error) {
+	row := q.db.QueryRowContext(ctx, deleteSiteMember, arg.SiteID, arg.UserID)
+	var i SiteMember
+	err := row.Scan(
+		&i.SiteID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSite = `-- name: GetSite :one
+SELECT id, domain, name, created_at FROM sites
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetSite(ctx context.Context, id int64) (Site, error) {
+	row := q.db.QueryRowContext(ctx, getSite, id)
+	var i Site
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSiteByDomain = `-- name: GetSiteByDomain :one
+SELECT id, domain, name, created_at FROM sites
+WHERE domain = $1 LIMIT 1
+`
+
+func (q *Queries) GetSiteByDomain(ctx context.Context, domain string) (Site, error) {
+	row := q.db.QueryRowContext(ctx, getSiteByDomain, domain)
+	var i Site
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSiteMember = `-- name: GetSiteMember :one
+SELECT site_id, user_id, role, created_at FROM site_members
+WHERE site_id = $1 AND user_id = $2 LIMIT 1
+`
+
+type GetSiteMemberParams struct {
+	SiteID int64 `json:"site_id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) GetSiteMember(ctx context.Context, arg GetSiteMemberParams) (SiteMember, error) {
+	row := q.db.QueryRowContext(ctx, getSiteMember, arg.SiteID, arg.UserID)
+	var i SiteMember
+	err := row.Scan(
+		&i.SiteID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listSiteMembers = `-- name: ListSiteMembers :many
+SELECT site_id, user_id, role, created_at FROM site_members
+WHERE site_id = $1
+ORDER BY user_id
+`
+
+func (q *Queries) ListSiteMembers(ctx context.Context, siteID int64) ([]SiteMember, error) {
+	rows, err := q.db.QueryContext(ctx, listSiteMembers, siteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SiteMember
+	for rows.Next() {
+		var i SiteMember
+		if err := rows.Scan(
+			&i.SiteID,
+			&i.UserID,
+			&i.Role,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSites = `-- name: ListSites :many
+SELECT id, domain, name, created_at FROM sites
+ORDER BY id
+LIMIT $1
+OFFSET $2
+`
+
+type ListSitesParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListSites(ctx context.Context, arg ListSitesParams) ([]Site, error) {
+	rows, err := q.db.QueryContext(ctx, listSites, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Site
+	for rows.Next() {
+		var i Site
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Name,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertSiteMember = `-- name: UpsertSiteMember :one
+INSERT INTO site_members (
+  site_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (site_id, user_id) DO UPDATE
+  SET role = EXCLUDED.role
+RETURNING site_id, user_id, role, created_at
+`
+
+type UpsertSiteMemberParams struct {
+	SiteID int64    `json:"site_id"`
+	UserID int64    `json:"user_id"`
+	Role   SiteRole `json:"role"`
+}
+
+func (q *Queries) UpsertSiteMember(ctx context.Context, arg UpsertSiteMemberParams) (SiteMember, error) {
+	row := q.db.QueryRowContext(ctx, upsertSiteMember, arg.SiteID, arg.UserID, arg.Role)
+	var i SiteMember
+	err := row.Scan(
+		&i.SiteID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,93 @@
+package frog_blossom_db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSiteByDomain(t *testing.T) {
+	// Arrange
+	site := fixtures.For(t, testQueries).Site()
+
+	// Act
+	found, err := testQueries.GetSiteByDomain(context.Background(), site.Domain)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, site, found)
+
+	_, err = testQueries.GetSiteByDomain(context.Background(), "missing."+site.Domain)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpsertSiteMember(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+	user := f.User()
+
+	// Act
+	f.Member(site.ID, user.ID, db.SiteRoleViewer)
+	member := f.Member(site.ID, user.ID, db.SiteRoleOwner)
+
+	// Assert
+	require.Equal(t, db.SiteRoleOwner, member.Role)
+	members, err := testQueries.ListSiteMembers(ctx, site.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+
+	deleted, err := testQueries.DeleteSiteMember(ctx, db.DeleteSiteMemberParams{SiteID: site.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, member, deleted)
+	_, err = testQueries.GetSiteMember(ctx, db.GetSiteMemberParams{SiteID: site.ID, UserID: user.ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestGetSiteUser(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+	user := f.SiteUser(site.ID)
+	outsider := f.User()
+
+	// Act
+	found, err := testQueries.GetSiteUser(ctx, db.GetSiteUserParams{SiteID: site.ID, ID: user.ID})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, user.ID, found.ID)
+
+	_, err = testQueries.GetSiteUser(ctx, db.GetSiteUserParams{SiteID: site.ID, ID: outsider.ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCreateSiteUserTx(t *testing.T) {
+	store := db.NewStore(testDB)
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+
+	result, err := store.CreateSiteUserTx(context.Background(), db.CreateSiteUserTxParams{
+		SiteID: site.ID,
+		User:   f.UserParams(),
+		Role:   db.SiteRoleViewer,
+	})
+	require.NoError(t, err)
+	require.Equal(t, site.ID, result.Member.SiteID)
+	require.Equal(t, result.User.ID, result.Member.UserID)
+	require.Equal(t, db.SiteRoleViewer, result.Member.Role)
+
+	_, err = store.CreateSiteUserTx(context.Background(), db.CreateSiteUserTxParams{
+		SiteID: -1,
+		User:   f.UserParams(),
+		Role:   db.SiteRoleEditor,
+	})
+	require.ErrorIs(t, err, apperr.ErrValidation)
+}
@@ -3,76 +3,218 @@ package frog_blossom_db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/reflection/frog_blossom_db/internal/apperr"
 )
 
-// Functions for executing db queries and transactions
+// Store provides all functions for executing db queries and transactions
+type Store interface {
+	Querier
+	InitSetupConfigTx(ctx context.Context, args InitSetupConfigTxParams) (InitSetupConfigTxResult, error)
+	CreatePostsTx(ctx context.Context, args CreateContentTxParams) (CreateContentTxResult, error)
+	CreatePageTx(ctx context.Context, args CreateContentTxParams) (CreateContentTxResult, error)
+	UpdatePostsTx(ctx context.Context, args UpdateContentTxParams) (UpdateContentTxResult, error)
+	UpdatePageTx(ctx context.Context, args UpdateContentTxParams) (UpdateContentTxResult, error)
+	DeletePostsTx(ctx context.Context, args DeleteContentTxParams) (DeleteContentTxResult, error)
+	DeletePageTx(ctx context.Context, args DeleteContentTxParams) (DeleteContentTxResult, error)
+	MovePageTx(ctx context.Context, args MovePageTxParams) (MovePageTxResult, error)
+	ReorderPagesTx(ctx context.Context, args ReorderPagesTxParams) (ReorderPagesTxResult, error)
+	AddPageComponentTx(ctx context.Context, args AddPageComponentTxParams) (PageComponentsTxResult, error)
+	MovePageComponentTx(ctx context.Context, args MovePageComponentTxParams) (PageComponentsTxResult, error)
+	RemovePageComponentTx(ctx context.Context, args RemovePageComponentTxParams) (PageComponentsTxResult, error)
+	CreateSiteUserTx(ctx context.Context, args CreateSiteUserTxParams) (CreateSiteUserTxResult, error)
+	AddPageTranslationTx(ctx context.Context, args AddTranslationTxParams) (PageTranslationsTxResult, error)
+	AddPostTranslationTx(ctx context.Context, args AddTranslationTxParams) (PostTranslationsTxResult, error)
+	CreateMediaTx(ctx context.Context, args CreateMediaParams) (CreateMediaTxResult, error)
+	DeleteMediaTx(ctx context.Context, args DeleteMediaParams) (Media, error)
+}
 
-type Store struct {
+// SQLStore provides all functions for executing SQL queries and transactions
+type SQLStore struct {
 	*Queries
-	db *sql.DB
+	db          *sql.DB
+	replicas    *ReplicaSet
+	middlewares []DBTXMiddleware
+	txObservers []func(TxEvent)
+}
+
+// StoreOption configures an SQLStore
+type StoreOption func(*SQLStore)
+
+// WithDBTXMiddleware wraps every query, inside and outside transactions.
+// The first middleware is the outermost.
+func WithDBTXMiddleware(middlewares ...DBTXMiddleware) StoreOption {
+	return func(store *SQLStore) {
+		store.middlewares = append(store.middlewares, middlewares...)
+	}
+}
+
+// WithTxObserver is notified of every commit, rollback and retry
+func WithTxObserver(observer func(TxEvent)) StoreOption {
+	return func(store *SQLStore) {
+		store.txObservers = append(store.txObservers, observer)
+	}
+}
+
+// WithReplicas sends read-only queries outside transactions to the
+// replicas; transactions always run on the primary
+func WithReplicas(replicas *ReplicaSet) StoreOption {
+	return func(store *SQLStore) {
+		store.replicas = replicas
+	}
+}
+
+func NewStore(db *sql.DB, opts ...StoreOption) Store {
+	store := &SQLStore{db: db}
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	var conn DBTX = db
+	if store.replicas != nil {
+		conn = routingDBTX{primary: db, replicas: store.replicas}
+	}
+	store.Queries = New(store.wrap(conn))
+	return store
+}
+
+func (store *SQLStore) wrap(db DBTX) DBTX {
+	for i := len(store.middlewares) - 1; i >= 0; i-- {
+		db = store.middlewares[i](db)
+	}
+	return db
 }
 
-func NewStore(db *sql.DB) *Store {
-	return &Store{
-		db:      db,
-		Queries: New(db),
+// TxEvent is a transaction outcome reported to tx observers
+type TxEvent string
+
+const (
+	TxCommit   TxEvent = "commit"
+	TxRollback TxEvent = "rollback"
+	TxRetry    TxEvent = "retry"
+)
+
+func (store *SQLStore) notify(event TxEvent) {
+	for _, observer := range store.txObservers {
+		observer(event)
 	}
 }
 
+// maxTxAttempts bounds how often a transaction is re-run after a
+// serialization failure or deadlock
+const maxTxAttempts = 3
+
 // executes a function within a db transaction
+// fn is re-run from scratch when Postgres aborts the transaction because of
+// a serialization failure or deadlock, so it must not keep state between runs.
+// Postgres errors are translated into apperr domain errors on the way out
+
+func (store *SQLStore) executeTx(ctx context.Context, fn func(*Queries) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = store.runTx(ctx, fn)
+		if !isRetryable(err) || attempt == maxTxAttempts {
+			break
+		}
+
+		store.notify(TxRetry)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * 10 * time.Millisecond):
+		}
+	}
+	return apperr.FromDB(err)
+}
 
-func (store *Store) executeTx(ctx context.Context, fn func(*Queries) error) error {
+func (store *SQLStore) runTx(ctx context.Context, fn func(*Queries) error) error {
+	markWritten(ctx)
 	tx, err := store.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	if _, err := tx.ExecContext(ctx, setCurrentSite, currentSite(ctx), true); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("set current site err: %w", err)
+	}
 
-	q := New(tx)
+	q := New(store.wrap(tx))
 	err = fn(q)
 	if err != nil {
+		store.notify(TxRollback)
 		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("transaction err: %v, rollback err: %v", err, rbErr)
+			return fmt.Errorf("transaction err: %w, rollback err: %v", err, rbErr)
 		}
 		return err
 	}
-	return tx.Commit()
+
+	if err := tx.Commit(); err != nil {
+		store.notify(TxRollback)
+		return err
+	}
+	store.notify(TxCommit)
+	return nil
+}
+
+// isRetryable reports whether Postgres aborted the transaction in a way
+// that succeeds when simply run again
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code {
+	case "40001", // serialization_failure
+		"40P01": // deadlock_detected
+		return true
+	}
+	return false
 }
 
 // InitSetupConfigTx populates db tables with initial site-specific config data
 // Use user info to populate the `posts`, `pages`, and `meta` tables
-func (store *Store) InitSetupConfigTx(ctx context.Context, args InitSetupConfigTxParams) (InitSetupConfigTxResult, error) {
+func (store *SQLStore) InitSetupConfigTx(ctx context.Context, args InitSetupConfigTxParams) (InitSetupConfigTxResult, error) {
 	var result InitSetupConfigTxResult
 
 	err := store.executeTx(ctx, func(q *Queries) error {
+		result = InitSetupConfigTxResult{}
 		var err error
 
-		user, err := q.GetUsers(ctx, args.UserId)
+		user, err := siteAuthor(ctx, q, args.SiteID, args.UserId)
 		if err != nil {
-			return fmt.Errorf("get users err: %v", err)
+			return err
 		}
 		result.User = user
 
 		for _, postsParams := range args.InitialPosts {
+			postsParams.SiteID = args.SiteID
 			post, err := q.CreatePosts(ctx, postsParams)
 			if err != nil {
-				return fmt.Errorf("create posts err: %v", err)
+				return fmt.Errorf("create posts err: %w", err)
 			}
 			result.Posts = append(result.Posts, post)
 		}
 
 		for _, pageParams := range args.InitialPages {
+			pageParams.SiteID = args.SiteID
 			page, err := q.CreatePages(ctx, pageParams)
 			if err != nil {
-				return fmt.Errorf("create pages err: %v", err)
+				return fmt.Errorf("create pages err: %w", err)
 			}
 			result.Pages = append(result.Pages, page)
 		}
 
 		for _, metaParas := range args.InitialMeta {
+			metaParas.SiteID = args.SiteID
 			meta, err := q.CreateMeta(ctx, metaParas)
 			if err != nil {
-				return fmt.Errorf("create meta err: %v", err)
+				return fmt.Errorf("create meta err: %w", err)
 			}
 			result.Metas = append(result.Metas, meta)
 		}
@@ -84,38 +226,45 @@ func (store *Store) InitSetupConfigTx(ctx context.Context, args InitSetupConfigT
 
 // CreatePostsTx creates new posts content based on user information
 // It utilizes user info(users.id, users.username) to create the `posts` and its respective `meta`.
-func (store *Store) CreatePostsTx(ctx context.Context, args CreateContentTxParams) (CreateContentTxResult, error) {
+// Metas with neither a PageID nor a PostsID are the meta of the first post.
+func (store *SQLStore) CreatePostsTx(ctx context.Context, args CreateContentTxParams) (CreateContentTxResult, error) {
 	var result CreateContentTxResult
 
 	err := store.executeTx(ctx, func(q *Queries) error {
+		result = CreateContentTxResult{}
 		var err error
 
-		user, err := q.GetUsers(ctx, args.UserId)
+		user, err := siteAuthor(ctx, q, args.SiteID, args.UserId)
 		if err != nil {
-			return fmt.Errorf("get users err: %v", err)
+			return err
 		}
 		result.User = user
 
 		if args.PageId != nil {
-			page, err := q.GetPages(ctx, *args.PageId)
+			page, err := q.GetPages(ctx, GetPagesParams{SiteID: args.SiteID, ID: *args.PageId})
 			if err != nil {
-				return fmt.Errorf("get pages err: %v", err)
+				return fmt.Errorf("get pages err: %w", err)
 			}
 			result.PageId = &page
 		}
 
 		for _, postParams := range args.Posts {
+			postParams.SiteID = args.SiteID
 			post, err := q.CreatePosts(ctx, postParams)
 			if err != nil {
-				return fmt.Errorf("create posts err: %v", err)
+				return fmt.Errorf("create posts err: %w", err)
 			}
 			result.Posts = append(result.Posts, post)
 		}
 
 		for _, metaParas := range args.Metas {
+			metaParas.SiteID = args.SiteID
+			if !metaParas.PageID.Valid && !metaParas.PostsID.Valid && len(result.Posts) > 0 {
+				metaParas.PostsID = sql.NullInt64{Int64: result.Posts[0].ID, Valid: true}
+			}
 			meta, err := q.CreateMeta(ctx, metaParas)
 			if err != nil {
-				return fmt.Errorf("create meta err: %v", err)
+				return fmt.Errorf("create meta err: %w", err)
 			}
 			result.Metas = append(result.Metas, meta)
 		}
@@ -127,36 +276,47 @@ func (store *Store) CreatePostsTx(ctx context.Context, args CreateContentTxParam
 
 // CreatePageTx creates new pages content based on user information
 // It utilizes user info(users.id, users.username) to create the `page` and its respective `meta`.
-func (store *Store) CreatePageTx(ctx context.Context, args CreateContentTxParams) (CreateContentTxResult, error) {
+func (store *SQLStore) CreatePageTx(ctx context.Context, args CreateContentTxParams) (CreateContentTxResult, error) {
 	var result CreateContentTxResult
 
 	err := store.executeTx(ctx, func(q *Queries) error {
+		result = CreateContentTxResult{}
 		var err error
 
-		user, err := q.GetUsers(ctx, args.UserId)
+		user, err := siteAuthor(ctx, q, args.SiteID, args.UserId)
 		if err != nil {
-			return fmt.Errorf("get users err: %v", err)
+			return err
 		}
 		result.User = user
 
-		posts, err := q.GetPosts(ctx, *args.PostId)
-		if err != nil {
-			return fmt.Errorf("get posts err: %v", err)
+		if args.PostId != nil {
+			posts, err := q.GetPosts(ctx, GetPostsParams{SiteID: args.SiteID, ID: *args.PostId})
+			if err != nil {
+				return fmt.Errorf("get posts err: %w", err)
+			}
+			result.PostId = &posts
 		}
-		result.PostId = &posts
 
-		for _, pageParams := range args.Pages {
+		for i, pageParams := range args.Pages {
+			pageParams.SiteID = args.SiteID
 			page, err := q.CreatePages(ctx, pageParams)
 			if err != nil {
-				return fmt.Errorf("create pages err: %v", err)
+				return fmt.Errorf("create pages err: %w", err)
 			}
 			result.Pages = append(result.Pages, page)
+
+			options, err := createPageOptions(ctx, q, page.ID, pageOptionsAt(args.PageOptions, i))
+			if err != nil {
+				return err
+			}
+			result.PageOptions = append(result.PageOptions, options)
 		}
 
 		for _, metaParas := range args.Metas {
+			metaParas.SiteID = args.SiteID
 			meta, err := q.CreateMeta(ctx, metaParas)
 			if err != nil {
-				return fmt.Errorf("create meta err: %v", err)
+				return fmt.Errorf("create meta err: %w", err)
 			}
 			result.Metas = append(result.Metas, meta)
 		}
@@ -169,42 +329,48 @@ func (store *Store) CreatePageTx(ctx context.Context, args CreateContentTxParams
 
 // UpdatePostsTx updates existing content in the `posts` table and its respective `meta` table.
 // It utilizes user info (users.id, users.username) to update the content and its associated metadata.
-func (store *Store) UpdatePostsTx(ctx context.Context, args UpdateContentTxParams) (UpdateContentTxResult, error) {
+func (store *SQLStore) UpdatePostsTx(ctx context.Context, args UpdateContentTxParams) (UpdateContentTxResult, error) {
 	var result UpdateContentTxResult
 
 	err := store.executeTx(ctx, func(q *Queries) error {
+		result = UpdateContentTxResult{}
 		var err error
 
-		user, err := q.GetUsers(ctx, args.UserId)
+		user, err := siteAuthor(ctx, q, args.SiteID, args.UserId)
 		if err != nil {
-			return fmt.Errorf("get user err: %v", err)
+			return err
 		}
 		result.User = user
 
-		post, err := q.GetPosts(ctx, *args.PostId)
+		post, err := q.GetPosts(ctx, GetPostsParams{SiteID: args.SiteID, ID: *args.PostId})
 		if err != nil {
-			return fmt.Errorf("get post err: %v", err)
+			return fmt.Errorf("get post err: %w", err)
 		}
 		result.PostId = &post
 
-		meta, err := q.GetMetaByPostsIDForUpdate(ctx, sql.NullInt64{Int64: *args.MetaPostID, Valid: true})
+		meta, err := q.GetMetaByPostsIDForUpdate(ctx, GetMetaByPostsIDForUpdateParams{
+			SiteID:  args.SiteID,
+			PostsID: sql.NullInt64{Int64: *args.MetaPostID, Valid: true},
+		})
 		if err != nil {
-			return fmt.Errorf("get meta err: %v", err)
+			return fmt.Errorf("get meta err: %w", err)
 		}
 		result.MetaPostID = &meta
 
 		for _, postParams := range args.Posts {
+			postParams.SiteID = args.SiteID
 			post, err := q.UpdatePosts(ctx, postParams)
 			if err != nil {
-				return fmt.Errorf("update post err: %v", err)
+				return fmt.Errorf("update post err: %w", err)
 			}
 			result.Posts = append(result.Posts, post)
 		}
 
 		for _, metaParas := range args.Metas {
+			metaParas.SiteID = args.SiteID
 			meta, err := q.UpdateMeta(ctx, metaParas)
 			if err != nil {
-				return fmt.Errorf("update meta err: %v", err)
+				return fmt.Errorf("update meta err: %w", err)
 			}
 			result.Metas = append(result.Metas, meta)
 		}
@@ -215,42 +381,64 @@ func (store *Store) UpdatePostsTx(ctx context.Context, args UpdateContentTxParam
 
 // UpdatePageTx updates existing content in the `page` table and its respective `meta` table.
 // It utilizes user info (users.id, users.username) to update the content and its associated metadata.
-func (store *Store) UpdatePageTx(ctx context.Context, args UpdateContentTxParams) (UpdateContentTxResult, error) {
+func (store *SQLStore) UpdatePageTx(ctx context.Context, args UpdateContentTxParams) (UpdateContentTxResult, error) {
 	var result UpdateContentTxResult
 
 	err := store.executeTx(ctx, func(q *Queries) error {
+		result = UpdateContentTxResult{}
 		var err error
 
-		user, err := q.GetUsers(ctx, args.UserId)
+		user, err := siteAuthor(ctx, q, args.SiteID, args.UserId)
 		if err != nil {
-			return fmt.Errorf("get user err: %v", err)
+			return err
 		}
 		result.User = user
 
-		page, err := q.GetPages(ctx, *args.PageId)
+		page, err := q.GetPages(ctx, GetPagesParams{SiteID: args.SiteID, ID: *args.PageId})
 		if err != nil {
-			return fmt.Errorf("get pages err: %v", err)
+			return fmt.Errorf("get pages err: %w", err)
 		}
 		result.PageId = &page
 
-		meta, err := q.GetMetaByPageIDForUpdate(ctx, sql.NullInt64{Int64: *args.MetaPageID, Valid: true})
-		if err != nil {
-			return fmt.Errorf("get meta err: %v", err)
+		if args.MetaPageID != nil {
+			meta, err := q.GetMetaByPageIDForUpdate(ctx, GetMetaByPageIDForUpdateParams{
+				SiteID: args.SiteID,
+				PageID: sql.NullInt64{Int64: *args.MetaPageID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("get meta err: %w", err)
+			}
+			result.MetaPageID = &meta
 		}
-		result.MetaPageID = &meta
 
-		for _, pageParams := range args.Pages {
+		for i, pageParams := range args.Pages {
+			pageParams.SiteID = args.SiteID
 			page, err := q.UpdatePages(ctx, pageParams)
 			if err != nil {
-				return fmt.Errorf("update pages err: %v", err)
+				return fmt.Errorf("update pages err: %w", err)
 			}
 			result.Pages = append(result.Pages, page)
+
+			var options []PageOption
+			if replacement := pageOptionsAt(args.PageOptions, i); replacement != nil {
+				if err := q.DeletePageOptions(ctx, page.ID); err != nil {
+					return fmt.Errorf("delete page options err: %w", err)
+				}
+				options, err = createPageOptions(ctx, q, page.ID, replacement)
+			} else {
+				options, err = q.ListPageOptions(ctx, page.ID)
+			}
+			if err != nil {
+				return fmt.Errorf("page options err: %w", err)
+			}
+			result.PageOptions = append(result.PageOptions, options)
 		}
 
 		for _, metaParas := range args.Metas {
+			metaParas.SiteID = args.SiteID
 			meta, err := q.UpdateMeta(ctx, metaParas)
 			if err != nil {
-				return fmt.Errorf("update meta err: %v", err)
+				return fmt.Errorf("update meta err: %w", err)
 			}
 			result.Metas = append(result.Metas, meta)
 		}
@@ -261,24 +449,25 @@ func (store *Store) UpdatePageTx(ctx context.Context, args UpdateContentTxParams
 
 // DeletePostsTx deletes existing content in the `posts` table and its respective `meta` table.
 // It utilizes posts info (post.id) to delete posts content and its associated metadata.
-func (store *Store) DeletePostsTx(ctx context.Context, args DeleteContentTxParams) (DeleteContentTxResult, error) {
+func (store *SQLStore) DeletePostsTx(ctx context.Context, args DeleteContentTxParams) (DeleteContentTxResult, error) {
 	var result DeleteContentTxResult
 
 	err := store.executeTx(ctx, func(q *Queries) error {
+		result = DeleteContentTxResult{}
 		var err error
 
-		err = q.DeleteMetaByPostId(ctx, sql.NullInt64{
-			Int64: *args.PostId,
-			Valid: true,
+		err = q.DeleteMetaByPostId(ctx, DeleteMetaByPostIdParams{
+			SiteID:  args.SiteID,
+			PostsID: sql.NullInt64{Int64: *args.PostId, Valid: true},
 		})
 		if err != nil {
-			return fmt.Errorf("delete meta err: %v", err)
+			return fmt.Errorf("delete meta err: %w", err)
 		}
 		result.DeletedMeta = true
 
-		err = q.DeletePosts(ctx, *args.PostId)
+		err = q.DeletePosts(ctx, DeletePostsParams{SiteID: args.SiteID, ID: *args.PostId})
 		if err != nil {
-			return fmt.Errorf("delete posts err: %v", err)
+			return fmt.Errorf("delete posts err: %w", err)
 		}
 		result.DeletedPost = true
 
@@ -289,24 +478,36 @@ func (store *Store) DeletePostsTx(ctx context.Context, args DeleteContentTxParam
 
 // DeletePageTx deletes existing content in the `page` table and its respective `meta` table.
 // It utilizes posts info (post.id) to delete posts content and its associated metadata.
-func (store *Store) DeletePageTx(ctx context.Context, args DeleteContentTxParams) (DeleteContentTxResult, error) {
+func (store *SQLStore) DeletePageTx(ctx context.Context, args DeleteContentTxParams) (DeleteContentTxResult, error) {
 	var result DeleteContentTxResult
 
 	err := store.executeTx(ctx, func(q *Queries) error {
+		result = DeleteContentTxResult{}
 		var err error
 
-		err = q.DeleteMetaByPageId(ctx, sql.NullInt64{
-			Int64: *args.PageId,
-			Valid: true,
+		children, err := q.CountPageChildren(ctx, CountPageChildrenParams{
+			SiteID:   args.SiteID,
+			ParentID: sql.NullInt64{Int64: *args.PageId, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("count page children err: %w", err)
+		}
+		if children > 0 {
+			return apperr.Conflict("page has child pages, move or delete them first", nil)
+		}
+
+		err = q.DeleteMetaByPageId(ctx, DeleteMetaByPageIdParams{
+			SiteID: args.SiteID,
+			PageID: sql.NullInt64{Int64: *args.PageId, Valid: true},
 		})
 		if err != nil {
-			return fmt.Errorf("delete meta err: %v", err)
+			return fmt.Errorf("delete meta err: %w", err)
 		}
 		result.DeletedMeta = true
 
-		err = q.DeletePages(ctx, *args.PageId)
+		err = q.DeletePages(ctx, DeletePagesParams{SiteID: args.SiteID, ID: *args.PageId})
 		if err != nil {
-			return fmt.Errorf("delete page err: %v", err)
+			return fmt.Errorf("delete page err: %w", err)
 		}
 		result.DeletedPage = true
 
@@ -314,3 +515,436 @@ func (store *Store) DeletePageTx(ctx context.Context, args DeleteContentTxParams
 	})
 	return result, err
 }
+
+// MovePageTx moves a page to a new parent. Moves within a site run one at
+// a time, so the cycle check of the pages trigger sees every earlier move.
+func (store *SQLStore) MovePageTx(ctx context.Context, args MovePageTxParams) (MovePageTxResult, error) {
+	var result MovePageTxResult
+
+	err := store.executeTx(ctx, func(q *Queries) error {
+		result = MovePageTxResult{}
+
+		if err := q.LockPageTree(ctx, args.SiteID); err != nil {
+			return fmt.Errorf("lock page tree err: %w", err)
+		}
+
+		page, err := q.MovePage(ctx, MovePageParams{
+			SiteID:    args.SiteID,
+			ID:        args.PageID,
+			ParentID:  nullInt64(args.ParentID),
+			MenuOrder: args.MenuOrder,
+		})
+		if err != nil {
+			return fmt.Errorf("move page err: %w", err)
+		}
+		result.Page = page
+
+		return nil
+	})
+	return result, err
+}
+
+// ReorderPagesTx sets the menu_order of siblings to their position in
+// args.PageIDs, which must name each of them exactly once
+func (store *SQLStore) ReorderPagesTx(ctx context.Context, args ReorderPagesTxParams) (ReorderPagesTxResult, error) {
+	var result ReorderPagesTxResult
+
+	err := store.executeTx(ctx, func(q *Queries) error {
+		result = ReorderPagesTxResult{}
+
+		siblings, err := q.ListPageSiblings(ctx, ListPageSiblingsParams{
+			SiteID:   args.SiteID,
+			ParentID: nullInt64(args.ParentID),
+		})
+		if err != nil {
+			return fmt.Errorf("list page siblings err: %w", err)
+		}
+
+		byID := make(map[int64]Page, len(siblings))
+		for _, page := range siblings {
+			byID[page.ID] = page
+		}
+		if len(args.PageIDs) != len(siblings) {
+			return apperr.Validation(fmt.Sprintf("page_ids must list all %d sibling pages", len(siblings)), nil)
+		}
+
+		for i, id := range args.PageIDs {
+			page, ok := byID[id]
+			if !ok {
+				return apperr.Validation(fmt.Sprintf("page %d is not a sibling or is listed twice", id), nil)
+			}
+			delete(byID, id)
+
+			page.MenuOrder = int64(i)
+			if err := q.UpdatePageMenuOrder(ctx, UpdatePageMenuOrderParams{SiteID: args.SiteID, ID: id, MenuOrder: page.MenuOrder}); err != nil {
+				return fmt.Errorf("update page menu order err: %w", err)
+			}
+			result.Pages = append(result.Pages, page)
+		}
+
+		return nil
+	})
+	return result, err
+}
+
+// editPageComponents locks the page and hands its components, in order, to
+// edit. The list edit returns is renumbered from 0 and stored as the result.
+func (store *SQLStore) editPageComponents(ctx context.Context, siteID, pageID int64, edit func(q *Queries, components []PageComponent) ([]PageComponent, PageComponent, error)) (PageComponentsTxResult, error) {
+	var result PageComponentsTxResult
+
+	err := store.executeTx(ctx, func(q *Queries) error {
+		result = PageComponentsTxResult{}
+
+		if _, err := q.GetPagesForUpdate(ctx, GetPagesForUpdateParams{SiteID: siteID, ID: pageID}); err != nil {
+			return fmt.Errorf("get pages err: %w", err)
+		}
+
+		components, err := q.ListPageComponents(ctx, pageID)
+		if err != nil {
+			return fmt.Errorf("list page components err: %w", err)
+		}
+
+		components, result.Component, err = edit(q, components)
+		if err != nil {
+			return err
+		}
+
+		result.Components = make([]PageComponent, 0, len(components))
+		for i, component := range components {
+			if component.Position != int32(i) {
+				component, err = q.UpdatePageComponentPosition(ctx, UpdatePageComponentPositionParams{
+					ID:       component.ID,
+					Position: int32(i),
+				})
+				if err != nil {
+					return fmt.Errorf("update page component position err: %w", err)
+				}
+			}
+			if component.ID == result.Component.ID {
+				result.Component = component
+			}
+			result.Components = append(result.Components, component)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// indexOfComponent returns where id is in components, or an error if the
+// page has no such component
+func indexOfComponent(components []PageComponent, id int64) (int, error) {
+	for i, component := range components {
+		if component.ID == id {
+			return i, nil
+		}
+	}
+	return 0, apperr.NotFound("component not found on page", nil)
+}
+
+// clampPosition limits position to 0..last
+func clampPosition(position int32, last int) int {
+	return min(max(int(position), 0), last)
+}
+
+// AddPageComponentTx stores a component and shifts the ones at and after
+// its position down. Callers validate the value against the component type.
+func (store *SQLStore) AddPageComponentTx(ctx context.Context, args AddPageComponentTxParams) (PageComponentsTxResult, error) {
+	return store.editPageComponents(ctx, args.SiteID, args.PageID, func(q *Queries, components []PageComponent) ([]PageComponent, PageComponent, error) {
+		position := len(components)
+		if args.Position != nil {
+			position = clampPosition(*args.Position, len(components))
+		}
+
+		component, err := q.CreatePageComponent(ctx, CreatePageComponentParams{
+			PageID:   args.PageID,
+			Position: int32(position),
+			Type:     args.Type,
+			Value:    args.Value,
+		})
+		if err != nil {
+			return nil, component, fmt.Errorf("create page component err: %w", err)
+		}
+
+		components = slices.Insert(components, position, component)
+		return components, component, nil
+	})
+}
+
+// MovePageComponentTx moves a component to another position on its page
+func (store *SQLStore) MovePageComponentTx(ctx context.Context, args MovePageComponentTxParams) (PageComponentsTxResult, error) {
+	return store.editPageComponents(ctx, args.SiteID, args.PageID, func(q *Queries, components []PageComponent) ([]PageComponent, PageComponent, error) {
+		i, err := indexOfComponent(components, args.ComponentID)
+		if err != nil {
+			return nil, PageComponent{}, err
+		}
+
+		component := components[i]
+		components = slices.Delete(components, i, i+1)
+		components = slices.Insert(components, clampPosition(args.Position, len(components)), component)
+		return components, component, nil
+	})
+}
+
+// RemovePageComponentTx deletes a component and closes the gap it leaves
+func (store *SQLStore) RemovePageComponentTx(ctx context.Context, args RemovePageComponentTxParams) (PageComponentsTxResult, error) {
+	return store.editPageComponents(ctx, args.SiteID, args.PageID, func(q *Queries, components []PageComponent) ([]PageComponent, PageComponent, error) {
+		i, err := indexOfComponent(components, args.ComponentID)
+		if err != nil {
+			return nil, PageComponent{}, err
+		}
+
+		component := components[i]
+		if err := q.DeletePageComponent(ctx, component.ID); err != nil {
+			return nil, component, fmt.Errorf("delete page component err: %w", err)
+		}
+		return slices.Delete(components, i, i+1), component, nil
+	})
+}
+
+// CreateSiteUserTx creates a user and adds them to a site
+func (store *SQLStore) CreateSiteUserTx(ctx context.Context, args CreateSiteUserTxParams) (CreateSiteUserTxResult, error) {
+	var result CreateSiteUserTxResult
+
+	err := store.executeTx(ctx, func(q *Queries) error {
+		result = CreateSiteUserTxResult{}
+		var err error
+
+		result.User, err = q.CreateUsers(ctx, args.User)
+		if err != nil {
+			return fmt.Errorf("create users err: %w", err)
+		}
+
+		result.Member, err = q.UpsertSiteMember(ctx, UpsertSiteMemberParams{
+			SiteID: args.SiteID,
+			UserID: result.User.ID,
+			Role:   args.Role,
+		})
+		if err != nil {
+			return fmt.Errorf("add site member err: %w", err)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// translationGroup returns the group of content with locale, or starts
+// one with sourceLocale when groupID is unset
+func translationGroup(ctx context.Context, q *Queries, args AddTranslationTxParams, groupID sql.NullInt64, locale sql.NullString) (sql.NullInt64, sql.NullString, error) {
+	if args.ID == args.SourceID {
+		return groupID, locale, apperr.Validation("content can not be its own translation", nil)
+	}
+	if groupID.Valid {
+		return groupID, locale, nil
+	}
+
+	if !locale.Valid {
+		if args.SourceLocale == "" {
+			return groupID, locale, apperr.Validation("source_locale is required, the source has no locale", nil)
+		}
+		locale = sql.NullString{String: args.SourceLocale, Valid: true}
+	}
+	group, err := q.CreateTranslationGroup(ctx, args.SiteID)
+	if err != nil {
+		return groupID, locale, fmt.Errorf("create translation group err: %w", err)
+	}
+	return sql.NullInt64{Int64: group.ID, Valid: true}, locale, nil
+}
+
+// AddPageTranslationTx links a page to the translation group of another.
+// A locale already taken in the group is a conflict.
+func (store *SQLStore) AddPageTranslationTx(ctx context.Context, args AddTranslationTxParams) (PageTranslationsTxResult, error) {
+	var result PageTranslationsTxResult
+
+	err := store.executeTx(ctx, func(q *Queries) error {
+		result = PageTranslationsTxResult{}
+
+		source, err := q.GetPagesForUpdate(ctx, GetPagesForUpdateParams{SiteID: args.SiteID, ID: args.SourceID})
+		if err != nil {
+			return fmt.Errorf("get pages err: %w", err)
+		}
+
+		group, locale, err := translationGroup(ctx, q, args, source.TranslationGroupID, source.Locale)
+		if err != nil {
+			return err
+		}
+		if group != source.TranslationGroupID || locale != source.Locale {
+			_, err := q.SetPageTranslation(ctx, SetPageTranslationParams{
+				SiteID:             args.SiteID,
+				ID:                 source.ID,
+				Locale:             locale,
+				TranslationGroupID: group,
+			})
+			if err != nil {
+				return fmt.Errorf("set page translation err: %w", err)
+			}
+		}
+
+		_, err = q.SetPageTranslation(ctx, SetPageTranslationParams{
+			SiteID:             args.SiteID,
+			ID:                 args.ID,
+			Locale:             sql.NullString{String: args.Locale, Valid: true},
+			TranslationGroupID: group,
+		})
+		if err != nil {
+			return fmt.Errorf("set page translation err: %w", err)
+		}
+
+		result.Pages, err = q.ListPageTranslations(ctx, ListPageTranslationsParams{SiteID: args.SiteID, TranslationGroupID: group})
+		if err != nil {
+			return fmt.Errorf("list page translations err: %w", err)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// AddPostTranslationTx links a post to the translation group of another.
+// A locale already taken in the group is a conflict.
+func (store *SQLStore) AddPostTranslationTx(ctx context.Context, args AddTranslationTxParams) (PostTranslationsTxResult, error) {
+	var result PostTranslationsTxResult
+
+	err := store.executeTx(ctx, func(q *Queries) error {
+		result = PostTranslationsTxResult{}
+
+		source, err := q.GetPostsForUpdate(ctx, GetPostsForUpdateParams{SiteID: args.SiteID, ID: args.SourceID})
+		if err != nil {
+			return fmt.Errorf("get posts err: %w", err)
+		}
+
+		group, locale, err := translationGroup(ctx, q, args, source.TranslationGroupID, source.Locale)
+		if err != nil {
+			return err
+		}
+		if group != source.TranslationGroupID || locale != source.Locale {
+			_, err := q.SetPostTranslation(ctx, SetPostTranslationParams{
+				SiteID:             args.SiteID,
+				ID:                 source.ID,
+				Locale:             locale,
+				TranslationGroupID: group,
+			})
+			if err != nil {
+				return fmt.Errorf("set post translation err: %w", err)
+			}
+		}
+
+		_, err = q.SetPostTranslation(ctx, SetPostTranslationParams{
+			SiteID:             args.SiteID,
+			ID:                 args.ID,
+			Locale:             sql.NullString{String: args.Locale, Valid: true},
+			TranslationGroupID: group,
+		})
+		if err != nil {
+			return fmt.Errorf("set post translation err: %w", err)
+		}
+
+		result.Posts, err = q.ListPostTranslations(ctx, ListPostTranslationsParams{SiteID: args.SiteID, TranslationGroupID: group})
+		if err != nil {
+			return fmt.Errorf("list post translations err: %w", err)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// CreateMediaTx adds a file uploaded by a site author, unless the site has
+// a file with the same checksum already
+func (store *SQLStore) CreateMediaTx(ctx context.Context, args CreateMediaParams) (CreateMediaTxResult, error) {
+	var result CreateMediaTxResult
+
+	err := store.executeTx(ctx, func(q *Queries) error {
+		result = CreateMediaTxResult{}
+
+		if _, err := siteAuthor(ctx, q, args.SiteID, args.UploaderID); err != nil {
+			return err
+		}
+
+		media, err := q.CreateMedia(ctx, args)
+		if err == nil {
+			result = CreateMediaTxResult{Media: media, Created: true}
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("create media err: %w", err)
+		}
+
+		result.Media, err = q.GetMediaByChecksum(ctx, GetMediaByChecksumParams{SiteID: args.SiteID, Checksum: args.Checksum})
+		if err != nil {
+			return fmt.Errorf("get media by checksum err: %w", err)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// DeleteMediaTx deletes a media item no post or meta refers to and returns
+// it, so its file can be removed from storage
+func (store *SQLStore) DeleteMediaTx(ctx context.Context, args DeleteMediaParams) (Media, error) {
+	var result Media
+
+	err := store.executeTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.DeleteMedia(ctx, args)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" { // foreign_key_violation
+			return apperr.Conflict("media is in use", err)
+		}
+		if err != nil {
+			return fmt.Errorf("delete media err: %w", err)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// siteAuthor returns the user if they may write content on the site, i.e.
+// they are a member and not a viewer. A non-member is sql.ErrNoRows.
+func siteAuthor(ctx context.Context, q *Queries, siteID, userID int64) (User, error) {
+	member, err := q.GetSiteMember(ctx, GetSiteMemberParams{SiteID: siteID, UserID: userID})
+	if err != nil {
+		return User{}, fmt.Errorf("get site member err: %w", err)
+	}
+	if member.Role == SiteRoleViewer {
+		return User{}, apperr.Forbidden("viewers can not write content", nil)
+	}
+
+	user, err := q.GetUsers(ctx, userID)
+	if err != nil {
+		return User{}, fmt.Errorf("get users err: %w", err)
+	}
+	return user, nil
+}
+
+func nullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+// pageOptionsAt returns the options given for the i-th page, or nil
+func pageOptionsAt(options [][]PageOptionParams, i int) []PageOptionParams {
+	if i < len(options) {
+		return options[i]
+	}
+	return nil
+}
+
+// createPageOptions stores options for the page in list order
+func createPageOptions(ctx context.Context, q *Queries, pageID int64, options []PageOptionParams) ([]PageOption, error) {
+	created := make([]PageOption, 0, len(options))
+	for i, option := range options {
+		o, err := q.CreatePageOption(ctx, CreatePageOptionParams{
+			PageID:   pageID,
+			Position: int32(i),
+			Name:     option.Name,
+			Type:     option.Type,
+			Value:    option.Value,
+			Required: option.Required,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create page option %q err: %w", option.Name, err)
+		}
+		created = append(created, o)
+	}
+	return created, nil
+}
@@ -1,73 +1,74 @@
-package frog_blossom_db
+package frog_blossom_db_test
 
 import (
 	"context"
 	"database/sql"
-	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
 )
 
+func onSite(siteID int64) func(*db.CreatePagesParams) {
+	return func(p *db.CreatePagesParams) { p.SiteID = siteID }
+}
+
+func postOnSite(siteID int64) func(*db.CreatePostsParams) {
+	return func(p *db.CreatePostsParams) { p.SiteID = siteID }
+}
+
 func TestInitSetupConfigTx(t *testing.T) {
 	// Arrange
-	store := NewStore(testDB)
+	store := db.NewStore(testDB)
 
-	newUser := createRandomUser(t)
-	newUser2 := createRandomUser(t)
+	f := fixtures.For(t, testQueries)
+	siteID := f.Site().ID
+	newUser := f.SiteUser(siteID)
+	newUser2 := f.SiteUser(siteID)
 
-	newPages := createRandomPage(t)
-	newPages2 := createRandomPage(t)
+	newPages := f.Page(onSite(siteID))
+	newPages2 := f.Page(onSite(siteID))
 
-	newPosts := createRandomPosts(t)
-	newPosts2 := createRandomPosts(t)
+	newPosts := f.Post(postOnSite(siteID))
+	newPosts2 := f.Post(postOnSite(siteID))
 
 	n := 5
 
 	errs := make(chan error)
-	results := make(chan InitSetupConfigTxResult)
+	results := make(chan db.InitSetupConfigTxResult)
 
 	// Act
 	for i := 0; i < n; i++ {
 		go func() {
-			result, err := store.InitSetupConfigTx(context.Background(), InitSetupConfigTxParams{
+			result, err := store.InitSetupConfigTx(context.Background(), db.InitSetupConfigTxParams{
+				SiteID:   siteID,
 				UserId:   newUser.ID,
 				Username: newUser.Username,
 				Email:    newUser.Email,
 				UserURl:  newUser.UserUrl.String,
-				InitialPages: []CreatePagesParams{
+				InitialPages: []db.CreatePagesParams{
 					{
-						Domain:         newPages.Domain,
 						AuthorID:       newUser.ID,
 						PageAuthor:     newUser.Username,
 						Title:          newPages.Title,
 						Url:            newPages.Url,
 						MenuOrder:      newPages.MenuOrder,
-						ComponentType:  newPages.ComponentType,
-						ComponentValue: newPages.ComponentValue,
 						PageIdentifier: newPages.PageIdentifier,
-						OptionID:       newPages.OptionID,
-						OptionName:     newPages.OptionName,
-						OptionValue:    newPages.OptionValue,
-						OptionRequired: false,
 					},
 					{
-						Domain:         newPages.Domain,
 						AuthorID:       newUser2.ID,
 						PageAuthor:     newUser2.Username,
 						Title:          newPages.Title,
 						Url:            newPages.Url,
 						MenuOrder:      newPages.MenuOrder,
-						ComponentType:  newPages.ComponentType,
-						ComponentValue: newPages.ComponentValue,
 						PageIdentifier: newPages.PageIdentifier,
-						OptionID:       newPages.OptionID,
-						OptionName:     newPages.OptionName,
-						OptionValue:    newPages.OptionValue,
-						OptionRequired: false,
 					},
 				},
 
-				InitialPosts: []CreatePostsParams{
+				InitialPosts: []db.CreatePostsParams{
 					{
 						Title:        newPosts.Title,
 						Content:      newPosts.Content,
@@ -92,7 +93,7 @@ func TestInitSetupConfigTx(t *testing.T) {
 						UpdatedBy:    newUser.Username,
 					},
 				},
-				InitialMeta: []CreateMetaParams{
+				InitialMeta: []db.CreateMetaParams{
 					{
 						PageID: sql.NullInt64{
 							Int64: newPages.ID, Valid: true,
@@ -115,10 +116,8 @@ func TestInitSetupConfigTx(t *testing.T) {
 						Locale: sql.NullString{
 							String: "en_US", Valid: true,
 						},
-						PageAmount:   100,
-						SiteLanguage: sql.NullString{String: "en", Valid: true},
-						MetaKey:      "sample_key_1",
-						MetaValue:    "sample_value_1",
+						MetaKey:   "sample_key_1",
+						MetaValue: "sample_value_1",
 					},
 					{
 						PageID: sql.NullInt64{
@@ -142,10 +141,8 @@ func TestInitSetupConfigTx(t *testing.T) {
 						Locale: sql.NullString{
 							String: "fr_FR", Valid: true,
 						},
-						PageAmount:   200,
-						SiteLanguage: sql.NullString{String: "fr", Valid: true},
-						MetaKey:      "sample_key_2",
-						MetaValue:    "sample_value_2",
+						MetaKey:   "sample_key_2",
+						MetaValue: "sample_value_2",
 					},
 				},
 			})
@@ -177,22 +174,16 @@ func TestInitSetupConfigTx(t *testing.T) {
 		require.NotEmpty(t, pages)
 
 		for _, page := range pages {
-			storePage, err := store.GetPages(context.Background(), page.ID)
+			storePage, err := store.GetPages(context.Background(), db.GetPagesParams{SiteID: siteID, ID: page.ID})
 			require.NoError(t, err)
 			require.NotEmpty(t, storePage)
 			require.Equal(t, page.ID, storePage.ID)
-			require.Equal(t, page.Domain, storePage.Domain)
+			require.Equal(t, siteID, storePage.SiteID)
 			require.Equal(t, page.AuthorID, storePage.AuthorID)
 			require.Equal(t, page.PageIdentifier, storePage.PageIdentifier)
 			require.Equal(t, page.Title, storePage.Title)
 			require.Equal(t, page.Url, storePage.Url)
 			require.Equal(t, page.MenuOrder, storePage.MenuOrder)
-			require.Equal(t, page.ComponentType, storePage.ComponentType)
-			require.Equal(t, page.ComponentValue, storePage.ComponentValue)
-			require.Equal(t, page.OptionID, storePage.OptionID)
-			require.Equal(t, page.OptionName, storePage.OptionName)
-			require.Equal(t, page.OptionValue, storePage.OptionValue)
-			require.Equal(t, page.OptionRequired, storePage.OptionRequired)
 
 		}
 
@@ -200,7 +191,7 @@ func TestInitSetupConfigTx(t *testing.T) {
 		require.NotEmpty(t, metas)
 
 		for _, meta := range metas {
-			storeMeta, err := store.GetMeta(context.Background(), meta.ID)
+			storeMeta, err := store.GetMeta(context.Background(), db.GetMetaParams{SiteID: siteID, ID: meta.ID})
 			require.NoError(t, err)
 			require.NotEmpty(t, storeMeta)
 			require.Equal(t, meta.ID, storeMeta.ID)
@@ -211,8 +202,6 @@ func TestInitSetupConfigTx(t *testing.T) {
 			require.Equal(t, meta.MetaRobots, storeMeta.MetaRobots)
 			require.Equal(t, meta.MetaOgImage, storeMeta.MetaOgImage)
 			require.Equal(t, meta.Locale, storeMeta.Locale)
-			require.Equal(t, meta.PageAmount, storeMeta.PageAmount)
-			require.Equal(t, meta.SiteLanguage, storeMeta.SiteLanguage)
 			require.Equal(t, meta.MetaKey, storeMeta.MetaKey)
 			require.Equal(t, meta.MetaValue, storeMeta.MetaValue)
 		}
@@ -221,25 +210,28 @@ func TestInitSetupConfigTx(t *testing.T) {
 
 func TestCreatePostsTx(t *testing.T) {
 	// Arrange
-	store := NewStore(testDB)
+	store := db.NewStore(testDB)
 
-	newUser := createRandomUser(t)
-	newPage := createRandomPage(t)
-	newPosts := createRandomPosts(t)
+	f := fixtures.For(t, testQueries)
+	siteID := f.Site().ID
+	newUser := f.SiteUser(siteID)
+	newPage := f.Page(onSite(siteID))
+	newPosts := f.Post(postOnSite(siteID))
 
 	n := 5
 
 	errs := make(chan error)
-	results := make(chan CreateContentTxResult)
+	results := make(chan db.CreateContentTxResult)
 
 	// Act
 	for i := 0; i < n; i++ {
 		go func() {
-			result, err := store.CreatePostsTx(context.Background(), CreateContentTxParams{
+			result, err := store.CreatePostsTx(context.Background(), db.CreateContentTxParams{
+				SiteID:   siteID,
 				UserId:   newUser.ID,
 				Username: newUser.Username,
 				PageId:   &newPage.ID,
-				Posts: []CreatePostsParams{
+				Posts: []db.CreatePostsParams{
 					{
 						Title:        newPosts.Title,
 						Content:      newPosts.Content,
@@ -264,7 +256,7 @@ func TestCreatePostsTx(t *testing.T) {
 						UpdatedBy:    newUser.Username,
 					},
 				},
-				Metas: []CreateMetaParams{
+				Metas: []db.CreateMetaParams{
 					{
 						PageID: sql.NullInt64{
 							Int64: newPage.ID, Valid: true,
@@ -287,10 +279,8 @@ func TestCreatePostsTx(t *testing.T) {
 						Locale: sql.NullString{
 							String: "en_US", Valid: true,
 						},
-						PageAmount:   100,
-						SiteLanguage: sql.NullString{String: "en", Valid: true},
-						MetaKey:      "sample_key_1",
-						MetaValue:    "sample_value_1",
+						MetaKey:   "sample_key_1",
+						MetaValue: "sample_value_1",
 					},
 					{
 						PageID: sql.NullInt64{
@@ -314,10 +304,8 @@ func TestCreatePostsTx(t *testing.T) {
 						Locale: sql.NullString{
 							String: "fr_FR", Valid: true,
 						},
-						PageAmount:   200,
-						SiteLanguage: sql.NullString{String: "fr", Valid: true},
-						MetaKey:      "sample_key_2",
-						MetaValue:    "sample_value_2",
+						MetaKey:   "sample_key_2",
+						MetaValue: "sample_value_2",
 					},
 				},
 			})
@@ -347,14 +335,14 @@ func TestCreatePostsTx(t *testing.T) {
 		require.NotEmpty(t, page)
 		require.Equal(t, newPage.ID, page.ID)
 
-		_, err = store.GetPages(context.Background(), page.ID)
+		_, err = store.GetPages(context.Background(), db.GetPagesParams{SiteID: siteID, ID: page.ID})
 		require.NoError(t, err)
 
 		posts := result.Posts
 		require.NotEmpty(t, posts)
 
 		for _, post := range posts {
-			storePosts, err := store.GetPosts(context.Background(), post.ID)
+			storePosts, err := store.GetPosts(context.Background(), db.GetPostsParams{SiteID: siteID, ID: post.ID})
 			require.NoError(t, err)
 			require.NotEmpty(t, storePosts)
 			require.Equal(t, post.ID, storePosts.ID)
@@ -375,7 +363,7 @@ func TestCreatePostsTx(t *testing.T) {
 		require.NotEmpty(t, meta)
 
 		for _, meta := range meta {
-			storeMeta, err := store.GetMeta(context.Background(), meta.ID)
+			storeMeta, err := store.GetMeta(context.Background(), db.GetMetaParams{SiteID: siteID, ID: meta.ID})
 			require.NoError(t, err)
 			require.NotEmpty(t, storeMeta)
 			require.Equal(t, meta.PageID, storeMeta.PageID)
@@ -385,8 +373,6 @@ func TestCreatePostsTx(t *testing.T) {
 			require.Equal(t, meta.MetaRobots, storeMeta.MetaRobots)
 			require.Equal(t, meta.MetaOgImage, storeMeta.MetaOgImage)
 			require.Equal(t, meta.Locale, storeMeta.Locale)
-			require.Equal(t, meta.PageAmount, storeMeta.PageAmount)
-			require.Equal(t, meta.SiteLanguage, storeMeta.SiteLanguage)
 			require.Equal(t, meta.MetaKey, storeMeta.MetaKey)
 			require.Equal(t, meta.MetaValue, storeMeta.MetaValue)
 		}
@@ -395,57 +381,46 @@ func TestCreatePostsTx(t *testing.T) {
 
 func TestCreatePageTx(t *testing.T) {
 	// Arrange
-	store := NewStore(testDB)
+	store := db.NewStore(testDB)
 
-	newUser := createRandomUser(t)
-	newPage := createRandomPage(t)
-	newPost := createRandomPosts(t)
+	f := fixtures.For(t, testQueries)
+	siteID := f.Site().ID
+	newUser := f.SiteUser(siteID)
+	newPage := f.Page(onSite(siteID))
+	newPost := f.Post(postOnSite(siteID))
 
 	n := 5
 
 	errs := make(chan error)
-	results := make(chan CreateContentTxResult)
+	results := make(chan db.CreateContentTxResult)
 
 	// Act
 	for i := 0; i < n; i++ {
 		go func() {
-			result, err := store.CreatePageTx(context.Background(), CreateContentTxParams{
+			result, err := store.CreatePageTx(context.Background(), db.CreateContentTxParams{
+				SiteID:   siteID,
 				UserId:   newUser.ID,
 				Username: newUser.Username,
 				PostId:   &newPost.ID,
-				Pages: []CreatePagesParams{
+				Pages: []db.CreatePagesParams{
 					{
-						Domain:         newPage.Domain,
 						AuthorID:       newUser.ID,
 						PageAuthor:     newUser.Username,
 						Title:          newPage.Title,
 						Url:            newPage.Url,
 						MenuOrder:      newPage.MenuOrder,
-						ComponentType:  newPage.ComponentType,
-						ComponentValue: newPage.ComponentValue,
 						PageIdentifier: newPage.PageIdentifier,
-						OptionID:       newPage.OptionID,
-						OptionName:     newPage.OptionName,
-						OptionValue:    newPage.OptionValue,
-						OptionRequired: false,
 					},
 					{
-						Domain:         newPage.Domain,
 						AuthorID:       newUser.ID,
 						PageAuthor:     newUser.Username,
 						Title:          newPage.Title,
 						Url:            newPage.Url,
 						MenuOrder:      newPage.MenuOrder,
-						ComponentType:  newPage.ComponentType,
-						ComponentValue: newPage.ComponentValue,
 						PageIdentifier: newPage.PageIdentifier,
-						OptionID:       newPage.OptionID,
-						OptionName:     newPage.OptionName,
-						OptionValue:    newPage.OptionValue,
-						OptionRequired: false,
 					},
 				},
-				Metas: []CreateMetaParams{
+				Metas: []db.CreateMetaParams{
 					{
 						PageID: sql.NullInt64{
 							Int64: newPage.ID, Valid: true,
@@ -468,10 +443,8 @@ func TestCreatePageTx(t *testing.T) {
 						Locale: sql.NullString{
 							String: "en_US", Valid: true,
 						},
-						PageAmount:   100,
-						SiteLanguage: sql.NullString{String: "en", Valid: true},
-						MetaKey:      "sample_key_1",
-						MetaValue:    "sample_value_1",
+						MetaKey:   "sample_key_1",
+						MetaValue: "sample_value_1",
 					},
 					{
 						PageID: sql.NullInt64{
@@ -495,10 +468,8 @@ func TestCreatePageTx(t *testing.T) {
 						Locale: sql.NullString{
 							String: "fr_FR", Valid: true,
 						},
-						PageAmount:   200,
-						SiteLanguage: sql.NullString{String: "fr", Valid: true},
-						MetaKey:      "sample_key_2",
-						MetaValue:    "sample_value_2",
+						MetaKey:   "sample_key_2",
+						MetaValue: "sample_value_2",
 					},
 				},
 			})
@@ -528,28 +499,30 @@ func TestCreatePageTx(t *testing.T) {
 
 func TestUpdatePostsTx(t *testing.T) {
 	// Arrange
-	store := NewStore(testDB)
+	store := db.NewStore(testDB)
 
-	newUser := createRandomUser(t)
 	newMeta := createRandomMeta(t)
+	siteID := newMeta.SiteID
+	newUser := fixtures.For(t, testQueries).SiteUser(siteID)
 	now := time.Now().UTC()
 
 	n := 5
 
 	errs := make(chan error)
-	results := make(chan UpdateContentTxResult)
+	results := make(chan db.UpdateContentTxResult)
 
 	// Act
 	for i := 0; i < n; i++ {
 		go func() {
 
-			postMeta, err := store.GetMetaByPostsIDForUpdate(context.Background(), sql.NullInt64{
-				Int64: newMeta.PostsID.Int64,
-				Valid: true,
+			postMeta, err := store.GetMetaByPostsIDForUpdate(context.Background(), db.GetMetaByPostsIDForUpdateParams{
+				SiteID:  siteID,
+				PostsID: sql.NullInt64{Int64: newMeta.PostsID.Int64, Valid: true},
 			})
 			require.NoError(t, err)
 
-			result, err := store.UpdatePostsTx(context.Background(), UpdateContentTxParams{
+			result, err := store.UpdatePostsTx(context.Background(), db.UpdateContentTxParams{
+				SiteID:     siteID,
 				UserId:     newUser.ID,
 				Username:   newUser.Username,
 				PageId:     nil,
@@ -557,7 +530,7 @@ func TestUpdatePostsTx(t *testing.T) {
 				MetaPageID: nil,
 				MetaPostID: &postMeta.PostsID.Int64,
 				Pages:      nil,
-				Posts: []UpdatePostsParams{
+				Posts: []db.UpdatePostsParams{
 					{
 						ID:           postMeta.PostsID.Int64,
 						Title:        "Lorem ipsum dolor sit amet",
@@ -565,7 +538,7 @@ func TestUpdatePostsTx(t *testing.T) {
 						AuthorID:     newUser.ID,
 						Url:          "https://example.com",
 						UpdatedAt:    now,
-						Status:       "admin",
+						Status:       db.PostStatusPublish,
 						PublishedAt:  now,
 						EditedAt:     now,
 						PostAuthor:   newUser.Username,
@@ -574,7 +547,7 @@ func TestUpdatePostsTx(t *testing.T) {
 						UpdatedBy:    newUser.Username,
 					},
 				},
-				Metas: []UpdateMetaParams{
+				Metas: []db.UpdateMetaParams{
 					{
 						ID:              postMeta.ID,
 						PageID:          sql.NullInt64{Int64: 0, Valid: false},
@@ -584,12 +557,8 @@ func TestUpdatePostsTx(t *testing.T) {
 						MetaRobots:      sql.NullString{String: "index, follow", Valid: true},
 						MetaOgImage:     sql.NullString{String: "https://example.com/image.jpg", Valid: true},
 						Locale:          sql.NullString{String: "ja_JP", Valid: true},
-						PageAmount:      3,
-						SiteLanguage: sql.NullString{
-							String: "ja", Valid: true,
-						},
-						MetaKey:   "_thumbnail_id",
-						MetaValue: "12345",
+						MetaKey:         "_thumbnail_id",
+						MetaValue:       "12345",
 					},
 				},
 			})
@@ -619,7 +588,7 @@ func TestUpdatePostsTx(t *testing.T) {
 		require.NotEmpty(t, posts)
 
 		for _, post := range posts {
-			storePosts, err := store.GetPosts(context.Background(), post.ID)
+			storePosts, err := store.GetPosts(context.Background(), db.GetPostsParams{SiteID: siteID, ID: post.ID})
 			require.NoError(t, err)
 			require.NotEmpty(t, storePosts)
 			require.Equal(t, post.ID, storePosts.ID)
@@ -640,7 +609,7 @@ func TestUpdatePostsTx(t *testing.T) {
 		require.NotEmpty(t, metas)
 
 		for _, meta := range metas {
-			storeMeta, err := store.GetMeta(context.Background(), meta.ID)
+			storeMeta, err := store.GetMeta(context.Background(), db.GetMetaParams{SiteID: siteID, ID: meta.ID})
 			require.NoError(t, err)
 			require.NotEmpty(t, storeMeta)
 			require.Equal(t, meta.ID, storeMeta.ID)
@@ -650,8 +619,6 @@ func TestUpdatePostsTx(t *testing.T) {
 			require.Equal(t, meta.MetaRobots, storeMeta.MetaRobots)
 			require.Equal(t, meta.MetaOgImage, storeMeta.MetaOgImage)
 			require.Equal(t, meta.Locale, storeMeta.Locale)
-			require.Equal(t, meta.PageAmount, storeMeta.PageAmount)
-			require.Equal(t, meta.SiteLanguage, storeMeta.SiteLanguage)
 			require.Equal(t, meta.MetaKey, storeMeta.MetaKey)
 			require.Equal(t, meta.MetaValue, storeMeta.MetaValue)
 		}
@@ -660,53 +627,48 @@ func TestUpdatePostsTx(t *testing.T) {
 
 func TestUpdatePageTx(t *testing.T) {
 	// Arrange
-	store := NewStore(testDB)
+	store := db.NewStore(testDB)
 
-	newUser := createRandomUser(t)
 	newMeta := createRandomMeta(t)
+	siteID := newMeta.SiteID
+	newUser := fixtures.For(t, testQueries).SiteUser(siteID)
 
 	n := 5
 
 	errs := make(chan error)
-	results := make(chan UpdateContentTxResult)
+	results := make(chan db.UpdateContentTxResult)
 
 	// Act
 	for i := 0; i < n; i++ {
 		go func() {
 
-			postMeta, err := store.GetMetaByPageIDForUpdate(context.Background(), sql.NullInt64{
-				Int64: newMeta.PageID.Int64,
-				Valid: true,
+			postMeta, err := store.GetMetaByPageIDForUpdate(context.Background(), db.GetMetaByPageIDForUpdateParams{
+				SiteID: siteID,
+				PageID: sql.NullInt64{Int64: newMeta.PageID.Int64, Valid: true},
 			})
 			require.NoError(t, err)
 
-			result, err := store.UpdatePageTx(context.Background(), UpdateContentTxParams{
+			result, err := store.UpdatePageTx(context.Background(), db.UpdateContentTxParams{
+				SiteID:     siteID,
 				UserId:     newUser.ID,
 				Username:   newUser.Username,
 				PageId:     &postMeta.PageID.Int64,
 				PostId:     nil,
 				MetaPageID: &postMeta.PageID.Int64,
 				MetaPostID: nil,
-				Pages: []UpdatePagesParams{
+				Pages: []db.UpdatePagesParams{
 					{
 						ID:             postMeta.PageID.Int64,
-						Domain:         "example.com",
 						AuthorID:       newUser.ID,
 						PageAuthor:     newUser.Username,
 						Title:          "Homepage",
 						Url:            "/home",
 						MenuOrder:      1,
-						ComponentType:  "Text",
-						ComponentValue: "Welcome to our website!",
 						PageIdentifier: "home",
-						OptionID:       98765,
-						OptionName:     "site_title",
-						OptionValue:    "My Website",
-						OptionRequired: true,
 					},
 				},
 				Posts: nil,
-				Metas: []UpdateMetaParams{
+				Metas: []db.UpdateMetaParams{
 					{
 						ID:              postMeta.ID,
 						PageID:          sql.NullInt64{Int64: postMeta.PageID.Int64, Valid: true},
@@ -716,12 +678,8 @@ func TestUpdatePageTx(t *testing.T) {
 						MetaRobots:      sql.NullString{String: "index, follow", Valid: true},
 						MetaOgImage:     sql.NullString{String: "https://example.com/image.jpg", Valid: true},
 						Locale:          sql.NullString{String: "ja_JP", Valid: true},
-						PageAmount:      3,
-						SiteLanguage: sql.NullString{
-							String: "ja", Valid: true,
-						},
-						MetaKey:   "_thumbnail_id",
-						MetaValue: "12345",
+						MetaKey:         "_thumbnail_id",
+						MetaValue:       "12345",
 					},
 				},
 			})
@@ -751,30 +709,24 @@ func TestUpdatePageTx(t *testing.T) {
 		require.NotEmpty(t, pages)
 
 		for _, page := range pages {
-			storePage, err := store.GetPages(context.Background(), page.ID)
+			storePage, err := store.GetPages(context.Background(), db.GetPagesParams{SiteID: siteID, ID: page.ID})
 			require.NoError(t, err)
 			require.NotEmpty(t, storePage)
 			require.Equal(t, storePage.ID, page.ID)
-			require.Equal(t, storePage.Domain, page.Domain)
+			require.Equal(t, siteID, storePage.SiteID)
 			require.Equal(t, storePage.AuthorID, page.AuthorID)
 			require.Equal(t, storePage.PageAuthor, page.PageAuthor)
 			require.Equal(t, storePage.Title, page.Title)
 			require.Equal(t, storePage.Url, page.Url)
 			require.Equal(t, storePage.MenuOrder, page.MenuOrder)
-			require.Equal(t, storePage.ComponentType, page.ComponentType)
-			require.Equal(t, storePage.ComponentValue, page.ComponentValue)
 			require.Equal(t, storePage.PageIdentifier, page.PageIdentifier)
-			require.Equal(t, storePage.OptionID, page.OptionID)
-			require.Equal(t, storePage.OptionName, page.OptionName)
-			require.Equal(t, storePage.OptionValue, page.OptionValue)
-			require.Equal(t, storePage.OptionRequired, page.OptionRequired)
 		}
 
 		metas := result.Metas
 		require.NotEmpty(t, metas)
 
 		for _, meta := range metas {
-			storeMeta, err := store.GetMeta(context.Background(), meta.ID)
+			storeMeta, err := store.GetMeta(context.Background(), db.GetMetaParams{SiteID: siteID, ID: meta.ID})
 			require.NoError(t, err)
 			require.NotEmpty(t, storeMeta)
 			require.Equal(t, meta.ID, storeMeta.ID)
@@ -784,8 +736,6 @@ func TestUpdatePageTx(t *testing.T) {
 			require.Equal(t, meta.MetaRobots, storeMeta.MetaRobots)
 			require.Equal(t, meta.MetaOgImage, storeMeta.MetaOgImage)
 			require.Equal(t, meta.Locale, storeMeta.Locale)
-			require.Equal(t, meta.PageAmount, storeMeta.PageAmount)
-			require.Equal(t, meta.SiteLanguage, storeMeta.SiteLanguage)
 			require.Equal(t, meta.MetaKey, storeMeta.MetaKey)
 			require.Equal(t, meta.MetaValue, storeMeta.MetaValue)
 		}
@@ -794,9 +744,10 @@ func TestUpdatePageTx(t *testing.T) {
 
 func TestDeletePostsTx(t *testing.T) {
 	// Arrange
-	store := NewStore(testDB)
+	store := db.NewStore(testDB)
 
 	newPost := createRandomPosts(t)
+	siteID := newPost.SiteID
 
 	n := 5
 
@@ -806,7 +757,8 @@ func TestDeletePostsTx(t *testing.T) {
 	for i := 0; i < n; i++ {
 		go func() {
 
-			_, err := store.DeletePostsTx(context.Background(), DeleteContentTxParams{
+			_, err := store.DeletePostsTx(context.Background(), db.DeleteContentTxParams{
+				SiteID: siteID,
 				PageId: nil,
 				PostId: &newPost.ID,
 			})
@@ -820,14 +772,14 @@ func TestDeletePostsTx(t *testing.T) {
 		err := <-errs
 		require.NoError(t, err)
 
-		posts, err := store.GetPosts(context.Background(), newPost.ID)
+		posts, err := store.GetPosts(context.Background(), db.GetPostsParams{SiteID: siteID, ID: newPost.ID})
 		require.Error(t, err)
 		require.EqualError(t, err, sql.ErrNoRows.Error())
 		require.Empty(t, posts)
 
-		meta, err := store.GetMetaByPostsIDForUpdate(context.Background(), sql.NullInt64{
-			Int64: newPost.ID,
-			Valid: true,
+		meta, err := store.GetMetaByPostsIDForUpdate(context.Background(), db.GetMetaByPostsIDForUpdateParams{
+			SiteID:  siteID,
+			PostsID: sql.NullInt64{Int64: newPost.ID, Valid: true},
 		})
 		require.Error(t, err)
 		require.EqualError(t, err, sql.ErrNoRows.Error())
@@ -837,9 +789,10 @@ func TestDeletePostsTx(t *testing.T) {
 
 func TestDeletePageTx(t *testing.T) {
 	// Arrange
-	store := NewStore(testDB)
+	store := db.NewStore(testDB)
 
 	newPage := createRandomPage(t)
+	siteID := newPage.SiteID
 
 	n := 5
 
@@ -849,7 +802,8 @@ func TestDeletePageTx(t *testing.T) {
 	for i := 0; i < n; i++ {
 		go func() {
 
-			_, err := store.DeletePageTx(context.Background(), DeleteContentTxParams{
+			_, err := store.DeletePageTx(context.Background(), db.DeleteContentTxParams{
+				SiteID: siteID,
 				PageId: &newPage.ID,
 				PostId: nil,
 			})
@@ -863,17 +817,66 @@ func TestDeletePageTx(t *testing.T) {
 		err := <-errs
 		require.NoError(t, err)
 
-		page, err := store.GetPages(context.Background(), newPage.ID)
+		page, err := store.GetPages(context.Background(), db.GetPagesParams{SiteID: siteID, ID: newPage.ID})
 		require.Error(t, err)
 		require.EqualError(t, err, sql.ErrNoRows.Error())
 		require.Empty(t, page)
 
-		meta, err := store.GetMetaByPageIDForUpdate(context.Background(), sql.NullInt64{
-			Int64: newPage.ID,
-			Valid: true,
+		meta, err := store.GetMetaByPageIDForUpdate(context.Background(), db.GetMetaByPageIDForUpdateParams{
+			SiteID: siteID,
+			PageID: sql.NullInt64{Int64: newPage.ID, Valid: true},
 		})
 		require.Error(t, err)
 		require.EqualError(t, err, sql.ErrNoRows.Error())
 		require.Empty(t, meta)
 	}
 }
+
+func TestCreatePostsTxUnknownUser(t *testing.T) {
+	store := db.NewStore(testDB)
+
+	_, err := store.CreatePostsTx(context.Background(), db.CreateContentTxParams{
+		UserId: -1,
+	})
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCreatePostsTxNotAMember(t *testing.T) {
+	store := db.NewStore(testDB)
+	f := fixtures.For(t, testQueries)
+	post := f.PostParams()
+	otherSite := f.Site()
+
+	_, err := store.CreatePostsTx(context.Background(), db.CreateContentTxParams{
+		SiteID: otherSite.ID,
+		UserId: post.AuthorID,
+		Posts:  []db.CreatePostsParams{post},
+	})
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+
+	viewer := f.User()
+	f.Member(post.SiteID, viewer.ID, db.SiteRoleViewer)
+	_, err = store.CreatePostsTx(context.Background(), db.CreateContentTxParams{
+		SiteID: post.SiteID,
+		UserId: viewer.ID,
+		Posts:  []db.CreatePostsParams{post},
+	})
+	require.ErrorIs(t, err, apperr.ErrForbidden)
+}
+
+func TestCreatePostsTxMetaOfNewPost(t *testing.T) {
+	store := db.NewStore(testDB)
+	post := fixtures.For(t, testQueries).PostParams()
+
+	result, err := store.CreatePostsTx(context.Background(), db.CreateContentTxParams{
+		SiteID: post.SiteID,
+		UserId: post.AuthorID,
+		Posts:  []db.CreatePostsParams{post},
+		Metas:  []db.CreateMetaParams{{MetaKey: "og", MetaValue: "image"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Metas, 1)
+	require.Equal(t, sql.NullInt64{Int64: result.Posts[0].ID, Valid: true}, result.Metas[0].PostsID)
+	require.False(t, result.Metas[0].PageID.Valid)
+}
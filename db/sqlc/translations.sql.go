@@ -0,0 +1,398 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: translations.sql
+
+package frog_blossom_db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const createTranslationGroup = `-- name: CreateTranslationGroup :one
+INSERT INTO translation_groups (site_id) VALUES ($1)
+RETURNING id, site_id, created_at
+`
+
+func (q *Queries) CreateTranslationGroup(ctx context.Context, siteID int64) (TranslationGroup, error) {
+	row := q.db.QueryRowContext(ctx, createTranslationGroup, siteID)
+	var i TranslationGroup
+	err := row.Scan(&i.ID, &i.SiteID, &i.CreatedAt)
+	return i, err
+}
+
+const getPageByUrlInLocale = `-- name: GetPageByUrlInLocale :one
+WITH source AS (
+  SELECT pages.id, pages.site_id, pages.translation_group_id FROM pages
+  WHERE pages.site_id = $2 AND pages.url = $3
+  ORDER BY array_position($1::text[], pages.locale::text) NULLS LAST, pages.id
+  LIMIT 1
+)
+SELECT p.id, p.author_id, p.page_author, p.title, p.url, p.menu_order, p.page_identifier, p.parent_id, p.site_id, p.locale, p.translation_group_id FROM pages p
+JOIN source ON p.site_id = source.site_id
+  AND (p.id = source.id OR p.translation_group_id = source.translation_group_id)
+ORDER BY array_position($1::text[], p.locale::text) NULLS LAST, p.id = source.id DESC
+LIMIT 1
+`
+
+type GetPageByUrlInLocaleParams struct {
+	Locales []string `json:"locales"`
+	SiteID  int64    `json:"site_id"`
+	Url     string   `json:"url"`
+}
+
+// Like GetPageInLocale for the page at @url. When pages of several locales
+// share the url, the one first in @locales is the source.
+func (q *Queries) GetPageByUrlInLocale(ctx context.Context, arg GetPageByUrlInLocaleParams) (Page, error) {
+	row := q.db.QueryRowContext(ctx, getPageByUrlInLocale, pq.Array(arg.Locales), arg.SiteID, arg.Url)
+	var i Page
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.PageAuthor,
+		&i.Title,
+		&i.Url,
+		&i.MenuOrder,
+		&i.PageIdentifier,
+		&i.ParentID,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+	)
+	return i, err
+}
+
+const getPageInLocale = `-- name: GetPageInLocale :one
+SELECT p.id, p.author_id, p.page_author, p.title, p.url, p.menu_order, p.page_identifier, p.parent_id, p.site_id, p.locale, p.translation_group_id FROM pages p
+JOIN pages source ON p.site_id = source.site_id
+  AND (p.id = source.id OR p.translation_group_id = source.translation_group_id)
+WHERE source.site_id = $1 AND source.id = $2
+ORDER BY array_position($3::text[], p.locale::text) NULLS LAST, p.id = source.id DESC
+LIMIT 1
+`
+
+type GetPageInLocaleParams struct {
+	SiteID  int64    `json:"site_id"`
+	ID      int64    `json:"id"`
+	Locales []string `json:"locales"`
+}
+
+// The variant of the page in the first of @locales it is translated to, or
+// the page itself if there is none
+func (q *Queries) GetPageInLocale(ctx context.Context, arg GetPageInLocaleParams) (Page, error) {
+	row := q.db.QueryRowContext(ctx, getPageInLocale, arg.SiteID, arg.ID, pq.Array(arg.Locales))
+	var i Page
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.PageAuthor,
+		&i.Title,
+		&i.Url,
+		&i.MenuOrder,
+		&i.PageIdentifier,
+		&i.ParentID,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+	)
+	return i, err
+}
+
+const getPostInLocale = `-- name: GetPostInLocale :one
+SELECT p.id, p.title, p.content, p.author_id, p.url, p.created_at, p.updated_at, p.status, p.published_at, p.edited_at, p.post_author, p.post_mime_type, p.published_by, p.updated_by, p.site_id, p.locale, p.translation_group_id, p.featured_media_id FROM posts p
+JOIN posts source ON p.site_id = source.site_id
+  AND (p.id = source.id OR p.translation_group_id = source.translation_group_id)
+WHERE source.site_id = $1 AND source.id = $2
+ORDER BY array_position($3::text[], p.locale::text) NULLS LAST, p.id = source.id DESC
+LIMIT 1
+`
+
+type GetPostInLocaleParams struct {
+	SiteID  int64    `json:"site_id"`
+	ID      int64    `json:"id"`
+	Locales []string `json:"locales"`
+}
+
+// The variant of the post in the first of @locales it is translated to, or
+// the post itself if there is none
+func (q *Queries) GetPostInLocale(ctx context.Context, arg GetPostInLocaleParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostInLocale, arg.SiteID, arg.ID, pq.Array(arg.Locales))
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Content,
+		&i.AuthorID,
+		&i.Url,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishedAt,
+		&i.EditedAt,
+		&i.PostAuthor,
+		&i.PostMimeType,
+		&i.PublishedBy,
+		&i.UpdatedBy,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+		&i.FeaturedMediaID,
+	)
+	return i, err
+}
+
+const leavePageTranslationGroup = `-- name: LeavePageTranslationGroup :one
+UPDATE pages
+  SET translation_group_id = NULL
+WHERE site_id = $1 AND id = $2
+RETURNING id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id
+`
+
+type LeavePageTranslationGroupParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) LeavePageTranslationGroup(ctx context.Context, arg LeavePageTranslationGroupParams) (Page, error) {
+	row := q.db.QueryRowContext(ctx, leavePageTranslationGroup, arg.SiteID, arg.ID)
+	var i Page
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.PageAuthor,
+		&i.Title,
+		&i.Url,
+		&i.MenuOrder,
+		&i.PageIdentifier,
+		&i.ParentID,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+	)
+	return i, err
+}
+
+const leavePostTranslationGroup = `-- name: LeavePostTranslationGroup :one
+UPDATE posts
+  SET translation_group_id = NULL
+WHERE site_id = $1 AND id = $2
+RETURNING id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by, site_id, locale, translation_group_id, featured_media_id
+`
+
+type LeavePostTranslationGroupParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) LeavePostTranslationGroup(ctx context.Context, arg LeavePostTranslationGroupParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, leavePostTranslationGroup, arg.SiteID, arg.ID)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Content,
+		&i.AuthorID,
+		&i.Url,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishedAt,
+		&i.EditedAt,
+		&i.PostAuthor,
+		&i.PostMimeType,
+		&i.PublishedBy,
+		&i.UpdatedBy,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+		&i.FeaturedMediaID,
+	)
+	return i, err
+}
+
+const listPageTranslations = `-- name: ListPageTranslations :many
+SELECT id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id FROM pages
+WHERE site_id = $1 AND translation_group_id = $2
+ORDER BY locale
+`
+
+type ListPageTranslationsParams struct {
+	SiteID             int64         `json:"site_id"`
+	TranslationGroupID sql.NullInt64 `json:"translation_group_id"`
+}
+
+func (q *Queries) ListPageTranslations(ctx context.Context, arg ListPageTranslationsParams) ([]Page, error) {
+	rows, err := q.db.QueryContext(ctx, listPageTranslations, arg.SiteID, arg.TranslationGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Page
+	for rows.Next() {
+		var i Page
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.PageAuthor,
+			&i.Title,
+			&i.Url,
+			&i.MenuOrder,
+			&i.PageIdentifier,
+			&i.ParentID,
+			&i.SiteID,
+			&i.Locale,
+			&i.TranslationGroupID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostTranslations = `-- name: ListPostTranslations :many
+SELECT id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by, site_id, locale, translation_group_id, featured_media_id FROM posts
+WHERE site_id = $1 AND translation_group_id = $2
+ORDER BY locale
+`
+
+type ListPostTranslationsParams struct {
+	SiteID             int64         `json:"site_id"`
+	TranslationGroupID sql.NullInt64 `json:"translation_group_id"`
+}
+
+func (q *Queries) ListPostTranslations(ctx context.Context, arg ListPostTranslationsParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostTranslations, arg.SiteID, arg.TranslationGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.AuthorID,
+			&i.Url,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.PublishedAt,
+			&i.EditedAt,
+			&i.PostAuthor,
+			&i.PostMimeType,
+			&i.PublishedBy,
+			&i.UpdatedBy,
+			&i.SiteID,
+			&i.Locale,
+			&i.TranslationGroupID,
+			&i.FeaturedMediaID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPageTranslation = `-- name: SetPageTranslation :one
+UPDATE pages
+  SET locale = $1,
+  translation_group_id = $2
+WHERE site_id = $3 AND id = $4
+RETURNING id, author_id, page_author, title, url, menu_order, page_identifier, parent_id, site_id, locale, translation_group_id
+`
+
+type SetPageTranslationParams struct {
+	Locale             sql.NullString `json:"locale"`
+	TranslationGroupID sql.NullInt64  `json:"translation_group_id"`
+	SiteID             int64          `json:"site_id"`
+	ID                 int64          `json:"id"`
+}
+
+func (q *Queries) SetPageTranslation(ctx context.Context, arg SetPageTranslationParams) (Page, error) {
+	row := q.db.QueryRowContext(ctx, setPageTranslation,
+		arg.Locale,
+		arg.TranslationGroupID,
+		arg.SiteID,
+		arg.ID,
+	)
+	var i Page
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.PageAuthor,
+		&i.Title,
+		&i.Url,
+		&i.MenuOrder,
+		&i.PageIdentifier,
+		&i.ParentID,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+	)
+	return i, err
+}
+
+const setPostTranslation = `-- name: SetPostTranslation :one
+UPDATE posts
+  SET locale = $1,
+  translation_group_id = $2
+WHERE site_id = $3 AND id = $4
+RETURNING id, title, content, author_id, url, created_at, updated_at, status, published_at, edited_at, post_author, post_mime_type, published_by, updated_by, site_id, locale, translation_group_id, featured_media_id
+`
+
+type SetPostTranslationParams struct {
+	Locale             sql.NullString `json:"locale"`
+	TranslationGroupID sql.NullInt64  `json:"translation_group_id"`
+	SiteID             int64          `json:"site_id"`
+	ID                 int64          `json:"id"`
+}
+
+func (q *Queries) SetPostTranslation(ctx context.Context, arg SetPostTranslationParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, setPostTranslation,
+		arg.Locale,
+		arg.TranslationGroupID,
+		arg.SiteID,
+		arg.ID,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Content,
+		&i.AuthorID,
+		&i.Url,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishedAt,
+		&i.EditedAt,
+		&i.PostAuthor,
+		&i.PostMimeType,
+		&i.PublishedBy,
+		&i.UpdatedBy,
+		&i.SiteID,
+		&i.Locale,
+		&i.TranslationGroupID,
+		&i.FeaturedMediaID,
+	)
+	return i, err
+}
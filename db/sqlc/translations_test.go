@@ -0,0 +1,228 @@
+package frog_blossom_db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+// translatedPage creates a page in the site language with fr and fr-CA
+// translations and returns the three pages in that order
+func translatedPage(t *testing.T, store db.Store) []db.Page {
+	t.Helper()
+
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+	onSite := func(p *db.CreatePagesParams) { p.SiteID = site.ID }
+	pages := []db.Page{f.Page(onSite), f.Page(onSite), f.Page(onSite)}
+
+	for i, locale := range []string{"fr", "fr-CA"} {
+		_, err := store.AddPageTranslationTx(context.Background(), db.AddTranslationTxParams{
+			SiteID:       site.ID,
+			SourceID:     pages[0].ID,
+			SourceLocale: "en",
+			ID:           pages[i+1].ID,
+			Locale:       locale,
+		})
+		require.NoError(t, err)
+	}
+	for i, page := range pages {
+		page, err := store.GetPages(context.Background(), db.GetPagesParams{SiteID: site.ID, ID: page.ID})
+		require.NoError(t, err)
+		pages[i] = page
+	}
+	return pages
+}
+
+func TestAddPageTranslationTx(t *testing.T) {
+	// Arrange
+	store := db.NewStore(testDB)
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+	source := f.Page(func(p *db.CreatePagesParams) { p.SiteID = site.ID })
+	translation := f.Page(func(p *db.CreatePagesParams) { p.SiteID = site.ID })
+
+	// Act
+	result, err := store.AddPageTranslationTx(context.Background(), db.AddTranslationTxParams{
+		SiteID:       site.ID,
+		SourceID:     source.ID,
+		SourceLocale: "en",
+		ID:           translation.ID,
+		Locale:       "de",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.Pages, 2)
+	require.Equal(t, translation.ID, result.Pages[0].ID)
+	require.Equal(t, "de", result.Pages[0].Locale.String)
+	require.Equal(t, source.ID, result.Pages[1].ID)
+	require.Equal(t, "en", result.Pages[1].Locale.String)
+	require.True(t, result.Pages[0].TranslationGroupID.Valid)
+	require.Equal(t, result.Pages[0].TranslationGroupID, result.Pages[1].TranslationGroupID)
+}
+
+func TestAddPageTranslationTxRejects(t *testing.T) {
+	store := db.NewStore(testDB)
+	pages := translatedPage(t, store)
+	f := fixtures.For(t, testQueries)
+	untranslated := f.Page(func(p *db.CreatePagesParams) { p.SiteID = pages[0].SiteID })
+	elsewhere := f.Page()
+
+	testCases := []struct {
+		name     string
+		args     db.AddTranslationTxParams
+		expected error
+	}{
+		{
+			name:     "LocaleTaken",
+			args:     db.AddTranslationTxParams{SourceID: pages[0].ID, ID: untranslated.ID, Locale: "fr"},
+			expected: apperr.ErrConflict,
+		},
+		{
+			name:     "OwnTranslation",
+			args:     db.AddTranslationTxParams{SourceID: pages[0].ID, ID: pages[0].ID, Locale: "de"},
+			expected: apperr.ErrValidation,
+		},
+		{
+			name:     "SourceWithoutLocale",
+			args:     db.AddTranslationTxParams{SourceID: untranslated.ID, ID: pages[1].ID, Locale: "de"},
+			expected: apperr.ErrValidation,
+		},
+		{
+			name:     "TranslationOnOtherSite",
+			args:     db.AddTranslationTxParams{SourceID: pages[0].ID, ID: elsewhere.ID, Locale: "de"},
+			expected: apperr.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.args.SiteID = pages[0].SiteID
+			_, err := store.AddPageTranslationTx(context.Background(), tc.args)
+			require.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestGetPageInLocale(t *testing.T) {
+	store := db.NewStore(testDB)
+	pages := translatedPage(t, store)
+	untranslated := fixtures.For(t, testQueries).Page(func(p *db.CreatePagesParams) { p.SiteID = pages[0].SiteID })
+
+	testCases := []struct {
+		name     string
+		id       int64
+		locales  []string
+		expected int64
+	}{
+		{name: "Exact", id: pages[0].ID, locales: []string{"fr-CA", "fr", "en"}, expected: pages[2].ID},
+		{name: "Language", id: pages[0].ID, locales: []string{"fr-BE", "fr", "en"}, expected: pages[1].ID},
+		{name: "SiteLanguage", id: pages[2].ID, locales: []string{"de", "en"}, expected: pages[0].ID},
+		{name: "NoMatch", id: pages[1].ID, locales: []string{"ja"}, expected: pages[1].ID},
+		{name: "Untranslated", id: untranslated.ID, locales: []string{"fr", "en"}, expected: untranslated.ID},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := store.GetPageInLocale(context.Background(), db.GetPageInLocaleParams{
+				SiteID:  pages[0].SiteID,
+				ID:      tc.id,
+				Locales: tc.locales,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, page.ID)
+		})
+	}
+
+	_, err := store.GetPageInLocale(context.Background(), db.GetPageInLocaleParams{SiteID: pages[0].SiteID + 1, ID: pages[0].ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestGetPageByUrlInLocale(t *testing.T) {
+	store := db.NewStore(testDB)
+	pages := translatedPage(t, store)
+
+	page, err := store.GetPageByUrlInLocale(context.Background(), db.GetPageByUrlInLocaleParams{
+		SiteID:  pages[0].SiteID,
+		Url:     pages[0].Url,
+		Locales: []string{"fr-CA", "fr", "en"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, pages[2].ID, page.ID)
+
+	page, err = store.GetPageByUrlInLocale(context.Background(), db.GetPageByUrlInLocaleParams{
+		SiteID:  pages[0].SiteID,
+		Url:     pages[1].Url,
+		Locales: []string{"en"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, pages[0].ID, page.ID)
+
+	_, err = store.GetPageByUrlInLocale(context.Background(), db.GetPageByUrlInLocaleParams{
+		SiteID:  pages[0].SiteID,
+		Url:     "/missing",
+		Locales: []string{"en"},
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestLeavePageTranslationGroup(t *testing.T) {
+	store := db.NewStore(testDB)
+	pages := translatedPage(t, store)
+
+	page, err := store.LeavePageTranslationGroup(context.Background(), db.LeavePageTranslationGroupParams{SiteID: pages[1].SiteID, ID: pages[1].ID})
+	require.NoError(t, err)
+	require.False(t, page.TranslationGroupID.Valid)
+	require.Equal(t, "fr", page.Locale.String)
+
+	remaining, err := store.ListPageTranslations(context.Background(), db.ListPageTranslationsParams{
+		SiteID:             pages[0].SiteID,
+		TranslationGroupID: pages[0].TranslationGroupID,
+	})
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+}
+
+func TestAddPostTranslationTx(t *testing.T) {
+	// Arrange
+	store := db.NewStore(testDB)
+	f := fixtures.For(t, testQueries)
+	site := f.Site()
+	source := f.Post(func(p *db.CreatePostsParams) { p.SiteID = site.ID })
+	translation := f.Post(func(p *db.CreatePostsParams) { p.SiteID = site.ID })
+
+	// Act
+	result, err := store.AddPostTranslationTx(context.Background(), db.AddTranslationTxParams{
+		SiteID:       site.ID,
+		SourceID:     source.ID,
+		SourceLocale: "en",
+		ID:           translation.ID,
+		Locale:       "pt-BR",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.Posts, 2)
+
+	post, err := store.GetPostInLocale(context.Background(), db.GetPostInLocaleParams{
+		SiteID:  site.ID,
+		ID:      source.ID,
+		Locales: []string{"pt-PT", "pt", "pt-BR", "en"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, translation.ID, post.ID)
+
+	_, err = store.AddPostTranslationTx(context.Background(), db.AddTranslationTxParams{
+		SiteID:   site.ID,
+		SourceID: translation.ID,
+		ID:       f.Post(func(p *db.CreatePostsParams) { p.SiteID = site.ID }).ID,
+		Locale:   "en",
+	})
+	require.ErrorIs(t, err, apperr.ErrConflict)
+}
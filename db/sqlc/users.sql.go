@@ -82,6 +82,37 @@ func (q *Queries) DeleteUsers(ctx context.Context, id int64) error {
 	return err
 }
 
+const getSiteUser = `-- name: GetSiteUser :one
+SELECT users.id, users.username, users.email, users.password, users.role, users.first_name, users.last_name, users.user_url, users.description, users.created_at, users.updated_at, users.is_deleted FROM users
+JOIN site_members ON site_members.user_id = users.id
+WHERE site_members.site_id = $1 AND users.id = $2 LIMIT 1
+`
+
+type GetSiteUserParams struct {
+	SiteID int64 `json:"site_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetSiteUser(ctx context.Context, arg GetSiteUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getSiteUser, arg.SiteID, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.Role,
+		&i.FirstName,
+		&i.LastName,
+		&i.UserUrl,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsDeleted,
+	)
+	return i, err
+}
+
 const getUsers = `-- name: GetUsers :one
 SELECT id, username, email, password, role, first_name, last_name, user_url, description, created_at, updated_at, is_deleted FROM users
 WHERE id = $1 LIMIT 1
@@ -1,4 +1,4 @@
-package frog_blossom_db
+package frog_blossom_db_test
 
 import (
 	"context"
@@ -6,26 +6,15 @@ import (
 	"testing"
 	"time"
 
-	"github.com/reflection/frog_blossom_db/utils"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/fixtures"
 	"github.com/stretchr/testify/require"
 )
 
-func createRandomUser(t *testing.T) User {
+func createRandomUser(t *testing.T) db.User {
 	// Arrange
-	args := CreateUsersParams{
-		Username:  utils.RandomUsername(),
-		Email:     "cshopcott6@friendfeed.com",
-		Password:  "pP7<8jrQbwS",
-		Role:      "user",
-		FirstName: "Cointon",
-		LastName:  "Shopcott",
-		UserUrl: sql.NullString{
-			String: "https://robohash.org/nihildelectussed.png?size=50x50&set=set1", Valid: true,
-		},
-		Description: sql.NullString{
-			String: "Morbi porttitor lorem id ligula. Suspendisse ornare consequat lectus. In est risus, auctor sed, tristique in, tempus sit amet, sem. Fusce consequat. Nulla nisl. Nunc nisl.", Valid: true,
-		},
-	}
+	args := fixtures.For(t, testQueries).UserParams()
+
 	// Act
 	user, err := testQueries.CreateUsers(context.Background(), args)
 	// Assert
@@ -73,7 +62,7 @@ func TestUpdateUser(t *testing.T) {
 	// Arrange
 	newUser := createRandomUser(t)
 
-	args := UpdateUsersParams{
+	args := db.UpdateUsersParams{
 		ID:          newUser.ID,
 		Username:    newUser.Username,
 		Email:       "holyunin8@si.edu",
@@ -122,7 +111,7 @@ func TestListUsers(t *testing.T) {
 		createRandomUser(t)
 	}
 
-	args := ListUsersParams{
+	args := db.ListUsersParams{
 		Limit:  5,
 		Offset: 5,
 	}
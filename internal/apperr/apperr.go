@@ -0,0 +1,178 @@
+// Package apperr defines the domain errors the API exposes to clients.
+//
+// Anything that is not an *Error is treated as an internal failure and its
+// message is never sent over the wire.
+package apperr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/lib/pq"
+)
+
+// Kind classifies an error and decides its HTTP status
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindForbidden
+	KindRateLimited
+	KindTooLarge
+)
+
+var kindInfo = map[Kind]struct {
+	code   string
+	status int
+}{
+	KindInternal:    {"internal", http.StatusInternalServerError},
+	KindNotFound:    {"not_found", http.StatusNotFound},
+	KindConflict:    {"conflict", http.StatusConflict},
+	KindValidation:  {"validation_failed", http.StatusBadRequest},
+	KindForbidden:   {"forbidden", http.StatusForbidden},
+	KindRateLimited: {"rate_limited", http.StatusTooManyRequests},
+	KindTooLarge:    {"too_large", http.StatusRequestEntityTooLarge},
+}
+
+// Code is the stable, machine-readable name of the kind
+func (k Kind) Code() string {
+	return kindInfo[k].code
+}
+
+// Status is the HTTP status code the kind is rendered with
+func (k Kind) Status() int {
+	return kindInfo[k].status
+}
+
+// Error is a domain error whose Detail is safe to show to clients
+type Error struct {
+	Kind   Kind
+	Detail string
+	Fields []FieldError
+	Err    error
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Kind.Code(), e.Detail, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Kind.Code(), e.Detail)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error of the same kind, so callers can
+// write errors.Is(err, apperr.ErrNotFound)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Detail == "" && t.Err == nil && t.Kind == e.Kind
+}
+
+// Sentinels for errors.Is checks
+var (
+	ErrNotFound    = &Error{Kind: KindNotFound}
+	ErrConflict    = &Error{Kind: KindConflict}
+	ErrValidation  = &Error{Kind: KindValidation}
+	ErrForbidden   = &Error{Kind: KindForbidden}
+	ErrRateLimited = &Error{Kind: KindRateLimited}
+	ErrTooLarge    = &Error{Kind: KindTooLarge}
+)
+
+func NotFound(detail string, err error) *Error {
+	return &Error{Kind: KindNotFound, Detail: detail, Err: err}
+}
+
+func Conflict(detail string, err error) *Error {
+	return &Error{Kind: KindConflict, Detail: detail, Err: err}
+}
+
+func Validation(detail string, err error) *Error {
+	return &Error{Kind: KindValidation, Detail: detail, Err: err}
+}
+
+// InvalidFields is a validation error listing every rejected field
+func InvalidFields(fields []FieldError, err error) *Error {
+	return &Error{Kind: KindValidation, Detail: "request has invalid fields", Fields: fields, Err: err}
+}
+
+func Forbidden(detail string, err error) *Error {
+	return &Error{Kind: KindForbidden, Detail: detail, Err: err}
+}
+
+func RateLimited(detail string, err error) *Error {
+	return &Error{Kind: KindRateLimited, Detail: detail, Err: err}
+}
+
+func TooLarge(detail string, err error) *Error {
+	return &Error{Kind: KindTooLarge, Detail: detail, Err: err}
+}
+
+// Postgres error codes mapped to domain errors
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgInvalidText         = "22P02"
+	pgStringTooLong       = "22001"
+	// also raised when a row violates a row-level security policy
+	pgInsufficientPrivilege = "42501"
+)
+
+// FromDB translates database errors into domain errors. Errors it does not
+// recognise, and errors that already are domain errors, are returned as is.
+func FromDB(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound("resource not found", err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case pgUniqueViolation:
+		return Conflict("resource already exists", err)
+	case pgForeignKeyViolation:
+		return Validation("referenced resource does not exist", err)
+	case pgNotNullViolation, pgCheckViolation, pgInvalidText, pgStringTooLong:
+		return Validation("invalid field value", err)
+	case pgInsufficientPrivilege:
+		return Forbidden("not allowed to write this resource", err)
+	}
+	return err
+}
+
+// From returns err as a domain error, falling back to KindInternal
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(FromDB(err), &appErr) {
+		return appErr
+	}
+	return &Error{Kind: KindInternal, Detail: "internal server error", Err: err}
+}
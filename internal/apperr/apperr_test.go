@@ -0,0 +1,55 @@
+package apperr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromDB(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want *Error
+	}{
+		{"NoRows", sql.ErrNoRows, ErrNotFound},
+		{"WrappedNoRows", fmt.Errorf("get users err: %w", sql.ErrNoRows), ErrNotFound},
+		{"UniqueViolation", &pq.Error{Code: "23505"}, ErrConflict},
+		{"ForeignKeyViolation", fmt.Errorf("create posts err: %w", &pq.Error{Code: "23503"}), ErrValidation},
+		{"InvalidEnumValue", &pq.Error{Code: "22P02"}, ErrValidation},
+		{"RowLevelSecurity", &pq.Error{Code: "42501"}, ErrForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := FromDB(tc.err)
+			require.ErrorIs(t, err, tc.want)
+			require.ErrorIs(t, err, tc.err, "cause must stay in the chain")
+		})
+	}
+}
+
+func TestFromDBKeepsUnknownErrors(t *testing.T) {
+	err := errors.New("connection reset by peer")
+	require.Equal(t, err, FromDB(err))
+
+	canceled := &pq.Error{Code: "57014"}
+	require.Equal(t, canceled, FromDB(canceled))
+}
+
+func TestFrom(t *testing.T) {
+	internal := From(errors.New("dial tcp: connection refused"))
+	require.Equal(t, KindInternal, internal.Kind)
+	require.Equal(t, http.StatusInternalServerError, internal.Kind.Status())
+	require.NotContains(t, internal.Detail, "dial tcp")
+
+	notFound := From(NotFound("user not found", nil))
+	require.Equal(t, "not_found", notFound.Kind.Code())
+	require.Equal(t, http.StatusNotFound, notFound.Kind.Status())
+	require.Equal(t, "user not found", notFound.Detail)
+}
@@ -0,0 +1,37 @@
+// Package buildinfo reports which build of the server is running
+package buildinfo
+
+import "runtime/debug"
+
+// Version is set at link time:
+//
+//	go build -ldflags "-X github.com/reflection/frog_blossom_db/internal/buildinfo.Version=v1.2.0" ./cmd
+var Version = "dev"
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Read returns the link-time version plus the VCS details Go embeds in the binary
+func Read() Info {
+	info := Info{Version: Version}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = build.GoVersion
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
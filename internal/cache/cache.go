@@ -0,0 +1,32 @@
+// Package cache puts a read-through cache in front of the Store for posts,
+// pages and meta.
+//
+// Entries are tagged with the rows they contain and dropped by tag when a
+// write commits, so a change is visible on the next read rather than after
+// the TTL. Concurrent misses for the same entry share one database query.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Backend stores encoded entries. LRU is the in-process implementation; a
+// shared backend such as Redis can be plugged in by implementing it.
+// Errors are logged and treated as misses, never failing a request.
+type Backend interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set stores value for ttl under key, tagged so Invalidate can find it
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error
+	// Invalidate drops every entry carrying any of the tags
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
+// Options configure the caching Store
+type Options struct {
+	// TTL bounds how stale an entry can get if an invalidation is missed,
+	// e.g. after a write by another process
+	TTL time.Duration
+	// Observe, if set, is called on every lookup with the query name
+	Observe func(query string, hit bool)
+}
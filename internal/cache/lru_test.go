@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute, nil))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), time.Minute, nil))
+	_, found, _ := c.Get(ctx, "a")
+	require.True(t, found)
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), time.Minute, nil))
+
+	_, found, _ = c.Get(ctx, "b")
+	require.False(t, found, "b was least recently used")
+	value, found, _ := c.Get(ctx, "a")
+	require.True(t, found)
+	require.Equal(t, []byte("1"), value)
+	require.Equal(t, 2, c.Len())
+}
+
+func TestLRUExpires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute, []string{"t"}))
+	_, found, _ := c.Get(ctx, "a")
+	require.True(t, found)
+
+	now = now.Add(time.Minute)
+	_, found, _ = c.Get(ctx, "a")
+	require.False(t, found)
+	require.Equal(t, 0, c.Len())
+	require.Empty(t, c.tags)
+}
+
+func TestLRUInvalidateByTag(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	require.NoError(t, c.Set(ctx, "post:1", []byte("p1"), time.Minute, []string{"post:1"}))
+	require.NoError(t, c.Set(ctx, "meta:7", []byte("m7"), time.Minute, []string{"meta:7", "post-meta:1"}))
+	require.NoError(t, c.Set(ctx, "post:2", []byte("p2"), time.Minute, []string{"post:2"}))
+
+	require.NoError(t, c.Invalidate(ctx, "post-meta:1", "post:1"))
+
+	for key, want := range map[string]bool{"post:1": false, "meta:7": false, "post:2": true} {
+		_, found, _ := c.Get(ctx, key)
+		require.Equal(t, want, found, key)
+	}
+	require.NotContains(t, c.tags, "meta:7")
+}
+
+func TestLRUReplaceDropsOldTags(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	require.NoError(t, c.Set(ctx, "k", []byte("old"), time.Minute, []string{"old"}))
+	require.NoError(t, c.Set(ctx, "k", []byte("new"), time.Minute, []string{"new"}))
+	require.NoError(t, c.Invalidate(ctx, "old"))
+
+	value, found, _ := c.Get(ctx, "k")
+	require.True(t, found)
+	require.Equal(t, []byte("new"), value)
+}
@@ -0,0 +1,429 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"golang.org/x/sync/singleflight"
+)
+
+// Tags name what an entry contains. Lists carry the tag of their kind,
+// single rows the tag of their id, and meta rows also the tag of the post
+// or page they belong to so deleting by owner finds them.
+const (
+	tagPosts = "posts"
+	tagPages = "pages"
+	tagMetas = "metas"
+	tagSites = "sites"
+)
+
+func postTag(id int64) string             { return "post:" + strconv.FormatInt(id, 10) }
+func pageTag(id int64) string             { return "page:" + strconv.FormatInt(id, 10) }
+func metaTag(id int64) string             { return "meta:" + strconv.FormatInt(id, 10) }
+func postMetaTag(id int64) string         { return "post-meta:" + strconv.FormatInt(id, 10) }
+func pageMetaTag(id int64) string         { return "page-meta:" + strconv.FormatInt(id, 10) }
+func siteSettingsTag(siteID int64) string { return "site-settings:" + strconv.FormatInt(siteID, 10) }
+
+// siteScope prefixes keys and tags with the site of ctx. The rows a query
+// returns depend on it through row-level security, so an entry loaded for
+// one site is never served to, or invalidated by, another.
+func siteScope(ctx context.Context) string {
+	if site, ok := db.SiteFrom(ctx); ok {
+		return "site:" + strconv.FormatInt(site.ID, 10) + ":"
+	}
+	return "nosite:"
+}
+
+func scoped(ctx context.Context, names []string) []string {
+	scope := siteScope(ctx)
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = scope + name
+	}
+	return out
+}
+
+func metaTags(meta db.Meta) []string {
+	tags := []string{metaTag(meta.ID)}
+	if meta.PostsID.Valid {
+		tags = append(tags, postMetaTag(meta.PostsID.Int64))
+	}
+	if meta.PageID.Valid {
+		tags = append(tags, pageMetaTag(meta.PageID.Int64))
+	}
+	return tags
+}
+
+// Store wraps a Store so reads of sites, posts, pages, meta and site
+// settings are served from backend, and writes through it invalidate what
+// they changed once they succeed. Keys and tags include the site of the
+// context, besides the site a read asks for, see siteScope. Values
+// returned for concurrent misses are shared and must not be modified.
+func Store(next db.Store, backend Backend, opts Options) db.Store {
+	return &cachedStore{Store: next, backend: backend, opts: opts}
+}
+
+type cachedStore struct {
+	db.Store
+	backend Backend
+	opts    Options
+	group   singleflight.Group
+	// epoch changes on every invalidation. A load that raced with one is
+	// not stored, and misses after it don't join loads started before it.
+	epoch atomic.Uint64
+}
+
+// loadTimeout bounds a load. It runs without the cancellation of the
+// request that started it, so the other requests waiting for it don't fail
+// when that one goes away.
+const loadTimeout = 30 * time.Second
+
+func cached[T any](s *cachedStore, ctx context.Context, query, key string, tags func(T) []string, load func(context.Context) (T, error)) (T, error) {
+	key = siteScope(ctx) + key
+	if data, found, err := s.backend.Get(ctx, key); err != nil {
+		slog.WarnContext(ctx, "cache get failed", slog.String("key", key), slog.Any("error", err))
+	} else if found {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			s.observe(query, true)
+			return value, nil
+		}
+	}
+	s.observe(query, false)
+
+	epoch := s.epoch.Load()
+	value, err, _ := s.group.Do(fmt.Sprintf("%s@%d", key, epoch), func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+		value, err := load(ctx)
+		if err != nil {
+			return value, err
+		}
+		data, err := json.Marshal(value)
+		if err == nil && s.epoch.Load() == epoch {
+			err = s.backend.Set(ctx, key, data, s.opts.TTL, scoped(ctx, tags(value)))
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "cache set failed", slog.String("key", key), slog.Any("error", err))
+		}
+		return value, nil
+	})
+	return value.(T), err
+}
+
+func (s *cachedStore) observe(query string, hit bool) {
+	if s.opts.Observe != nil {
+		s.opts.Observe(query, hit)
+	}
+}
+
+// invalidate drops the tagged entries if the write succeeded
+func (s *cachedStore) invalidate(ctx context.Context, err error, tags ...string) {
+	if err != nil {
+		return
+	}
+	s.epoch.Add(1)
+	tags = scoped(ctx, tags)
+	if err := s.backend.Invalidate(ctx, tags...); err != nil {
+		slog.ErrorContext(ctx, "cache invalidation failed", slog.Any("tags", tags), slog.Any("error", err))
+	}
+}
+
+// Reads
+
+// GetSiteByDomain runs on every request to resolve its site. No query of
+// the store changes or deletes a site, so nothing here invalidates it: a
+// site edited in the database is served as it was for up to Options.TTL.
+// Misses aren't cached, so a new site is found at once.
+func (s *cachedStore) GetSiteByDomain(ctx context.Context, domain string) (db.Site, error) {
+	return cached(s, ctx, "GetSiteByDomain", "site:domain:"+domain,
+		func(db.Site) []string { return []string{tagSites} },
+		func(ctx context.Context) (db.Site, error) { return s.Store.GetSiteByDomain(ctx, domain) })
+}
+
+func (s *cachedStore) GetPosts(ctx context.Context, arg db.GetPostsParams) (db.Post, error) {
+	return cached(s, ctx, "GetPosts", fmt.Sprintf("post:%d:%d", arg.SiteID, arg.ID),
+		func(post db.Post) []string { return []string{postTag(post.ID)} },
+		func(ctx context.Context) (db.Post, error) { return s.Store.GetPosts(ctx, arg) })
+}
+
+func (s *cachedStore) GetPages(ctx context.Context, arg db.GetPagesParams) (db.Page, error) {
+	return cached(s, ctx, "GetPages", fmt.Sprintf("page:%d:%d", arg.SiteID, arg.ID),
+		func(page db.Page) []string { return []string{pageTag(page.ID)} },
+		func(ctx context.Context) (db.Page, error) { return s.Store.GetPages(ctx, arg) })
+}
+
+// ListPageOptions and ListPageComponents are tagged with their page, which
+// every write to the page, its options or its components invalidates.
+// Callers look the page up in its site first.
+func (s *cachedStore) ListPageOptions(ctx context.Context, pageID int64) ([]db.PageOption, error) {
+	return cached(s, ctx, "ListPageOptions", "page-options:"+strconv.FormatInt(pageID, 10),
+		func([]db.PageOption) []string { return []string{pageTag(pageID)} },
+		func(ctx context.Context) ([]db.PageOption, error) { return s.Store.ListPageOptions(ctx, pageID) })
+}
+
+func (s *cachedStore) ListPageComponents(ctx context.Context, pageID int64) ([]db.PageComponent, error) {
+	return cached(s, ctx, "ListPageComponents", "page-components:"+strconv.FormatInt(pageID, 10),
+		func([]db.PageComponent) []string { return []string{pageTag(pageID)} },
+		func(ctx context.Context) ([]db.PageComponent, error) { return s.Store.ListPageComponents(ctx, pageID) })
+}
+
+func (s *cachedStore) ListSitePages(ctx context.Context, siteID int64) ([]db.Page, error) {
+	return cached(s, ctx, "ListSitePages", fmt.Sprintf("pages:site:%d", siteID),
+		func([]db.Page) []string { return []string{tagPages} },
+		func(ctx context.Context) ([]db.Page, error) { return s.Store.ListSitePages(ctx, siteID) })
+}
+
+func (s *cachedStore) GetPageSubtree(ctx context.Context, arg db.GetPageSubtreeParams) ([]db.Page, error) {
+	return cached(s, ctx, "GetPageSubtree", fmt.Sprintf("pages:subtree:%d:%d", arg.SiteID, arg.ID),
+		func([]db.Page) []string { return []string{tagPages} },
+		func(ctx context.Context) ([]db.Page, error) { return s.Store.GetPageSubtree(ctx, arg) })
+}
+
+func (s *cachedStore) ListSiteSettings(ctx context.Context, siteID int64) ([]db.SiteSetting, error) {
+	return cached(s, ctx, "ListSiteSettings", siteSettingsTag(siteID),
+		func([]db.SiteSetting) []string { return []string{siteSettingsTag(siteID)} },
+		func(ctx context.Context) ([]db.SiteSetting, error) { return s.Store.ListSiteSettings(ctx, siteID) })
+}
+
+func (s *cachedStore) GetMeta(ctx context.Context, arg db.GetMetaParams) (db.Meta, error) {
+	return cached(s, ctx, "GetMeta", fmt.Sprintf("meta:%d:%d", arg.SiteID, arg.ID), metaTags,
+		func(ctx context.Context) (db.Meta, error) { return s.Store.GetMeta(ctx, arg) })
+}
+
+func (s *cachedStore) ListPosts(ctx context.Context, arg db.ListPostsParams) ([]db.Post, error) {
+	return cached(s, ctx, "ListPosts", fmt.Sprintf("posts:%d:%d:%d", arg.SiteID, arg.Limit, arg.Offset),
+		func([]db.Post) []string { return []string{tagPosts} },
+		func(ctx context.Context) ([]db.Post, error) { return s.Store.ListPosts(ctx, arg) })
+}
+
+func (s *cachedStore) ListPages(ctx context.Context, arg db.ListPagesParams) ([]db.Page, error) {
+	return cached(s, ctx, "ListPages", fmt.Sprintf("pages:%d:%d:%d", arg.SiteID, arg.Limit, arg.Offset),
+		func([]db.Page) []string { return []string{tagPages} },
+		func(ctx context.Context) ([]db.Page, error) { return s.Store.ListPages(ctx, arg) })
+}
+
+func (s *cachedStore) ListMeta(ctx context.Context, arg db.ListMetaParams) ([]db.Meta, error) {
+	return cached(s, ctx, "ListMeta", fmt.Sprintf("metas:%d:%d:%d", arg.SiteID, arg.Limit, arg.Offset),
+		func([]db.Meta) []string { return []string{tagMetas} },
+		func(ctx context.Context) ([]db.Meta, error) { return s.Store.ListMeta(ctx, arg) })
+}
+
+// Single-statement writes
+
+func (s *cachedStore) CreatePosts(ctx context.Context, arg db.CreatePostsParams) (db.Post, error) {
+	post, err := s.Store.CreatePosts(ctx, arg)
+	s.invalidate(ctx, err, tagPosts)
+	return post, err
+}
+
+func (s *cachedStore) UpdatePosts(ctx context.Context, arg db.UpdatePostsParams) (db.Post, error) {
+	post, err := s.Store.UpdatePosts(ctx, arg)
+	s.invalidate(ctx, err, tagPosts, postTag(arg.ID))
+	return post, err
+}
+
+func (s *cachedStore) DeletePosts(ctx context.Context, arg db.DeletePostsParams) error {
+	err := s.Store.DeletePosts(ctx, arg)
+	s.invalidate(ctx, err, tagPosts, postTag(arg.ID))
+	return err
+}
+
+func (s *cachedStore) CreatePages(ctx context.Context, arg db.CreatePagesParams) (db.Page, error) {
+	page, err := s.Store.CreatePages(ctx, arg)
+	s.invalidate(ctx, err, tagPages)
+	return page, err
+}
+
+func (s *cachedStore) UpdatePages(ctx context.Context, arg db.UpdatePagesParams) (db.Page, error) {
+	page, err := s.Store.UpdatePages(ctx, arg)
+	s.invalidate(ctx, err, tagPages, pageTag(arg.ID))
+	return page, err
+}
+
+func (s *cachedStore) DeletePages(ctx context.Context, arg db.DeletePagesParams) error {
+	err := s.Store.DeletePages(ctx, arg)
+	s.invalidate(ctx, err, tagPages, pageTag(arg.ID))
+	return err
+}
+
+func (s *cachedStore) CreatePageOption(ctx context.Context, arg db.CreatePageOptionParams) (db.PageOption, error) {
+	option, err := s.Store.CreatePageOption(ctx, arg)
+	s.invalidate(ctx, err, pageTag(arg.PageID))
+	return option, err
+}
+
+func (s *cachedStore) DeletePageOptions(ctx context.Context, pageID int64) error {
+	err := s.Store.DeletePageOptions(ctx, pageID)
+	s.invalidate(ctx, err, pageTag(pageID))
+	return err
+}
+
+func (s *cachedStore) CreateMeta(ctx context.Context, arg db.CreateMetaParams) (db.Meta, error) {
+	meta, err := s.Store.CreateMeta(ctx, arg)
+	s.invalidate(ctx, err, tagMetas)
+	return meta, err
+}
+
+func (s *cachedStore) UpdateMeta(ctx context.Context, arg db.UpdateMetaParams) (db.Meta, error) {
+	meta, err := s.Store.UpdateMeta(ctx, arg)
+	s.invalidate(ctx, err, tagMetas, metaTag(arg.ID))
+	return meta, err
+}
+
+func (s *cachedStore) DeleteMeta(ctx context.Context, arg db.DeleteMetaParams) error {
+	err := s.Store.DeleteMeta(ctx, arg)
+	s.invalidate(ctx, err, tagMetas, metaTag(arg.ID))
+	return err
+}
+
+func (s *cachedStore) DeleteMetaByPostId(ctx context.Context, arg db.DeleteMetaByPostIdParams) error {
+	err := s.Store.DeleteMetaByPostId(ctx, arg)
+	s.invalidate(ctx, err, tagMetas, postMetaTag(arg.PostsID.Int64))
+	return err
+}
+
+func (s *cachedStore) DeleteMetaByPageId(ctx context.Context, arg db.DeleteMetaByPageIdParams) error {
+	err := s.Store.DeleteMetaByPageId(ctx, arg)
+	s.invalidate(ctx, err, tagMetas, pageMetaTag(arg.PageID.Int64))
+	return err
+}
+
+func (s *cachedStore) LeavePostTranslationGroup(ctx context.Context, arg db.LeavePostTranslationGroupParams) (db.Post, error) {
+	post, err := s.Store.LeavePostTranslationGroup(ctx, arg)
+	s.invalidate(ctx, err, tagPosts, postTag(arg.ID))
+	return post, err
+}
+
+func (s *cachedStore) LeavePageTranslationGroup(ctx context.Context, arg db.LeavePageTranslationGroupParams) (db.Page, error) {
+	page, err := s.Store.LeavePageTranslationGroup(ctx, arg)
+	s.invalidate(ctx, err, tagPages, pageTag(arg.ID))
+	return page, err
+}
+
+func (s *cachedStore) UpsertSiteSetting(ctx context.Context, arg db.UpsertSiteSettingParams) (db.SiteSetting, error) {
+	setting, err := s.Store.UpsertSiteSetting(ctx, arg)
+	s.invalidate(ctx, err, siteSettingsTag(arg.SiteID))
+	return setting, err
+}
+
+func (s *cachedStore) DeleteSiteSetting(ctx context.Context, arg db.DeleteSiteSettingParams) error {
+	err := s.Store.DeleteSiteSetting(ctx, arg)
+	s.invalidate(ctx, err, siteSettingsTag(arg.SiteID))
+	return err
+}
+
+// Transactions invalidate after they committed
+
+func (s *cachedStore) InitSetupConfigTx(ctx context.Context, args db.InitSetupConfigTxParams) (db.InitSetupConfigTxResult, error) {
+	result, err := s.Store.InitSetupConfigTx(ctx, args)
+	s.invalidate(ctx, err, tagPosts, tagPages, tagMetas)
+	return result, err
+}
+
+func (s *cachedStore) CreatePostsTx(ctx context.Context, args db.CreateContentTxParams) (db.CreateContentTxResult, error) {
+	result, err := s.Store.CreatePostsTx(ctx, args)
+	s.invalidate(ctx, err, tagPosts, tagMetas)
+	return result, err
+}
+
+func (s *cachedStore) CreatePageTx(ctx context.Context, args db.CreateContentTxParams) (db.CreateContentTxResult, error) {
+	result, err := s.Store.CreatePageTx(ctx, args)
+	s.invalidate(ctx, err, tagPages, tagMetas)
+	return result, err
+}
+
+func (s *cachedStore) UpdatePostsTx(ctx context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	result, err := s.Store.UpdatePostsTx(ctx, args)
+	tags := []string{tagPosts, tagMetas}
+	for _, post := range result.Posts {
+		tags = append(tags, postTag(post.ID))
+	}
+	for _, meta := range result.Metas {
+		tags = append(tags, metaTag(meta.ID))
+	}
+	s.invalidate(ctx, err, tags...)
+	return result, err
+}
+
+func (s *cachedStore) UpdatePageTx(ctx context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	result, err := s.Store.UpdatePageTx(ctx, args)
+	tags := []string{tagPages, tagMetas}
+	for _, page := range result.Pages {
+		tags = append(tags, pageTag(page.ID))
+	}
+	for _, meta := range result.Metas {
+		tags = append(tags, metaTag(meta.ID))
+	}
+	s.invalidate(ctx, err, tags...)
+	return result, err
+}
+
+func (s *cachedStore) MovePageTx(ctx context.Context, args db.MovePageTxParams) (db.MovePageTxResult, error) {
+	result, err := s.Store.MovePageTx(ctx, args)
+	s.invalidate(ctx, err, tagPages, pageTag(args.PageID))
+	return result, err
+}
+
+func (s *cachedStore) ReorderPagesTx(ctx context.Context, args db.ReorderPagesTxParams) (db.ReorderPagesTxResult, error) {
+	result, err := s.Store.ReorderPagesTx(ctx, args)
+	tags := []string{tagPages}
+	for _, id := range args.PageIDs {
+		tags = append(tags, pageTag(id))
+	}
+	s.invalidate(ctx, err, tags...)
+	return result, err
+}
+
+func (s *cachedStore) AddPageComponentTx(ctx context.Context, args db.AddPageComponentTxParams) (db.PageComponentsTxResult, error) {
+	result, err := s.Store.AddPageComponentTx(ctx, args)
+	s.invalidate(ctx, err, pageTag(args.PageID))
+	return result, err
+}
+
+func (s *cachedStore) MovePageComponentTx(ctx context.Context, args db.MovePageComponentTxParams) (db.PageComponentsTxResult, error) {
+	result, err := s.Store.MovePageComponentTx(ctx, args)
+	s.invalidate(ctx, err, pageTag(args.PageID))
+	return result, err
+}
+
+func (s *cachedStore) RemovePageComponentTx(ctx context.Context, args db.RemovePageComponentTxParams) (db.PageComponentsTxResult, error) {
+	result, err := s.Store.RemovePageComponentTx(ctx, args)
+	s.invalidate(ctx, err, pageTag(args.PageID))
+	return result, err
+}
+
+func (s *cachedStore) DeletePostsTx(ctx context.Context, args db.DeleteContentTxParams) (db.DeleteContentTxResult, error) {
+	result, err := s.Store.DeletePostsTx(ctx, args)
+	if args.PostId != nil {
+		s.invalidate(ctx, err, tagPosts, tagMetas, postTag(*args.PostId), postMetaTag(*args.PostId))
+	}
+	return result, err
+}
+
+func (s *cachedStore) DeletePageTx(ctx context.Context, args db.DeleteContentTxParams) (db.DeleteContentTxResult, error) {
+	result, err := s.Store.DeletePageTx(ctx, args)
+	if args.PageId != nil {
+		s.invalidate(ctx, err, tagPages, tagMetas, pageTag(*args.PageId), pageMetaTag(*args.PageId))
+	}
+	return result, err
+}
+
+// Linking a translation changes the locale and group of both sides
+func (s *cachedStore) AddPostTranslationTx(ctx context.Context, args db.AddTranslationTxParams) (db.PostTranslationsTxResult, error) {
+	result, err := s.Store.AddPostTranslationTx(ctx, args)
+	s.invalidate(ctx, err, tagPosts, postTag(args.SourceID), postTag(args.ID))
+	return result, err
+}
+
+func (s *cachedStore) AddPageTranslationTx(ctx context.Context, args db.AddTranslationTxParams) (db.PageTranslationsTxResult, error) {
+	result, err := s.Store.AddPageTranslationTx(ctx, args)
+	s.invalidate(ctx, err, tagPages, pageTag(args.SourceID), pageTag(args.ID))
+	return result, err
+}
@@ -0,0 +1,446 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore serves posts and meta from maps and counts the reads that
+// reached it
+type fakeStore struct {
+	db.Store
+	mu       sync.Mutex
+	posts    map[int64]db.Post
+	pages    map[int64]db.Page
+	metas    map[int64]db.Meta
+	options  map[int64][]db.PageOption
+	settings map[int64][]db.SiteSetting
+	reads    atomic.Int32
+	// release, if set, blocks GetPosts after it read the row until closed
+	// or its context is done
+	release chan struct{}
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		posts: map[int64]db.Post{1: {ID: 1, SiteID: 1, Title: "first"}},
+		pages: map[int64]db.Page{3: {ID: 3, SiteID: 1, Title: "home"}},
+		metas: map[int64]db.Meta{7: {ID: 7, SiteID: 1, PostsID: sql.NullInt64{Int64: 1, Valid: true}, MetaKey: "k"}},
+		options: map[int64][]db.PageOption{
+			3: {{ID: 1, PageID: 3, Name: "theme", Type: db.OptionTypeString, Value: []byte(`"light"`)}},
+		},
+		settings: map[int64][]db.SiteSetting{
+			1: {{SiteID: 1, Key: "page_amount", Value: []byte(`25`)}},
+		},
+	}
+}
+
+func (f *fakeStore) GetPosts(ctx context.Context, arg db.GetPostsParams) (db.Post, error) {
+	f.mu.Lock()
+	post, found := f.posts[arg.ID]
+	f.mu.Unlock()
+	found = found && post.SiteID == arg.SiteID
+
+	f.reads.Add(1)
+	if f.release != nil {
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+			return db.Post{}, ctx.Err()
+		}
+	}
+	if !found {
+		return db.Post{}, sql.ErrNoRows
+	}
+	return post, nil
+}
+
+func (f *fakeStore) GetMeta(_ context.Context, arg db.GetMetaParams) (db.Meta, error) {
+	f.reads.Add(1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.metas[arg.ID], nil
+}
+
+// visible is what row-level security does: rows of other sites than the
+// one of ctx are hidden
+func visible(ctx context.Context, siteID int64) bool {
+	site, ok := db.SiteFrom(ctx)
+	return ok && site.ID == siteID
+}
+
+func (f *fakeStore) GetPages(ctx context.Context, arg db.GetPagesParams) (db.Page, error) {
+	f.reads.Add(1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	page, found := f.pages[arg.ID]
+	if !found || page.SiteID != arg.SiteID || !visible(ctx, page.SiteID) {
+		return db.Page{}, sql.ErrNoRows
+	}
+	return page, nil
+}
+
+func (f *fakeStore) ListPageOptions(ctx context.Context, pageID int64) ([]db.PageOption, error) {
+	f.reads.Add(1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if page, found := f.pages[pageID]; found && !visible(ctx, page.SiteID) {
+		return nil, nil
+	}
+	return f.options[pageID], nil
+}
+
+func (f *fakeStore) ListSiteSettings(_ context.Context, siteID int64) ([]db.SiteSetting, error) {
+	f.reads.Add(1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.settings[siteID], nil
+}
+
+func (f *fakeStore) UpsertSiteSetting(_ context.Context, arg db.UpsertSiteSettingParams) (db.SiteSetting, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	setting := db.SiteSetting{SiteID: arg.SiteID, Key: arg.Key, Value: arg.Value}
+	f.settings[arg.SiteID] = []db.SiteSetting{setting}
+	return setting, nil
+}
+
+func (f *fakeStore) UpdatePageTx(_ context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result db.UpdateContentTxResult
+	for i, p := range args.Pages {
+		result.Pages = append(result.Pages, db.Page{ID: p.ID, Title: p.Title})
+		if i < len(args.PageOptions) && args.PageOptions[i] != nil {
+			var options []db.PageOption
+			for position, o := range args.PageOptions[i] {
+				options = append(options, db.PageOption{PageID: p.ID, Position: int32(position), Name: o.Name, Type: o.Type, Value: o.Value})
+			}
+			f.options[p.ID] = options
+		}
+		result.PageOptions = append(result.PageOptions, f.options[p.ID])
+	}
+	return result, nil
+}
+
+func (f *fakeStore) UpdatePostsTx(_ context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result db.UpdateContentTxResult
+	for _, p := range args.Posts {
+		post := f.posts[p.ID]
+		post.Title = p.Title
+		f.posts[p.ID] = post
+		result.Posts = append(result.Posts, post)
+	}
+	return result, nil
+}
+
+func (f *fakeStore) DeletePostsTx(_ context.Context, args db.DeleteContentTxParams) (db.DeleteContentTxResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.posts, *args.PostId)
+	for id, meta := range f.metas {
+		if meta.PostsID.Int64 == *args.PostId {
+			delete(f.metas, id)
+		}
+	}
+	return db.DeleteContentTxResult{DeletedPost: true, DeletedMeta: true}, nil
+}
+
+func (f *fakeStore) AddPostTranslationTx(_ context.Context, args db.AddTranslationTxParams) (db.PostTranslationsTxResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result db.PostTranslationsTxResult
+	group := sql.NullInt64{Int64: args.SourceID, Valid: true}
+	for id, locale := range map[int64]string{args.SourceID: args.SourceLocale, args.ID: args.Locale} {
+		post := f.posts[id]
+		post.Locale = sql.NullString{String: locale, Valid: true}
+		post.TranslationGroupID = group
+		f.posts[id] = post
+		result.Posts = append(result.Posts, post)
+	}
+	return result, nil
+}
+
+func newTestStore(next db.Store) (db.Store, map[string]int) {
+	lookups := map[string]int{}
+	var mu sync.Mutex
+	return Store(next, NewLRU(100), Options{
+		TTL: time.Minute,
+		Observe: func(query string, hit bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			if hit {
+				lookups[query+" hit"]++
+			} else {
+				lookups[query+" miss"]++
+			}
+		},
+	}), lookups
+}
+
+func TestReadThrough(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStore()
+	store, lookups := newTestStore(fake)
+
+	for i := 0; i < 3; i++ {
+		post, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+		require.NoError(t, err)
+		require.Equal(t, "first", post.Title)
+	}
+
+	require.Equal(t, int32(1), fake.reads.Load())
+	require.Equal(t, map[string]int{"GetPosts miss": 1, "GetPosts hit": 2}, lookups)
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStore()
+	store, _ := newTestStore(fake)
+
+	_, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 404})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 404})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Equal(t, int32(2), fake.reads.Load())
+}
+
+func TestOtherSitesDoNotShareEntries(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStore()
+	store, _ := newTestStore(fake)
+
+	_, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+	require.NoError(t, err)
+
+	_, err = store.GetPosts(ctx, db.GetPostsParams{SiteID: 2, ID: 1})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Equal(t, int32(2), fake.reads.Load())
+}
+
+func TestSitesOfContextDoNotShareEntries(t *testing.T) {
+	fake := newFakeStore()
+	store, _ := newTestStore(fake)
+	siteA := db.WithSite(context.Background(), db.Site{ID: 1})
+	siteB := db.WithSite(context.Background(), db.Site{ID: 2})
+
+	page, err := store.GetPages(siteA, db.GetPagesParams{SiteID: 1, ID: 3})
+	require.NoError(t, err)
+	require.Equal(t, "home", page.Title)
+	options, err := store.ListPageOptions(siteA, 3)
+	require.NoError(t, err)
+	require.Len(t, options, 1)
+
+	// a request of site B asking for the same rows, as a buggy caller
+	// would, goes to the database and gets what site B may see
+	_, err = store.GetPages(siteB, db.GetPagesParams{SiteID: 1, ID: 3})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	options, err = store.ListPageOptions(siteB, 3)
+	require.NoError(t, err)
+	require.Empty(t, options)
+	require.Equal(t, int32(4), fake.reads.Load())
+
+	// and site A still reads its own entries
+	_, err = store.GetPages(siteA, db.GetPagesParams{SiteID: 1, ID: 3})
+	require.NoError(t, err)
+	require.Equal(t, int32(4), fake.reads.Load())
+}
+
+func TestUpdateInvalidates(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStore()
+	store, _ := newTestStore(fake)
+
+	_, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+	require.NoError(t, err)
+
+	_, err = store.UpdatePostsTx(ctx, db.UpdateContentTxParams{
+		Posts: []db.UpdatePostsParams{{ID: 1, Title: "second"}},
+	})
+	require.NoError(t, err)
+
+	post, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+	require.NoError(t, err)
+	require.Equal(t, "second", post.Title)
+	require.Equal(t, int32(2), fake.reads.Load())
+}
+
+func TestAddingTranslationInvalidatesSource(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStore()
+	fake.posts[2] = db.Post{ID: 2, SiteID: 1, Title: "deuxième"}
+	store, _ := newTestStore(fake)
+
+	_, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+	require.NoError(t, err)
+
+	_, err = store.AddPostTranslationTx(ctx, db.AddTranslationTxParams{SiteID: 1, SourceID: 1, SourceLocale: "en", ID: 2, Locale: "fr"})
+	require.NoError(t, err)
+
+	post, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+	require.NoError(t, err)
+	require.Equal(t, "en", post.Locale.String)
+	require.Equal(t, int32(2), fake.reads.Load())
+}
+
+func TestReplacingPageOptionsInvalidates(t *testing.T) {
+	ctx := db.WithSite(context.Background(), db.Site{ID: 1})
+	fake := newFakeStore()
+	store, _ := newTestStore(fake)
+
+	options, err := store.ListPageOptions(ctx, 3)
+	require.NoError(t, err)
+	require.Len(t, options, 1)
+
+	_, err = store.UpdatePageTx(ctx, db.UpdateContentTxParams{
+		Pages: []db.UpdatePagesParams{{ID: 3, Title: "home"}},
+		PageOptions: [][]db.PageOptionParams{{
+			{Name: "theme", Type: db.OptionTypeString, Value: []byte(`"dark"`)},
+			{Name: "posts_per_page", Type: db.OptionTypeNumber, Value: []byte(`10`)},
+		}},
+	})
+	require.NoError(t, err)
+
+	options, err = store.ListPageOptions(ctx, 3)
+	require.NoError(t, err)
+	require.Len(t, options, 2)
+	require.JSONEq(t, `"dark"`, string(options[0].Value))
+	require.Equal(t, int32(2), fake.reads.Load())
+}
+
+func TestUpsertingSiteSettingInvalidatesSite(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStore()
+	store, _ := newTestStore(fake)
+
+	_, err := store.ListSiteSettings(ctx, 1)
+	require.NoError(t, err)
+	_, err = store.ListSiteSettings(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), fake.reads.Load())
+
+	_, err = store.UpsertSiteSetting(ctx, db.UpsertSiteSettingParams{SiteID: 1, Key: "page_amount", Value: []byte(`50`)})
+	require.NoError(t, err)
+
+	settings, err := store.ListSiteSettings(ctx, 1)
+	require.NoError(t, err)
+	require.JSONEq(t, `50`, string(settings[0].Value))
+	require.Equal(t, int32(2), fake.reads.Load())
+}
+
+func TestDeleteInvalidatesOwnedMeta(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStore()
+	store, _ := newTestStore(fake)
+
+	_, err := store.GetMeta(ctx, db.GetMetaParams{SiteID: 1, ID: 7})
+	require.NoError(t, err)
+
+	postID := int64(1)
+	_, err = store.DeletePostsTx(ctx, db.DeleteContentTxParams{SiteID: 1, PostId: &postID})
+	require.NoError(t, err)
+
+	meta, err := store.GetMeta(ctx, db.GetMetaParams{SiteID: 1, ID: 7})
+	require.NoError(t, err)
+	require.Zero(t, meta.ID, "meta of the deleted post is read again")
+	_, err = store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestConcurrentMissesShareOneQuery(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStore()
+	fake.release = make(chan struct{})
+	store, _ := newTestStore(fake)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			post, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+			require.NoError(t, err)
+			require.Equal(t, "first", post.Title)
+		}()
+	}
+
+	// let the callers pile up behind the first query
+	require.Eventually(t, func() bool { return fake.reads.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(fake.release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), fake.reads.Load())
+}
+
+func TestFirstCallerGoingAwayDoesNotFailTheOthers(t *testing.T) {
+	fake := newFakeStore()
+	fake.release = make(chan struct{})
+	store, _ := newTestStore(fake)
+
+	first, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan error)
+	go func() {
+		_, err := store.GetPosts(first, db.GetPostsParams{SiteID: 1, ID: 1})
+		firstDone <- err
+	}()
+	require.Eventually(t, func() bool { return fake.reads.Load() == 1 }, time.Second, time.Millisecond)
+
+	secondDone := make(chan error)
+	go func() {
+		post, err := store.GetPosts(context.Background(), db.GetPostsParams{SiteID: 1, ID: 1})
+		if err == nil && post.Title != "first" {
+			err = fmt.Errorf("got post %q", post.Title)
+		}
+		secondDone <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-secondDone:
+		t.Fatalf("second caller returned before the load finished: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(fake.release)
+
+	require.NoError(t, <-secondDone)
+	require.NoError(t, <-firstDone)
+	require.Equal(t, int32(1), fake.reads.Load())
+}
+
+func TestLoadRacingAnInvalidationIsNotStored(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStore()
+	fake.release = make(chan struct{})
+	store, _ := newTestStore(fake)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+	}()
+	require.Eventually(t, func() bool { return fake.reads.Load() == 1 }, time.Second, time.Millisecond)
+
+	// the write commits while the read is in flight
+	_, err := store.UpdatePostsTx(ctx, db.UpdateContentTxParams{
+		Posts: []db.UpdatePostsParams{{ID: 1, Title: "second"}},
+	})
+	require.NoError(t, err)
+	close(fake.release)
+	<-done
+
+	post, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: 1, ID: 1})
+	require.NoError(t, err)
+	require.Equal(t, "second", post.Title)
+}
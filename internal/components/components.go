@@ -0,0 +1,191 @@
+// Package components keeps the JSON Schemas that page component values
+// are validated against, keyed by component type.
+//
+// The built-in types are hero, rich-text, gallery and form, see schemas/.
+// More are added from Go code:
+//
+//	components.Register("quote", []byte(`{"type": "object", ...}`))
+package components
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+//go:embed schemas/*.json
+var builtins embed.FS
+
+// Type is a registered component type and its schema
+type Type struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type entry struct {
+	raw    json.RawMessage
+	schema *jsonschema.Schema
+}
+
+// Registry maps component types to their schemas. It is safe for
+// concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	types map[string]entry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{types: map[string]entry{}}
+}
+
+// Default holds the built-in types and is used by the API
+var Default = NewRegistry()
+
+func init() {
+	files, err := builtins.ReadDir("schemas")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		schema, err := builtins.ReadFile(path.Join("schemas", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+		if err := Default.Register(strings.TrimSuffix(file.Name(), ".json"), schema); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register adds a type to the Default registry
+func Register(name string, schema []byte) error {
+	return Default.Register(name, schema)
+}
+
+// Register compiles schema and adds it under name. Schemas without
+// $schema are read as draft 2020-12; formats are asserted.
+func (r *Registry) Register(name string, schema []byte) error {
+	if name == "" || len(name) > 64 {
+		return fmt.Errorf("components: type name must be 1 to 64 characters, got %q", name)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+	url := "mem://components/" + name + ".json"
+	if err := compiler.AddResource(url, bytes.NewReader(schema)); err != nil {
+		return fmt.Errorf("components: read schema of %s: %w", name, err)
+	}
+	compiled, err := compiler.Compile(url)
+	if err != nil {
+		return fmt.Errorf("components: compile schema of %s: %w", name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.types[name]; ok {
+		return fmt.Errorf("components: type %s is already registered", name)
+	}
+	r.types[name] = entry{raw: json.RawMessage(schema), schema: compiled}
+	return nil
+}
+
+// Types returns the registered types sorted by name
+func (r *Registry) Types() []Type {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]Type, 0, len(r.types))
+	for name, entry := range r.types {
+		types = append(types, Type{Name: name, Schema: entry.raw})
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// Validate checks value against the schema of the named type. Failures are
+// validation errors listing the offending fields, as value.<path> (or type
+// when the type isn't registered).
+func (r *Registry) Validate(name string, value json.RawMessage) error {
+	r.mu.RLock()
+	entry, ok := r.types[name]
+	r.mu.RUnlock()
+	if !ok {
+		var names []string
+		for _, t := range r.Types() {
+			names = append(names, t.Name)
+		}
+		return apperr.InvalidFields([]apperr.FieldError{{
+			Field:   "type",
+			Rule:    "registered",
+			Message: "must be one of: " + strings.Join(names, ", "),
+		}}, nil)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return apperr.InvalidFields([]apperr.FieldError{{
+			Field:   "value",
+			Rule:    "json",
+			Message: "must be valid JSON",
+		}}, err)
+	}
+
+	err := entry.schema.Validate(doc)
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) {
+		fields := fieldErrors(validationErr)
+		// causes come in map order
+		sort.SliceStable(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+		return apperr.InvalidFields(fields, err)
+	}
+	return err
+}
+
+// fieldErrors flattens the causes of a schema failure into one field error
+// per innermost failure
+func fieldErrors(err *jsonschema.ValidationError) []apperr.FieldError {
+	if len(err.Causes) == 0 {
+		return []apperr.FieldError{{
+			Field:   fieldPath(err.InstanceLocation),
+			Rule:    path.Base(err.KeywordLocation),
+			Message: err.Message,
+		}}
+	}
+	var fields []apperr.FieldError
+	for _, cause := range err.Causes {
+		fields = append(fields, fieldErrors(cause)...)
+	}
+	return fields
+}
+
+// fieldPath turns a JSON pointer into the request field it points at, e.g.
+// /images/0/src becomes value.images[0].src
+func fieldPath(pointer string) string {
+	var b strings.Builder
+	b.WriteString("value")
+	if pointer == "" {
+		return b.String()
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		if _, err := strconv.Atoi(token); err == nil {
+			b.WriteString("[" + token + "]")
+			continue
+		}
+		b.WriteString("." + token)
+	}
+	return b.String()
+}
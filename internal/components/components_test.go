@@ -0,0 +1,80 @@
+package components
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinTypes(t *testing.T) {
+	var names []string
+	for _, typ := range Default.Types() {
+		names = append(names, typ.Name)
+		require.True(t, json.Valid(typ.Schema))
+	}
+	require.Equal(t, []string{"form", "gallery", "hero", "rich-text"}, names)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		typ    string
+		value  string
+		fields []string
+		rule   string
+	}{
+		{name: "Hero", typ: "hero", value: `{"title": "Welcome", "cta": {"label": "Join", "url": "/join"}}`},
+		{name: "RichText", typ: "rich-text", value: `{"format": "markdown", "body": "# Hi"}`},
+		{name: "Gallery", typ: "gallery", value: `{"columns": 3, "images": [{"src": "/a.png", "alt": "A"}]}`},
+		{name: "Form", typ: "form", value: `{"fields": [{"name": "email", "label": "Email", "type": "email", "required": true}]}`},
+		{name: "MissingTitle", typ: "hero", value: `{"subtitle": "x"}`, fields: []string{"value"}, rule: "required"},
+		{name: "NestedItem", typ: "gallery", value: `{"images": [{"src": "/a.png"}, {"alt": "no src"}]}`, fields: []string{"value.images[1]"}, rule: "required"},
+		{name: "WrongType", typ: "gallery", value: `{"columns": 9, "images": [{"src": "/a.png"}]}`, fields: []string{"value.columns"}, rule: "maximum"},
+		{name: "UnknownField", typ: "rich-text", value: `{"format": "html", "body": "", "color": "red"}`, fields: []string{"value"}, rule: "additionalProperties"},
+		{name: "UnknownType", typ: "carousel", value: `{}`, fields: []string{"type"}, rule: "registered"},
+		{name: "NotJSON", typ: "hero", value: `{`, fields: []string{"value"}, rule: "json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Default.Validate(tc.typ, json.RawMessage(tc.value))
+			if tc.fields == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var appErr *apperr.Error
+			require.ErrorAs(t, err, &appErr)
+			require.ErrorIs(t, err, apperr.ErrValidation)
+			var fields []string
+			for _, field := range appErr.Fields {
+				fields = append(fields, field.Field)
+				require.NotEmpty(t, field.Message)
+			}
+			require.Equal(t, tc.fields, fields)
+			require.Equal(t, tc.rule, appErr.Fields[0].Rule)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	registry := NewRegistry()
+	schema := []byte(`{"type": "object", "required": ["text"], "properties": {"text": {"type": "string"}}}`)
+
+	require.NoError(t, registry.Register("quote", schema))
+	require.Error(t, registry.Register("quote", schema), "types can't be registered twice")
+	require.Error(t, registry.Register("broken", []byte(`{"type": 42}`)))
+	require.Error(t, registry.Register("", schema))
+
+	require.NoError(t, registry.Validate("quote", json.RawMessage(`{"text": "Ribbit"}`)))
+	require.Error(t, registry.Validate("quote", json.RawMessage(`{"text": 1}`)))
+	require.Len(t, registry.Types(), 1)
+}
+
+func TestFieldPath(t *testing.T) {
+	require.Equal(t, "value", fieldPath(""))
+	require.Equal(t, "value.images[0].src", fieldPath("/images/0/src"))
+	require.Equal(t, "value.a/b", fieldPath("/a~1b"))
+}
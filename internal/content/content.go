@@ -0,0 +1,126 @@
+// Package content renders the stored body of a post to HTML for delivery.
+// A renderer is chosen by the post's mime type; its output is sanitized
+// against an allow-list, then headings get anchors for a table of contents
+// and the text is counted for a reading time.
+package content
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// WordsPerMinute is the reading speed reading times assume
+const WordsPerMinute = 200
+
+// ErrUnsupported is returned for mime types there is no renderer for
+var ErrUnsupported = errors.New("content: unsupported mime type")
+
+// Heading is an entry of the table of contents, linking to its anchor
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+// Rendered is the delivered form of a body
+type Rendered struct {
+	HTML        string    `json:"html"`
+	TOC         []Heading `json:"toc"`
+	WordCount   int       `json:"word_count"`
+	ReadingTime int       `json:"reading_time_minutes"`
+}
+
+// Renderer turns a body into HTML, which is sanitized afterwards
+type Renderer func(source string) (string, error)
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	// raw HTML is kept here and filtered by the policy like any other HTML
+	goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+)
+
+func renderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func renderHTML(source string) (string, error) {
+	return source, nil
+}
+
+// renderText makes a paragraph of every block separated by a blank line
+func renderText(source string) (string, error) {
+	var buf strings.Builder
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	for _, block := range strings.Split(source, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		lines := strings.Split(html.EscapeString(block), "\n")
+		buf.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+	return buf.String(), nil
+}
+
+var renderers = map[string]Renderer{
+	"text/markdown":   renderMarkdown,
+	"text/x-markdown": renderMarkdown,
+	"text/html":       renderHTML,
+	"text/plain":      renderText,
+}
+
+// policy allows the markup of user content: text formatting, links,
+// images, lists and tables, without scripts or styles. Code blocks keep
+// their language class for highlighters.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[a-zA-Z0-9+#-]+$`)).OnElements("code")
+	return p
+}()
+
+// mediaType is mimeType without parameters such as charset
+func mediaType(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(mimeType))
+	}
+	return mediaType
+}
+
+// Supported reports whether there is a renderer for mimeType
+func Supported(mimeType string) bool {
+	_, ok := renderers[mediaType(mimeType)]
+	return ok
+}
+
+// Render renders source as mimeType, without caching
+func Render(mimeType, source string) (Rendered, error) {
+	render, ok := renderers[mediaType(mimeType)]
+	if !ok {
+		return Rendered{}, fmt.Errorf("%w: %q", ErrUnsupported, mimeType)
+	}
+	out, err := render(source)
+	if err != nil {
+		return Rendered{}, err
+	}
+	return annotate(policy.Sanitize(out))
+}
+
+// readingTime is in whole minutes, rounded up
+func readingTime(words int) int {
+	return (words + WordsPerMinute - 1) / WordsPerMinute
+}
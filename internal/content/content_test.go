@@ -0,0 +1,103 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	rendered, err := Render("text/markdown; charset=utf-8", "# Frogs\n\nFrogs *like* ponds.\n\n## Where they live\n\n| pond | frogs |\n|---|---|\n| big | 3 |\n")
+	require.NoError(t, err)
+
+	require.Contains(t, rendered.HTML, `<h1 id="frogs">Frogs</h1>`)
+	require.Contains(t, rendered.HTML, `<p>Frogs <em>like</em> ponds.</p>`)
+	require.Contains(t, rendered.HTML, `<h2 id="where-they-live">Where they live</h2>`)
+	require.Contains(t, rendered.HTML, `<table>`)
+	require.Equal(t, []Heading{
+		{Level: 1, ID: "frogs", Text: "Frogs"},
+		{Level: 2, ID: "where-they-live", Text: "Where they live"},
+	}, rendered.TOC)
+	require.Equal(t, 11, rendered.WordCount)
+	require.Equal(t, 1, rendered.ReadingTime)
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mimeType string
+		source   string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Markdown",
+			mimeType: "text/markdown",
+			source:   "Hi <script>alert(1)</script>[there](javascript:alert(1)) <img src=x onerror=alert(1)>\n\n```go\nfunc main() {}\n```\n",
+			contains: []string{`<code class="language-go">`, `<img src="x"/>`},
+			excludes: []string{"<script", "javascript:", "onerror"},
+		},
+		{
+			name:     "HTML",
+			mimeType: "text/html",
+			source:   `<h2 id="main" style="color:red" onclick="x()">Title</h2><iframe src="https://example.com"></iframe><a href="https://example.com">link</a>`,
+			contains: []string{`<h2 id="title">Title</h2>`, `<a href="https://example.com" rel="nofollow">link</a>`},
+			excludes: []string{"style", "onclick", "<iframe", `id="main"`},
+		},
+		{
+			name:     "Plain",
+			mimeType: "text/plain",
+			source:   "Frogs & <toads>\nhop\n\nsecond",
+			contains: []string{"<p>Frogs &amp; &lt;toads&gt;<br/>\nhop</p>", "<p>second</p>"},
+			excludes: []string{"<toads>"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered, err := Render(tc.mimeType, tc.source)
+			require.NoError(t, err)
+			for _, s := range tc.contains {
+				require.Contains(t, rendered.HTML, s)
+			}
+			for _, s := range tc.excludes {
+				require.NotContains(t, rendered.HTML, s)
+			}
+		})
+	}
+}
+
+func TestRenderUnsupported(t *testing.T) {
+	_, err := Render("application/pdf", "%PDF")
+	require.ErrorIs(t, err, ErrUnsupported)
+	require.False(t, Supported("application/pdf"))
+	require.True(t, Supported("Text/Markdown"))
+}
+
+func TestTableOfContents(t *testing.T) {
+	rendered, err := Render("text/html", `<h2>Intro</h2><h3>Ça <em>va</em>?</h3><h2>Intro</h2><h2>!!</h2>`)
+	require.NoError(t, err)
+
+	require.Equal(t, []Heading{
+		{Level: 2, ID: "intro", Text: "Intro"},
+		{Level: 3, ID: "ça-va", Text: "Ça va?"},
+		{Level: 2, ID: "intro-2", Text: "Intro"},
+		{Level: 2, ID: "section", Text: "!!"},
+	}, rendered.TOC)
+}
+
+func TestWordCount(t *testing.T) {
+	rendered, err := Render("text/html", "<p>one <b>tw</b>o</p><p>three</p><ul><li>four</li><li>five</li></ul>")
+	require.NoError(t, err)
+	require.Equal(t, 5, rendered.WordCount)
+
+	rendered, err = Render("text/plain", strings.Repeat("word ", WordsPerMinute*2+1))
+	require.NoError(t, err)
+	require.Equal(t, WordsPerMinute*2+1, rendered.WordCount)
+	require.Equal(t, 3, rendered.ReadingTime)
+
+	rendered, err = Render("text/plain", "")
+	require.NoError(t, err)
+	require.Equal(t, Rendered{HTML: "", TOC: []Heading{}}, rendered)
+}
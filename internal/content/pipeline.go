@@ -0,0 +1,71 @@
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/reflection/frog_blossom_db/internal/cache"
+	"golang.org/x/sync/singleflight"
+)
+
+// version is part of every key, so a change to the output doesn't serve
+// entries rendered before it from a shared backend
+const version = "1"
+
+// Pipeline renders bodies once and serves them from backend afterwards.
+// Entries are keyed on the hash of the body and its mime type, so an edit
+// is a new entry and nothing needs to be invalidated; ttl only bounds how
+// long unused entries are kept. Without a backend every call renders.
+type Pipeline struct {
+	backend cache.Backend
+	ttl     time.Duration
+	group   singleflight.Group
+}
+
+func NewPipeline(backend cache.Backend, ttl time.Duration) *Pipeline {
+	return &Pipeline{backend: backend, ttl: ttl}
+}
+
+// Key is the cache key of source rendered as mimeType
+func Key(mimeType, source string) string {
+	sum := sha256.Sum256([]byte(version + "\x00" + mediaType(mimeType) + "\x00" + source))
+	return "content:" + hex.EncodeToString(sum[:])
+}
+
+// Render renders source as mimeType, or returns the render cached for it.
+// Concurrent misses for the same body render it once.
+func (p *Pipeline) Render(ctx context.Context, mimeType, source string) (Rendered, error) {
+	if p.backend == nil || !Supported(mimeType) {
+		return Render(mimeType, source)
+	}
+
+	key := Key(mimeType, source)
+	if data, found, err := p.backend.Get(ctx, key); err != nil {
+		slog.WarnContext(ctx, "content cache get failed", slog.String("key", key), slog.Any("error", err))
+	} else if found {
+		var rendered Rendered
+		if err := json.Unmarshal(data, &rendered); err == nil {
+			return rendered, nil
+		}
+	}
+
+	value, err, _ := p.group.Do(key, func() (any, error) {
+		rendered, err := Render(mimeType, source)
+		if err != nil {
+			return rendered, err
+		}
+		data, err := json.Marshal(rendered)
+		if err == nil {
+			err = p.backend.Set(ctx, key, data, p.ttl, nil)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "content cache set failed", slog.String("key", key), slog.Any("error", err))
+		}
+		return rendered, nil
+	})
+	return value.(Rendered), err
+}
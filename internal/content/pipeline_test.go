@@ -0,0 +1,58 @@
+package content
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reflection/frog_blossom_db/internal/cache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineCachesByContent(t *testing.T) {
+	// Arrange
+	backend := cache.NewLRU(10)
+	pipeline := NewPipeline(backend, time.Minute)
+	ctx := context.Background()
+
+	// Act
+	first, err := pipeline.Render(ctx, "text/markdown", "# Frogs")
+	require.NoError(t, err)
+	again, err := pipeline.Render(ctx, "text/markdown", "# Frogs")
+	require.NoError(t, err)
+
+	// Assert
+	require.Equal(t, first, again)
+	require.Equal(t, 1, backend.Len())
+	_, found, err := backend.Get(ctx, Key("text/markdown", "# Frogs"))
+	require.NoError(t, err)
+	require.True(t, found)
+
+	// another body or mime type is another entry
+	_, err = pipeline.Render(ctx, "text/markdown", "# Toads")
+	require.NoError(t, err)
+	plain, err := pipeline.Render(ctx, "text/plain", "# Frogs")
+	require.NoError(t, err)
+	require.Equal(t, "<p># Frogs</p>\n", plain.HTML)
+	require.Equal(t, 3, backend.Len())
+}
+
+func TestPipelineServesCachedEntries(t *testing.T) {
+	backend := cache.NewLRU(10)
+	pipeline := NewPipeline(backend, time.Minute)
+	ctx := context.Background()
+	require.NoError(t, backend.Set(ctx, Key("text/html", "<p>a</p>"), []byte(`{"html":"cached","toc":[],"word_count":9,"reading_time_minutes":1}`), time.Minute, nil))
+
+	rendered, err := pipeline.Render(ctx, "text/html; charset=utf-8", "<p>a</p>")
+	require.NoError(t, err)
+	require.Equal(t, Rendered{HTML: "cached", TOC: []Heading{}, WordCount: 9, ReadingTime: 1}, rendered)
+}
+
+func TestPipelineUnsupported(t *testing.T) {
+	backend := cache.NewLRU(10)
+	pipeline := NewPipeline(backend, time.Minute)
+
+	_, err := pipeline.Render(context.Background(), "application/pdf", "%PDF")
+	require.ErrorIs(t, err, ErrUnsupported)
+	require.Zero(t, backend.Len())
+}
@@ -0,0 +1,130 @@
+package content
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// inline elements don't separate words, so "<b>frog</b>s" is one word
+var inline = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Cite: true, atom.Code: true,
+	atom.Del: true, atom.Dfn: true, atom.Em: true, atom.I: true, atom.Ins: true,
+	atom.Kbd: true, atom.Mark: true, atom.Q: true, atom.S: true, atom.Samp: true,
+	atom.Small: true, atom.Span: true, atom.Strike: true, atom.Strong: true,
+	atom.Sub: true, atom.Sup: true, atom.U: true, atom.Var: true,
+}
+
+// annotate gives every heading of the sanitized body an anchor, replacing
+// any id it had, and lists them in order with the body's word count
+func annotate(body string) (Rendered, error) {
+	container := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(body), container)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	rendered := Rendered{TOC: []Heading{}}
+	slugs := map[string]int{}
+	var text strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			text.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			if level, ok := headingLevels[n.DataAtom]; ok {
+				title := strings.Join(strings.Fields(textOf(n)), " ")
+				id := uniqueSlug(slugs, slugify(title))
+				setAttr(n, "id", id)
+				rendered.TOC = append(rendered.TOC, Heading{Level: level, ID: id, Text: title})
+			}
+		}
+		if !inline[n.DataAtom] {
+			text.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if !inline[n.DataAtom] {
+			text.WriteByte(' ')
+		}
+	}
+
+	var out strings.Builder
+	for _, n := range nodes {
+		walk(n)
+		if err := html.Render(&out, n); err != nil {
+			return Rendered{}, err
+		}
+	}
+	rendered.HTML = out.String()
+	rendered.WordCount = len(strings.Fields(text.String()))
+	rendered.ReadingTime = readingTime(rendered.WordCount)
+	return rendered, nil
+}
+
+func textOf(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var text strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		text.WriteString(textOf(c))
+	}
+	return text.String()
+}
+
+func setAttr(n *html.Node, key, value string) {
+	for i := range n.Attr {
+		if n.Attr[i].Key == key {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: value})
+}
+
+// slugify keeps the letters and digits of title, lower case, with single
+// dashes between the words
+func slugify(title string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if slug.Len() == 0 {
+		return "section"
+	}
+	return slug.String()
+}
+
+// uniqueSlug numbers repeats of a slug: intro, intro-2, intro-3
+func uniqueSlug(seen map[string]int, slug string) string {
+	seen[slug]++
+	if n := seen[slug]; n > 1 {
+		candidate := slug + "-" + strconv.Itoa(n)
+		if _, taken := seen[candidate]; !taken {
+			seen[candidate] = 1
+			return candidate
+		}
+		return uniqueSlug(seen, candidate)
+	}
+	return slug
+}
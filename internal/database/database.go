@@ -0,0 +1,105 @@
+// Package database opens the Postgres connection pool with the configured
+// limits and server-side statement timeout.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reflection/frog_blossom_db/config"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// Open creates the pool described by config. Like sql.Open it does not
+// connect; the first query or a ping does.
+func Open(config config.Config) (*sql.DB, error) {
+	return open(config, config.DBSource)
+}
+
+// OpenReplicas creates a pool per DB_REPLICA_SOURCES entry with the same
+// limits and statement timeout as the primary
+func OpenReplicas(config config.Config) ([]*sql.DB, error) {
+	var conns []*sql.DB
+	for i, source := range config.DBReplicaSources {
+		conn, err := open(config, source)
+		if err != nil {
+			for _, c := range conns {
+				c.Close()
+			}
+			return nil, fmt.Errorf("replica %d: %w", i+1, err)
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+// ErrBypassesRLS is returned by CheckRole for roles row-level security
+// doesn't apply to
+var ErrBypassesRLS = errors.New("database role bypasses row-level security")
+
+// CheckRole makes sure the role conn logs in as is subject to row-level
+// security. Superusers and BYPASSRLS roles see the rows of every site, so
+// the app must connect as a member of frog_app instead.
+func CheckRole(ctx context.Context, conn *sql.DB) error {
+	var role string
+	var superuser, bypassRLS bool
+	err := conn.QueryRowContext(ctx,
+		`SELECT rolname, rolsuper, rolbypassrls FROM pg_roles WHERE rolname = current_user`,
+	).Scan(&role, &superuser, &bypassRLS)
+	if err != nil {
+		return fmt.Errorf("look up database role: %w", err)
+	}
+	if superuser || bypassRLS {
+		return fmt.Errorf("%w: %s is a superuser or BYPASSRLS, connect as a member of frog_app", ErrBypassesRLS, role)
+	}
+	return nil
+}
+
+func open(config config.Config, source string) (*sql.DB, error) {
+	source, err := WithStatementTimeout(source, config.DBStatementTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := db.OpenScoped(config.DBDriver, source)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetMaxOpenConns(config.DBMaxOpenConns)
+	conn.SetMaxIdleConns(config.DBMaxIdleConns)
+	conn.SetConnMaxLifetime(config.DBConnMaxLifetime)
+
+	return conn, nil
+}
+
+// WithStatementTimeout adds statement_timeout to a lib/pq data source, in
+// URL or key=value form. lib/pq sends unknown parameters to the server as
+// run-time settings, so every connection of the pool starts with the
+// timeout applied and queries running longer are cancelled by Postgres.
+// A zero timeout returns source unchanged.
+func WithStatementTimeout(source string, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		return source, nil
+	}
+	ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+
+	if strings.HasPrefix(source, "postgres://") || strings.HasPrefix(source, "postgresql://") {
+		u, err := url.Parse(source)
+		if err != nil {
+			return "", fmt.Errorf("parse DB_SOURCE: %w", err)
+		}
+		query := u.Query()
+		query.Set("statement_timeout", ms)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+
+	return strings.TrimSpace(source + " statement_timeout=" + ms), nil
+}
@@ -0,0 +1,207 @@
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// Site creates a site on a random domain
+func (f *Factory) Site(overrides ...func(*db.CreateSiteParams)) db.Site {
+	f.tb.Helper()
+
+	args := db.CreateSiteParams{
+		Domain: f.String(8) + ".com",
+		Name:   f.Sentence(2),
+	}
+	for _, override := range overrides {
+		override(&args)
+	}
+
+	site, err := f.store.CreateSite(context.Background(), args)
+	require.NoError(f.tb, err)
+	return site
+}
+
+// Member adds user to the site with role
+func (f *Factory) Member(siteID, userID int64, role db.SiteRole) db.SiteMember {
+	f.tb.Helper()
+
+	member, err := f.store.UpsertSiteMember(context.Background(), db.UpsertSiteMemberParams{
+		SiteID: siteID,
+		UserID: userID,
+		Role:   role,
+	})
+	require.NoError(f.tb, err)
+	return member
+}
+
+// SiteUser creates a user who is an editor of the site
+func (f *Factory) SiteUser(siteID int64, overrides ...func(*db.CreateUsersParams)) db.User {
+	f.tb.Helper()
+
+	user := f.User(overrides...)
+	f.Member(siteID, user.ID, db.SiteRoleEditor)
+	return user
+}
+
+// UserParams builds random CreateUsersParams; overrides run last
+func (f *Factory) UserParams(overrides ...func(*db.CreateUsersParams)) db.CreateUsersParams {
+	args := db.CreateUsersParams{
+		Username:    f.Username(),
+		Email:       f.Email(),
+		Password:    f.String(12),
+		Role:        "user",
+		FirstName:   f.String(6),
+		LastName:    f.String(8),
+		UserUrl:     sql.NullString{String: f.URL(), Valid: true},
+		Description: sql.NullString{String: f.Sentence(12), Valid: true},
+		UpdatedAt:   time.Now().UTC(),
+		IsDeleted:   sql.NullBool{Bool: false, Valid: true},
+	}
+	for _, override := range overrides {
+		override(&args)
+	}
+	return args
+}
+
+// User creates a user
+func (f *Factory) User(overrides ...func(*db.CreateUsersParams)) db.User {
+	f.tb.Helper()
+
+	user, err := f.store.CreateUsers(context.Background(), f.UserParams(overrides...))
+	require.NoError(f.tb, err)
+	return user
+}
+
+// PostParams builds random CreatePostsParams. A site is created when the
+// overrides leave SiteID unset, and an author who edits it when they leave
+// AuthorID unset.
+func (f *Factory) PostParams(overrides ...func(*db.CreatePostsParams)) db.CreatePostsParams {
+	f.tb.Helper()
+
+	now := time.Now().UTC()
+	args := db.CreatePostsParams{
+		Title:        f.Sentence(4),
+		Content:      f.Sentence(40),
+		Url:          f.URL(),
+		UpdatedAt:    now,
+		Status:       db.PostStatusDraft,
+		PublishedAt:  now,
+		EditedAt:     now,
+		PostMimeType: "text/plain",
+	}
+	for _, override := range overrides {
+		override(&args)
+	}
+
+	if args.SiteID == 0 {
+		args.SiteID = f.Site().ID
+	}
+	if args.AuthorID == 0 {
+		author := f.SiteUser(args.SiteID)
+		args.AuthorID = author.ID
+		setIfEmpty(&args.PostAuthor, author.Username)
+		setIfEmpty(&args.PublishedBy, author.Username)
+		setIfEmpty(&args.UpdatedBy, author.Username)
+	}
+	return args
+}
+
+// Post creates a post and, if needed, its site and author
+func (f *Factory) Post(overrides ...func(*db.CreatePostsParams)) db.Post {
+	f.tb.Helper()
+
+	post, err := f.store.CreatePosts(context.Background(), f.PostParams(overrides...))
+	require.NoError(f.tb, err)
+	return post
+}
+
+// PageParams builds random CreatePagesParams. A site is created when the
+// overrides leave SiteID unset, and an author who edits it when they leave
+// AuthorID unset.
+func (f *Factory) PageParams(overrides ...func(*db.CreatePagesParams)) db.CreatePagesParams {
+	f.tb.Helper()
+
+	identifier := f.String(8)
+	args := db.CreatePagesParams{
+		Title:          f.Sentence(3),
+		Url:            "/" + identifier,
+		MenuOrder:      f.Int(0, 100),
+		PageIdentifier: identifier,
+	}
+	for _, override := range overrides {
+		override(&args)
+	}
+
+	if args.SiteID == 0 {
+		args.SiteID = f.Site().ID
+	}
+	if args.AuthorID == 0 {
+		author := f.SiteUser(args.SiteID)
+		args.AuthorID = author.ID
+		setIfEmpty(&args.PageAuthor, author.Username)
+	}
+	return args
+}
+
+// Page creates a page and, if needed, its site and author
+func (f *Factory) Page(overrides ...func(*db.CreatePagesParams)) db.Page {
+	f.tb.Helper()
+
+	page, err := f.store.CreatePages(context.Background(), f.PageParams(overrides...))
+	require.NoError(f.tb, err)
+	return page
+}
+
+// MetaParams builds random CreateMetaParams. A site, and a page and a post
+// on it, are created when the overrides leave SiteID, PageID and PostsID
+// unset.
+func (f *Factory) MetaParams(overrides ...func(*db.CreateMetaParams)) db.CreateMetaParams {
+	f.tb.Helper()
+
+	args := db.CreateMetaParams{
+		MetaTitle:       sql.NullString{String: f.Sentence(4), Valid: true},
+		MetaDescription: sql.NullString{String: f.Sentence(12), Valid: true},
+		MetaRobots:      sql.NullString{String: "index, follow", Valid: true},
+		MetaOgImage:     sql.NullString{String: f.URL() + ".jpg", Valid: true},
+		Locale:          sql.NullString{String: "en_US", Valid: true},
+		MetaKey:         f.String(10),
+		MetaValue:       f.String(10),
+	}
+	for _, override := range overrides {
+		override(&args)
+	}
+
+	if args.SiteID == 0 {
+		args.SiteID = f.Site().ID
+	}
+	if !args.PageID.Valid {
+		page := f.Page(func(p *db.CreatePagesParams) { p.SiteID = args.SiteID })
+		args.PageID = sql.NullInt64{Int64: page.ID, Valid: true}
+	}
+	if !args.PostsID.Valid {
+		post := f.Post(func(p *db.CreatePostsParams) { p.SiteID = args.SiteID })
+		args.PostsID = sql.NullInt64{Int64: post.ID, Valid: true}
+	}
+	return args
+}
+
+// Meta creates a meta row and, if needed, its site and the page and post it
+// describes
+func (f *Factory) Meta(overrides ...func(*db.CreateMetaParams)) db.Meta {
+	f.tb.Helper()
+
+	meta, err := f.store.CreateMeta(context.Background(), f.MetaParams(overrides...))
+	require.NoError(f.tb, err)
+	return meta
+}
+
+func setIfEmpty(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
@@ -0,0 +1,153 @@
+// Package fixtures builds deterministic test data.
+//
+// Every test gets its own random stream derived from a base seed and the
+// test name, so a failing test produces the same rows when re-run on its
+// own or alongside others. The base seed is logged when a test fails and
+// can be replayed with FIXTURES_SEED:
+//
+//	FIXTURES_SEED=1718000000000000000 go test ./db/sqlc -run TestCreatePostsTx
+package fixtures
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// SeedEnv is the environment variable read for the base seed
+const SeedEnv = "FIXTURES_SEED"
+
+const alphabet = "abcdefghijklmnopqrstuvwxyz"
+
+// Creator is the subset of the store the factories write through
+type Creator interface {
+	CreateSite(ctx context.Context, arg db.CreateSiteParams) (db.Site, error)
+	UpsertSiteMember(ctx context.Context, arg db.UpsertSiteMemberParams) (db.SiteMember, error)
+	CreateUsers(ctx context.Context, arg db.CreateUsersParams) (db.User, error)
+	CreatePosts(ctx context.Context, arg db.CreatePostsParams) (db.Post, error)
+	CreatePages(ctx context.Context, arg db.CreatePagesParams) (db.Page, error)
+	CreateMeta(ctx context.Context, arg db.CreateMetaParams) (db.Meta, error)
+}
+
+// Factory hands out random values and rows for a single test
+type Factory struct {
+	tb    testing.TB
+	store Creator
+	seed  int64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+var (
+	baseSeed     int64
+	baseSeedOnce sync.Once
+
+	registryMu sync.Mutex
+	registry   = map[testing.TB]*Factory{}
+)
+
+// Seed returns the base seed of this test binary, read from FIXTURES_SEED
+// or taken from the clock when unset
+func Seed() int64 {
+	baseSeedOnce.Do(func() {
+		if v := os.Getenv(SeedEnv); v != "" {
+			seed, err := strconv.ParseInt(v, 10, 64)
+			if err == nil {
+				baseSeed = seed
+				return
+			}
+		}
+		baseSeed = time.Now().UnixNano()
+	})
+	return baseSeed
+}
+
+// For returns the factory of tb, creating it on first use. Helpers called
+// from the same test share one stream, so they never repeat values.
+func For(tb testing.TB, store Creator) *Factory {
+	tb.Helper()
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if f, ok := registry[tb]; ok {
+		return f
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(tb.Name()))
+	f := &Factory{
+		tb:    tb,
+		store: store,
+		seed:  Seed(),
+		rand:  rand.New(rand.NewSource(Seed() ^ int64(h.Sum64()))),
+	}
+	registry[tb] = f
+
+	tb.Cleanup(func() {
+		if tb.Failed() {
+			tb.Logf("fixtures: replay with %s=%d", SeedEnv, f.seed)
+		}
+		registryMu.Lock()
+		delete(registry, tb)
+		registryMu.Unlock()
+	})
+	return f
+}
+
+// Int returns a random number between min and max, inclusive
+func (f *Factory) Int(min, max int64) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return min + f.rand.Int63n(max-min+1)
+}
+
+// String returns a random lowercase string of length n
+func (f *Factory) String(n int) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteByte(alphabet[f.rand.Intn(len(alphabet))])
+	}
+	return sb.String()
+}
+
+// Bool returns a random boolean
+func (f *Factory) Bool() bool {
+	return f.Int(0, 1) == 1
+}
+
+// Username returns a random username
+func (f *Factory) Username() string {
+	return f.String(10)
+}
+
+// Email returns a random email address
+func (f *Factory) Email() string {
+	return f.String(8) + "@" + f.String(6) + ".com"
+}
+
+// URL returns a random https URL
+func (f *Factory) URL() string {
+	return "https://" + f.String(8) + ".com/" + f.String(6)
+}
+
+// Sentence returns n random words separated by spaces
+func (f *Factory) Sentence(n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = f.String(int(f.Int(3, 9)))
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,99 @@
+package fixtures
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCreator struct {
+	sites   []db.CreateSiteParams
+	members []db.UpsertSiteMemberParams
+	users   []db.CreateUsersParams
+}
+
+func (c *fakeCreator) CreateSite(_ context.Context, arg db.CreateSiteParams) (db.Site, error) {
+	c.sites = append(c.sites, arg)
+	return db.Site{ID: int64(len(c.sites)), Domain: arg.Domain}, nil
+}
+
+func (c *fakeCreator) UpsertSiteMember(_ context.Context, arg db.UpsertSiteMemberParams) (db.SiteMember, error) {
+	c.members = append(c.members, arg)
+	return db.SiteMember{SiteID: arg.SiteID, UserID: arg.UserID, Role: arg.Role}, nil
+}
+
+func (c *fakeCreator) CreateUsers(_ context.Context, arg db.CreateUsersParams) (db.User, error) {
+	c.users = append(c.users, arg)
+	return db.User{ID: int64(len(c.users)), Username: arg.Username}, nil
+}
+
+func (c *fakeCreator) CreatePosts(_ context.Context, arg db.CreatePostsParams) (db.Post, error) {
+	return db.Post{ID: 1, SiteID: arg.SiteID, AuthorID: arg.AuthorID, PostAuthor: arg.PostAuthor}, nil
+}
+
+func (c *fakeCreator) CreatePages(_ context.Context, arg db.CreatePagesParams) (db.Page, error) {
+	return db.Page{ID: 1, SiteID: arg.SiteID, AuthorID: arg.AuthorID}, nil
+}
+
+func (c *fakeCreator) CreateMeta(_ context.Context, arg db.CreateMetaParams) (db.Meta, error) {
+	return db.Meta{ID: 1, SiteID: arg.SiteID, PageID: arg.PageID, PostsID: arg.PostsID}, nil
+}
+
+func TestForSharesStreamWithinTest(t *testing.T) {
+	store := &fakeCreator{}
+
+	a := For(t, store)
+	b := For(t, store)
+	require.Same(t, a, b)
+	require.NotEqual(t, a.Username(), b.Username())
+}
+
+func TestStreamIsReproducible(t *testing.T) {
+	seed := int64(42)
+	first := &Factory{tb: t, rand: rand.New(rand.NewSource(seed))}
+	second := &Factory{tb: t, rand: rand.New(rand.NewSource(seed))}
+
+	require.Equal(t, first.UserParams().Username, second.UserParams().Username)
+	require.Equal(t, first.Int(0, 1000), second.Int(0, 1000))
+}
+
+func TestPostParamsCreatesAuthor(t *testing.T) {
+	store := &fakeCreator{}
+	f := For(t, store)
+
+	args := f.PostParams()
+	require.Len(t, store.users, 1)
+	require.Equal(t, int64(1), args.AuthorID)
+	require.Equal(t, []db.UpsertSiteMemberParams{{SiteID: args.SiteID, UserID: 1, Role: db.SiteRoleEditor}}, store.members)
+	require.Equal(t, store.users[0].Username, args.PostAuthor)
+	require.Equal(t, store.users[0].Username, args.PublishedBy)
+}
+
+func TestPostParamsKeepsOverrides(t *testing.T) {
+	store := &fakeCreator{}
+	f := For(t, store)
+
+	args := f.PostParams(func(p *db.CreatePostsParams) {
+		p.AuthorID = 7
+		p.PostAuthor = "frog"
+		p.Title = "Blossom"
+	})
+	require.Empty(t, store.users)
+	require.Equal(t, int64(7), args.AuthorID)
+	require.Equal(t, "frog", args.PostAuthor)
+	require.Equal(t, "Blossom", args.Title)
+}
+
+func TestMetaParamsCreatesPageAndPost(t *testing.T) {
+	store := &fakeCreator{}
+	f := For(t, store)
+
+	args := f.MetaParams()
+	require.True(t, args.PageID.Valid)
+	require.True(t, args.PostsID.Valid)
+	require.Len(t, store.users, 2)
+	require.Len(t, store.sites, 1, "page and post share the site of the meta")
+}
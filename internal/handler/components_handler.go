@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/components"
+	"github.com/reflection/frog_blossom_db/internal/validation"
+)
+
+func ListComponentTypesHandler(registry *components.Registry) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, registry.Types())
+	}
+}
+
+type pageComponentURI struct {
+	ID          int64 `uri:"id" binding:"required,min=1"`
+	ComponentID int64 `uri:"component_id" binding:"required,min=1"`
+}
+
+// pageComponentsResult renders the outcome of a component edit, mapping
+// a missing page to 404
+func pageComponentsResult(ctx *gin.Context, result db.PageComponentsTxResult, err error) {
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.Error(apperr.NotFound("page not found", err))
+			return
+		}
+
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func ListPageComponentsHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var req getPagesRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		if _, err := store.GetPages(ctx, db.GetPagesParams{SiteID: site.ID, ID: req.ID}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("page not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+
+		components, err := store.ListPageComponents(ctx, req.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if components == nil {
+			components = []db.PageComponent{}
+		}
+		ctx.JSON(http.StatusOK, components)
+	}
+}
+
+type addPageComponentRequest struct {
+	Type  string          `json:"type" binding:"required,max=64"`
+	Value json.RawMessage `json:"value" binding:"required"`
+	// Position defaults to after the last component
+	Position *int32 `json:"position" binding:"omitempty,min=0"`
+}
+
+func AddPageComponentHandler(store db.Store, registry *components.Registry) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri getPagesRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		var req addPageComponentRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		if err := registry.Validate(req.Type, req.Value); err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		result, err := store.AddPageComponentTx(ctx, db.AddPageComponentTxParams{
+			SiteID:   site.ID,
+			PageID:   uri.ID,
+			Type:     req.Type,
+			Value:    req.Value,
+			Position: req.Position,
+		})
+		pageComponentsResult(ctx, result, err)
+	}
+}
+
+type movePageComponentRequest struct {
+	Position *int32 `json:"position" binding:"required,min=0"`
+}
+
+func MovePageComponentHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri pageComponentURI
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		var req movePageComponentRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		result, err := store.MovePageComponentTx(ctx, db.MovePageComponentTxParams{
+			SiteID:      site.ID,
+			PageID:      uri.ID,
+			ComponentID: uri.ComponentID,
+			Position:    *req.Position,
+		})
+		pageComponentsResult(ctx, result, err)
+	}
+}
+
+func RemovePageComponentHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri pageComponentURI
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		result, err := store.RemovePageComponentTx(ctx, db.RemovePageComponentTxParams{
+			SiteID:      site.ID,
+			PageID:      uri.ID,
+			ComponentID: uri.ComponentID,
+		})
+		pageComponentsResult(ctx, result, err)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// seedComponents adds a hero and a rich-text component to the seeded page
+func seedComponents(store *fakeStore) {
+	seedPage(store)
+	for _, component := range []db.AddPageComponentTxParams{
+		{SiteID: testSite.ID, PageID: 3, Type: "hero", Value: json.RawMessage(`{"title": "Welcome"}`)},
+		{SiteID: testSite.ID, PageID: 3, Type: "rich-text", Value: json.RawMessage(`{"format": "markdown", "body": "Ribbit."}`)},
+	} {
+		if _, err := store.AddPageComponentTx(context.Background(), component); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func TestListComponentTypesHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/component-types",
+			status: http.StatusOK,
+			golden: "list_component_types_ok",
+		},
+	})
+}
+
+func TestListPageComponentsHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/3/components",
+			setup:  seedComponents,
+			status: http.StatusOK,
+			golden: "list_page_components_ok",
+		},
+		{
+			name:   "Empty",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/3/components",
+			setup:  seedPage,
+			status: http.StatusOK,
+			golden: "list_page_components_empty",
+		},
+		{
+			name:   "PageNotFound",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/42/components",
+			status: http.StatusNotFound,
+			golden: "list_page_components_not_found",
+		},
+	})
+}
+
+func TestAddPageComponentHandler(t *testing.T) {
+	gallery := map[string]any{
+		"columns": 2,
+		"images":  []map[string]string{{"src": "/pond.png", "alt": "Pond"}},
+	}
+
+	runCases(t, []apiCase{
+		{
+			name:   "Append",
+			method: http.MethodPost,
+			path:   "/api/v1/pages/3/components",
+			body:   map[string]any{"type": "gallery", "value": gallery},
+			setup:  seedComponents,
+			status: http.StatusOK,
+			golden: "add_page_component_append",
+		},
+		{
+			name:   "AtPosition",
+			method: http.MethodPost,
+			path:   "/api/v1/pages/3/components",
+			body:   map[string]any{"type": "gallery", "value": gallery, "position": 0},
+			setup:  seedComponents,
+			status: http.StatusOK,
+			golden: "add_page_component_at_position",
+		},
+		{
+			name:   "InvalidValue",
+			method: http.MethodPost,
+			path:   "/api/v1/pages/3/components",
+			body: map[string]any{"type": "gallery", "value": map[string]any{
+				"columns": 12,
+				"images":  []map[string]string{{"alt": "no source"}},
+			}},
+			setup:  seedComponents,
+			status: http.StatusBadRequest,
+			golden: "add_page_component_invalid_value",
+		},
+		{
+			name:   "UnknownType",
+			method: http.MethodPost,
+			path:   "/api/v1/pages/3/components",
+			body:   map[string]any{"type": "carousel", "value": map[string]any{}},
+			setup:  seedComponents,
+			status: http.StatusBadRequest,
+			golden: "add_page_component_unknown_type",
+		},
+		{
+			name:   "PageNotFound",
+			method: http.MethodPost,
+			path:   "/api/v1/pages/42/components",
+			body:   map[string]any{"type": "gallery", "value": gallery},
+			status: http.StatusNotFound,
+			golden: "add_page_component_page_not_found",
+		},
+	})
+}
+
+func TestMovePageComponentHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/3/components/10/position",
+			body:   map[string]any{"position": 1},
+			setup:  seedComponents,
+			status: http.StatusOK,
+			golden: "move_page_component_ok",
+		},
+		{
+			name:   "MissingPosition",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/3/components/10/position",
+			body:   map[string]any{},
+			setup:  seedComponents,
+			status: http.StatusBadRequest,
+			golden: "move_page_component_missing_position",
+		},
+		{
+			name:   "ComponentNotFound",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/3/components/99/position",
+			body:   map[string]any{"position": 0},
+			setup:  seedComponents,
+			status: http.StatusNotFound,
+			golden: "move_page_component_not_found",
+		},
+	})
+}
+
+func TestRemovePageComponentHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodDelete,
+			path:   "/api/v1/pages/3/components/10",
+			setup:  seedComponents,
+			status: http.StatusOK,
+			golden: "remove_page_component_ok",
+		},
+		{
+			name:   "ComponentNotFound",
+			method: http.MethodDelete,
+			path:   "/api/v1/pages/3/components/99",
+			setup:  seedComponents,
+			status: http.StatusNotFound,
+			golden: "remove_page_component_not_found",
+		},
+	})
+}
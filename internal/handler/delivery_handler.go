@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/locale"
+	"github.com/reflection/frog_blossom_db/internal/sitesettings"
+)
+
+// alternate is a language variant of delivered content, see
+// https://developers.google.com/search/docs/specialty/international/localized-versions
+type alternate struct {
+	Hreflang string `json:"hreflang"`
+	Href     string `json:"href"`
+}
+
+type deliveredPage struct {
+	Locale     string             `json:"locale"`
+	Page       db.Page            `json:"page"`
+	Components []db.PageComponent `json:"components"`
+	Alternates []alternate        `json:"alternates"`
+}
+
+// requestScheme is the scheme the client used, behind a proxy as well
+func requestScheme(ctx *gin.Context) string {
+	if proto := ctx.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		return proto
+	}
+	if ctx.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// variant is a page or post of a translation group
+type variant struct {
+	locale sql.NullString
+	url    string
+}
+
+// localeAlternates links every variant under its locale prefix. Variants
+// in the site language are served without one and are also the x-default.
+func localeAlternates(ctx *gin.Context, site db.Site, siteLocale string, variants []variant) []alternate {
+	origin := requestScheme(ctx) + "://" + site.Domain
+	alternates := make([]alternate, 0, len(variants)+1)
+	var fallback *alternate
+	for _, v := range variants {
+		hreflang := contentLocale(v.locale, siteLocale)
+		href := origin + "/" + hreflang + v.url
+		if hreflang == siteLocale {
+			href = origin + v.url
+			fallback = &alternate{Hreflang: "x-default", Href: href}
+		}
+		alternates = append(alternates, alternate{Hreflang: hreflang, Href: href})
+	}
+	if fallback != nil {
+		alternates = append(alternates, *fallback)
+	}
+	return alternates
+}
+
+func pageAlternates(ctx *gin.Context, site db.Site, siteLocale string, pages []db.Page) []alternate {
+	variants := make([]variant, len(pages))
+	for i, page := range pages {
+		variants[i] = variant{locale: page.Locale, url: page.Url}
+	}
+	return localeAlternates(ctx, site, siteLocale, variants)
+}
+
+func postAlternates(ctx *gin.Context, site db.Site, siteLocale string, posts []db.Post) []alternate {
+	variants := make([]variant, len(posts))
+	for i, post := range posts {
+		variants[i] = variant{locale: post.Locale, url: post.Url}
+	}
+	return localeAlternates(ctx, site, siteLocale, variants)
+}
+
+// contentLocale is the locale of a page or post; without one it is in the
+// site language
+func contentLocale(locale sql.NullString, siteLocale string) string {
+	if locale.Valid {
+		return locale.String
+	}
+	return siteLocale
+}
+
+// linkHeader renders alternates as an HTTP Link header
+func linkHeader(alternates []alternate) string {
+	links := make([]string, 0, len(alternates))
+	for _, a := range alternates {
+		links = append(links, "<"+a.Href+`>; rel="alternate"; hreflang="`+a.Hreflang+`"`)
+	}
+	return strings.Join(links, ", ")
+}
+
+// DeliverPageHandler serves the page at the URL path in the best locale
+// there is for the visitor. A locale prefix, as in /fr-CA/about, picks the
+// locale; otherwise Accept-Language does. Either falls back to the base
+// language and then the site language.
+func DeliverPageHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		settings, err := sitesettings.Load(ctx, store, site.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		siteLocale := settings.String("site_language")
+
+		path := ctx.Param("path")
+		negotiated := locale.FromAcceptLanguage(ctx.GetHeader("Accept-Language"))
+		find := func(url string, requested []string) (db.Page, error) {
+			return store.GetPageByUrlInLocale(ctx, db.GetPageByUrlInLocaleParams{
+				SiteID:  site.ID,
+				Url:     url,
+				Locales: locale.Chain(requested, siteLocale),
+			})
+		}
+
+		var page db.Page
+		tag, url, prefixed := locale.FromPath(path)
+		if prefixed {
+			page, err = find(url, []string{tag})
+		}
+		// a first segment such as /new can be a path as well as a locale
+		if !prefixed || errors.Is(err, sql.ErrNoRows) {
+			ctx.Header("Vary", "Accept-Language")
+			page, err = find(path, negotiated)
+		}
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("page not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+
+		variants, err := pageTranslations(ctx, store, page)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		components, err := store.ListPageComponents(ctx, page.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if components == nil {
+			components = []db.PageComponent{}
+		}
+
+		delivered := deliveredPage{
+			Locale:     contentLocale(page.Locale, siteLocale),
+			Page:       page,
+			Components: components,
+			Alternates: pageAlternates(ctx, site, siteLocale, variants),
+		}
+		ctx.Header("Content-Language", delivered.Locale)
+		ctx.Header("Link", linkHeader(delivered.Alternates))
+		ctx.JSON(http.StatusOK, delivered)
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+const aboutLinks = `<http://example.com/de/uber-uns>; rel="alternate"; hreflang="de", ` +
+	`<http://example.com/about>; rel="alternate"; hreflang="en", ` +
+	`<http://example.com/fr/a-propos>; rel="alternate"; hreflang="fr", ` +
+	`<http://example.com/about>; rel="alternate"; hreflang="x-default"`
+
+func TestDeliverPageHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:    "SiteLanguage",
+			method:  http.MethodGet,
+			path:    "/api/v1/delivery/about",
+			setup:   seedTranslations,
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "en", "Vary": "Accept-Language", "Link": aboutLinks},
+			golden:  "deliver_page_site_language",
+		},
+		{
+			name:    "PrefixFallsBackToLanguage",
+			method:  http.MethodGet,
+			path:    "/api/v1/delivery/fr-CA/about",
+			setup:   seedTranslations,
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "fr", "Vary": "", "Link": aboutLinks},
+			golden:  "deliver_page_prefix_fallback",
+		},
+		{
+			name:    "PrefixOnTranslatedUrl",
+			method:  http.MethodGet,
+			path:    "/api/v1/delivery/de/uber-uns",
+			setup:   seedTranslations,
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "de"},
+			golden:  "deliver_page_prefix_translated_url",
+		},
+		{
+			name:    "AcceptLanguage",
+			method:  http.MethodGet,
+			path:    "/api/v1/delivery/about",
+			header:  http.Header{"Accept-Language": {"ja;q=0.9, de-AT, en;q=0.5"}},
+			setup:   seedTranslations,
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "de", "Vary": "Accept-Language"},
+			golden:  "deliver_page_accept_language",
+		},
+		{
+			name:    "Untranslated",
+			method:  http.MethodGet,
+			path:    "/api/v1/delivery/fr/contact",
+			setup:   seedTranslations,
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "en", "Link": `<http://example.com/contact>; rel="alternate"; hreflang="en", <http://example.com/contact>; rel="alternate"; hreflang="x-default"`},
+			golden:  "deliver_page_untranslated",
+		},
+		{
+			name:   "PathLooksLikeLocale",
+			method: http.MethodGet,
+			path:   "/api/v1/delivery/new/arrivals",
+			setup: func(store *fakeStore) {
+				seedTranslations(store)
+				store.addPage(db.Page{ID: 30, SiteID: testSite.ID, Title: "New arrivals", Url: "/new/arrivals", PageIdentifier: "arrivals"}, nil)
+			},
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "en"},
+			golden:  "deliver_page_path_looks_like_locale",
+		},
+		{
+			name:   "OtherSiteLanguage",
+			method: http.MethodGet,
+			path:   "/api/v1/delivery/about",
+			setup: func(store *fakeStore) {
+				seedTranslations(store)
+				store.UpsertSiteSetting(context.Background(), db.UpsertSiteSettingParams{SiteID: testSite.ID, Key: "site_language", Value: json.RawMessage(`"fr"`)})
+			},
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "fr"},
+			golden:  "deliver_page_other_site_language",
+		},
+		{
+			name:   "NotFound",
+			method: http.MethodGet,
+			path:   "/api/v1/delivery/fr/missing",
+			setup:  seedTranslations,
+			status: http.StatusNotFound,
+			golden: "deliver_page_not_found",
+		},
+	})
+}
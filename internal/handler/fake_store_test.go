@@ -0,0 +1,821 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"time"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+)
+
+// fixedTime keeps timestamps in golden files stable
+var fixedTime = time.Date(2024, time.May, 27, 10, 0, 0, 0, time.UTC)
+
+// fakeStore is an in-memory db.Store for handler tests. Methods the tests do
+// not need fall through to the nil embedded Store and panic when called.
+type fakeStore struct {
+	db.Store
+
+	sites      map[int64]db.Site
+	members    map[int64]map[int64]db.SiteMember
+	users      map[int64]db.User
+	pages      map[int64]db.Page
+	posts      map[int64]db.Post
+	options    map[int64][]db.PageOption
+	components map[int64][]db.PageComponent
+	settings   map[int64]map[string]db.SiteSetting
+	media      map[int64]db.Media
+	// mediaInUse holds the media a post or meta refers to
+	mediaInUse map[int64]bool
+	nextID     int64
+
+	// err is returned by every method but GetSiteByDomain when set, so
+	// requests still reach the handler
+	err error
+}
+
+// testSite is the site requests are made on, see runCases. otherSite holds
+// rows that must not leak into it.
+var (
+	testSite  = db.Site{ID: 1, Domain: "example.com", Name: "Example", CreatedAt: fixedTime}
+	otherSite = db.Site{ID: 2, Domain: "other.example", Name: "Other", CreatedAt: fixedTime}
+)
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		sites:      map[int64]db.Site{testSite.ID: testSite, otherSite.ID: otherSite},
+		members:    map[int64]map[int64]db.SiteMember{},
+		users:      map[int64]db.User{},
+		pages:      map[int64]db.Page{},
+		posts:      map[int64]db.Post{},
+		options:    map[int64][]db.PageOption{},
+		components: map[int64][]db.PageComponent{},
+		settings:   map[int64]map[string]db.SiteSetting{},
+		media:      map[int64]db.Media{},
+		mediaInUse: map[int64]bool{},
+		nextID:     1,
+	}
+}
+
+func (s *fakeStore) CreateSite(_ context.Context, arg db.CreateSiteParams) (db.Site, error) {
+	if s.err != nil {
+		return db.Site{}, s.err
+	}
+	for _, site := range s.sites {
+		if site.Domain == arg.Domain {
+			return db.Site{}, apperr.Conflict("domain is already taken", nil)
+		}
+	}
+	site := db.Site{ID: int64(len(s.sites)) + 1, Domain: arg.Domain, Name: arg.Name, CreatedAt: fixedTime}
+	s.sites[site.ID] = site
+	return site, nil
+}
+
+func (s *fakeStore) GetSiteByDomain(_ context.Context, domain string) (db.Site, error) {
+	for _, site := range s.sites {
+		if site.Domain == domain {
+			return site, nil
+		}
+	}
+	return db.Site{}, sql.ErrNoRows
+}
+
+func (s *fakeStore) ListSites(_ context.Context, arg db.ListSitesParams) ([]db.Site, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var sites []db.Site
+	for _, site := range s.sites {
+		sites = append(sites, site)
+	}
+	sort.Slice(sites, func(i, j int) bool { return sites[i].ID < sites[j].ID })
+	start := min(int(arg.Offset), len(sites))
+	return sites[start:min(start+int(arg.Limit), len(sites))], nil
+}
+
+func (s *fakeStore) UpsertSiteMember(_ context.Context, arg db.UpsertSiteMemberParams) (db.SiteMember, error) {
+	if s.err != nil {
+		return db.SiteMember{}, s.err
+	}
+	if s.members[arg.SiteID] == nil {
+		s.members[arg.SiteID] = map[int64]db.SiteMember{}
+	}
+	member := db.SiteMember{SiteID: arg.SiteID, UserID: arg.UserID, Role: arg.Role, CreatedAt: fixedTime}
+	s.members[arg.SiteID][arg.UserID] = member
+	return member, nil
+}
+
+func (s *fakeStore) ListSiteMembers(_ context.Context, siteID int64) ([]db.SiteMember, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var members []db.SiteMember
+	for _, member := range s.members[siteID] {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+	return members, nil
+}
+
+func (s *fakeStore) DeleteSiteMember(_ context.Context, arg db.DeleteSiteMemberParams) (db.SiteMember, error) {
+	if s.err != nil {
+		return db.SiteMember{}, s.err
+	}
+	member, ok := s.members[arg.SiteID][arg.UserID]
+	if !ok {
+		return db.SiteMember{}, sql.ErrNoRows
+	}
+	delete(s.members[arg.SiteID], arg.UserID)
+	return member, nil
+}
+
+// siteAuthor mimics SQLStore: only editors and owners of the site write
+func (s *fakeStore) siteAuthor(ctx context.Context, siteID, userID int64) (db.User, error) {
+	if s.err != nil {
+		return db.User{}, s.err
+	}
+	member, ok := s.members[siteID][userID]
+	if !ok {
+		return db.User{}, sql.ErrNoRows
+	}
+	if member.Role == db.SiteRoleViewer {
+		return db.User{}, apperr.Forbidden("viewers can not write content", nil)
+	}
+	return s.GetUsers(ctx, userID)
+}
+
+// addUser stores the user as an editor of testSite
+func (s *fakeStore) addUser(user db.User) db.User {
+	if user.ID == 0 {
+		user.ID = s.nextID
+	}
+	if s.nextID <= user.ID {
+		s.nextID = user.ID + 1
+	}
+	user.CreatedAt = fixedTime
+	user.UpdatedAt = fixedTime
+	s.users[user.ID] = user
+	s.UpsertSiteMember(context.Background(), db.UpsertSiteMemberParams{SiteID: testSite.ID, UserID: user.ID, Role: db.SiteRoleEditor})
+	return user
+}
+
+func (s *fakeStore) CreateUsers(_ context.Context, arg db.CreateUsersParams) (db.User, error) {
+	if s.err != nil {
+		return db.User{}, s.err
+	}
+	return s.addUser(db.User{
+		Username:    arg.Username,
+		Email:       arg.Email,
+		Password:    arg.Password,
+		Role:        arg.Role,
+		FirstName:   arg.FirstName,
+		LastName:    arg.LastName,
+		UserUrl:     arg.UserUrl,
+		Description: arg.Description,
+		IsDeleted:   arg.IsDeleted,
+	}), nil
+}
+
+func (s *fakeStore) CreateSiteUserTx(ctx context.Context, args db.CreateSiteUserTxParams) (db.CreateSiteUserTxResult, error) {
+	user, err := s.CreateUsers(ctx, args.User)
+	if err != nil {
+		return db.CreateSiteUserTxResult{}, err
+	}
+	member, err := s.UpsertSiteMember(ctx, db.UpsertSiteMemberParams{SiteID: args.SiteID, UserID: user.ID, Role: args.Role})
+	return db.CreateSiteUserTxResult{User: user, Member: member}, err
+}
+
+func (s *fakeStore) GetSiteUser(ctx context.Context, arg db.GetSiteUserParams) (db.User, error) {
+	if s.err != nil {
+		return db.User{}, s.err
+	}
+	if _, ok := s.members[arg.SiteID][arg.ID]; !ok {
+		return db.User{}, sql.ErrNoRows
+	}
+	return s.GetUsers(ctx, arg.ID)
+}
+
+func (s *fakeStore) GetUsers(_ context.Context, id int64) (db.User, error) {
+	if s.err != nil {
+		return db.User{}, s.err
+	}
+	user, ok := s.users[id]
+	if !ok {
+		return db.User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (s *fakeStore) id() int64 {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+func (s *fakeStore) addPage(page db.Page, options []db.PageOptionParams) (db.Page, []db.PageOption) {
+	if page.ID == 0 {
+		page.ID = s.id()
+	}
+	if s.nextID <= page.ID {
+		s.nextID = page.ID + 1
+	}
+	s.pages[page.ID] = page
+	if options != nil {
+		s.options[page.ID] = nil
+		for i, option := range options {
+			s.options[page.ID] = append(s.options[page.ID], db.PageOption{
+				ID:       s.id(),
+				PageID:   page.ID,
+				Position: int32(i),
+				Name:     option.Name,
+				Type:     option.Type,
+				Value:    option.Value,
+				Required: option.Required,
+			})
+		}
+	}
+	return page, s.options[page.ID]
+}
+
+func (s *fakeStore) CreatePageTx(ctx context.Context, args db.CreateContentTxParams) (db.CreateContentTxResult, error) {
+	var result db.CreateContentTxResult
+	user, err := s.siteAuthor(ctx, args.SiteID, args.UserId)
+	if err != nil {
+		return result, err
+	}
+	result.User = user
+
+	for i, p := range args.Pages {
+		var options []db.PageOptionParams
+		if i < len(args.PageOptions) {
+			options = args.PageOptions[i]
+		}
+		page, pageOptions := s.addPage(db.Page{
+			SiteID:         args.SiteID,
+			AuthorID:       p.AuthorID,
+			PageAuthor:     p.PageAuthor,
+			Title:          p.Title,
+			Url:            p.Url,
+			MenuOrder:      p.MenuOrder,
+			PageIdentifier: p.PageIdentifier,
+		}, options)
+		result.Pages = append(result.Pages, page)
+		result.PageOptions = append(result.PageOptions, pageOptions)
+	}
+	return result, nil
+}
+
+func (s *fakeStore) CreatePostsTx(ctx context.Context, args db.CreateContentTxParams) (db.CreateContentTxResult, error) {
+	var result db.CreateContentTxResult
+	user, err := s.siteAuthor(ctx, args.SiteID, args.UserId)
+	if err != nil {
+		return result, err
+	}
+	result.User = user
+
+	for _, p := range args.Posts {
+		post := db.Post{
+			ID:              s.id(),
+			SiteID:          args.SiteID,
+			Title:           p.Title,
+			Content:         p.Content,
+			AuthorID:        p.AuthorID,
+			Url:             p.Url,
+			CreatedAt:       fixedTime,
+			UpdatedAt:       fixedTime,
+			Status:          p.Status,
+			PublishedAt:     fixedTime,
+			EditedAt:        fixedTime,
+			PostAuthor:      p.PostAuthor,
+			PostMimeType:    p.PostMimeType,
+			PublishedBy:     p.PublishedBy,
+			UpdatedBy:       p.UpdatedBy,
+			FeaturedMediaID: p.FeaturedMediaID,
+		}
+		s.posts[post.ID] = post
+		result.Posts = append(result.Posts, post)
+	}
+	for _, m := range args.Metas {
+		if !m.PageID.Valid && !m.PostsID.Valid && len(result.Posts) > 0 {
+			m.PostsID = sql.NullInt64{Int64: result.Posts[0].ID, Valid: true}
+		}
+		result.Metas = append(result.Metas, db.Meta{
+			ID:              s.id(),
+			SiteID:          args.SiteID,
+			PageID:          m.PageID,
+			PostsID:         m.PostsID,
+			MetaTitle:       m.MetaTitle,
+			MetaDescription: m.MetaDescription,
+			MetaRobots:      m.MetaRobots,
+			MetaOgImage:     m.MetaOgImage,
+			Locale:          m.Locale,
+			MetaKey:         m.MetaKey,
+			MetaValue:       m.MetaValue,
+			MetaOgImageID:   m.MetaOgImageID,
+		})
+	}
+	return result, nil
+}
+
+func (s *fakeStore) UpdatePageTx(ctx context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	var result db.UpdateContentTxResult
+	user, err := s.siteAuthor(ctx, args.SiteID, args.UserId)
+	if err != nil {
+		return result, err
+	}
+	result.User = user
+
+	page, err := s.GetPages(ctx, db.GetPagesParams{SiteID: args.SiteID, ID: *args.PageId})
+	if err != nil {
+		return result, err
+	}
+	result.PageId = &page
+
+	for i, p := range args.Pages {
+		var options []db.PageOptionParams
+		if i < len(args.PageOptions) {
+			options = args.PageOptions[i]
+		}
+		page := s.pages[p.ID]
+		page.AuthorID, page.PageAuthor = p.AuthorID, p.PageAuthor
+		page.Title, page.Url, page.MenuOrder = p.Title, p.Url, p.MenuOrder
+		page.PageIdentifier = p.PageIdentifier
+		page, pageOptions := s.addPage(page, options)
+		result.Pages = append(result.Pages, page)
+		result.PageOptions = append(result.PageOptions, pageOptions)
+	}
+	return result, nil
+}
+
+func (s *fakeStore) GetPages(_ context.Context, arg db.GetPagesParams) (db.Page, error) {
+	if s.err != nil {
+		return db.Page{}, s.err
+	}
+	page, ok := s.pages[arg.ID]
+	if !ok || page.SiteID != arg.SiteID {
+		return db.Page{}, sql.ErrNoRows
+	}
+	return page, nil
+}
+
+func (s *fakeStore) GetPosts(_ context.Context, arg db.GetPostsParams) (db.Post, error) {
+	if s.err != nil {
+		return db.Post{}, s.err
+	}
+	post, ok := s.posts[arg.ID]
+	if !ok || post.SiteID != arg.SiteID {
+		return db.Post{}, sql.ErrNoRows
+	}
+	return post, nil
+}
+
+func (s *fakeStore) ListPageOptions(_ context.Context, pageID int64) ([]db.PageOption, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.options[pageID], nil
+}
+
+// sortedPages returns the pages matching keep ordered like ListSitePages
+func (s *fakeStore) sortedPages(keep func(db.Page) bool) []db.Page {
+	var pages []db.Page
+	for _, page := range s.pages {
+		if keep(page) {
+			pages = append(pages, page)
+		}
+	}
+	sort.Slice(pages, func(i, j int) bool {
+		a, b := pages[i], pages[j]
+		if a.ParentID != b.ParentID {
+			return !a.ParentID.Valid || (b.ParentID.Valid && a.ParentID.Int64 < b.ParentID.Int64)
+		}
+		if a.MenuOrder != b.MenuOrder {
+			return a.MenuOrder < b.MenuOrder
+		}
+		return a.ID < b.ID
+	})
+	return pages
+}
+
+func (s *fakeStore) ListSitePages(_ context.Context, siteID int64) ([]db.Page, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.sortedPages(func(page db.Page) bool { return page.SiteID == siteID }), nil
+}
+
+func (s *fakeStore) GetPageSubtree(_ context.Context, arg db.GetPageSubtreeParams) ([]db.Page, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	inTree := map[int64]bool{}
+	var walk func(id int64)
+	walk = func(id int64) {
+		if page, ok := s.pages[id]; !ok || page.SiteID != arg.SiteID {
+			return
+		}
+		inTree[id] = true
+		for _, page := range s.pages {
+			if page.ParentID.Valid && page.ParentID.Int64 == id {
+				walk(page.ID)
+			}
+		}
+	}
+	walk(arg.ID)
+	return s.sortedPages(func(page db.Page) bool { return inTree[page.ID] }), nil
+}
+
+func (s *fakeStore) MovePageTx(ctx context.Context, args db.MovePageTxParams) (db.MovePageTxResult, error) {
+	page, err := s.GetPages(ctx, db.GetPagesParams{SiteID: args.SiteID, ID: args.PageID})
+	if err != nil {
+		return db.MovePageTxResult{}, err
+	}
+	page.ParentID = sql.NullInt64{}
+	if args.ParentID != nil {
+		page.ParentID = sql.NullInt64{Int64: *args.ParentID, Valid: true}
+	}
+	page.MenuOrder = args.MenuOrder
+	s.pages[page.ID] = page
+	return db.MovePageTxResult{Page: page}, nil
+}
+
+func (s *fakeStore) ReorderPagesTx(_ context.Context, args db.ReorderPagesTxParams) (db.ReorderPagesTxResult, error) {
+	var result db.ReorderPagesTxResult
+	if s.err != nil {
+		return result, s.err
+	}
+	for i, id := range args.PageIDs {
+		page := s.pages[id]
+		page.MenuOrder = int64(i)
+		s.pages[id] = page
+		result.Pages = append(result.Pages, page)
+	}
+	return result, nil
+}
+
+func (s *fakeStore) ListPageComponents(_ context.Context, pageID int64) ([]db.PageComponent, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.components[pageID], nil
+}
+
+// editComponents mimics SQLStore's locking edit: edit gets the page's
+// components and the result is renumbered
+func (s *fakeStore) editComponents(siteID, pageID, componentID int64, edit func(components []db.PageComponent, i int) ([]db.PageComponent, db.PageComponent)) (db.PageComponentsTxResult, error) {
+	var result db.PageComponentsTxResult
+	if s.err != nil {
+		return result, s.err
+	}
+	if page, ok := s.pages[pageID]; !ok || page.SiteID != siteID {
+		return result, sql.ErrNoRows
+	}
+
+	components := s.components[pageID]
+	i := -1
+	for j, component := range components {
+		if component.ID == componentID {
+			i = j
+		}
+	}
+	if componentID != 0 && i < 0 {
+		return result, apperr.NotFound("component not found on page", nil)
+	}
+
+	components, result.Component = edit(slices.Clone(components), i)
+	for j := range components {
+		components[j].Position = int32(j)
+		if components[j].ID == result.Component.ID {
+			result.Component = components[j]
+		}
+	}
+	s.components[pageID] = components
+	result.Components = components
+	return result, nil
+}
+
+func (s *fakeStore) AddPageComponentTx(_ context.Context, args db.AddPageComponentTxParams) (db.PageComponentsTxResult, error) {
+	return s.editComponents(args.SiteID, args.PageID, 0, func(components []db.PageComponent, _ int) ([]db.PageComponent, db.PageComponent) {
+		component := db.PageComponent{
+			ID:        s.id(),
+			PageID:    args.PageID,
+			Type:      args.Type,
+			Value:     args.Value,
+			CreatedAt: fixedTime,
+			UpdatedAt: fixedTime,
+		}
+		position := len(components)
+		if args.Position != nil {
+			position = min(int(*args.Position), position)
+		}
+		return slices.Insert(components, position, component), component
+	})
+}
+
+func (s *fakeStore) MovePageComponentTx(_ context.Context, args db.MovePageComponentTxParams) (db.PageComponentsTxResult, error) {
+	return s.editComponents(args.SiteID, args.PageID, args.ComponentID, func(components []db.PageComponent, i int) ([]db.PageComponent, db.PageComponent) {
+		component := components[i]
+		components = slices.Delete(components, i, i+1)
+		return slices.Insert(components, min(int(args.Position), len(components)), component), component
+	})
+}
+
+func (s *fakeStore) RemovePageComponentTx(_ context.Context, args db.RemovePageComponentTxParams) (db.PageComponentsTxResult, error) {
+	return s.editComponents(args.SiteID, args.PageID, args.ComponentID, func(components []db.PageComponent, i int) ([]db.PageComponent, db.PageComponent) {
+		component := components[i]
+		return slices.Delete(components, i, i+1), component
+	})
+}
+
+func (s *fakeStore) ListSiteSettings(_ context.Context, siteID int64) ([]db.SiteSetting, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var settings []db.SiteSetting
+	for _, setting := range s.settings[siteID] {
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+func (s *fakeStore) UpsertSiteSetting(_ context.Context, arg db.UpsertSiteSettingParams) (db.SiteSetting, error) {
+	if s.err != nil {
+		return db.SiteSetting{}, s.err
+	}
+	if s.settings[arg.SiteID] == nil {
+		s.settings[arg.SiteID] = map[string]db.SiteSetting{}
+	}
+	setting := db.SiteSetting{SiteID: arg.SiteID, Key: arg.Key, Value: arg.Value, UpdatedAt: fixedTime}
+	s.settings[arg.SiteID][arg.Key] = setting
+	return setting, nil
+}
+
+func (s *fakeStore) DeleteSiteSetting(_ context.Context, arg db.DeleteSiteSettingParams) error {
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.settings[arg.SiteID], arg.Key)
+	return nil
+}
+
+func (s *fakeStore) ListPageTranslations(_ context.Context, arg db.ListPageTranslationsParams) ([]db.Page, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	pages := s.sortedPages(func(page db.Page) bool {
+		return page.SiteID == arg.SiteID && page.TranslationGroupID == arg.TranslationGroupID
+	})
+	sort.SliceStable(pages, func(i, j int) bool { return pages[i].Locale.String < pages[j].Locale.String })
+	return pages, nil
+}
+
+// localeRank mirrors the ORDER BY of the InLocale queries: the position in
+// locales, with rows in none of them last
+func localeRank(locale sql.NullString, locales []string) int {
+	if i := slices.Index(locales, locale.String); locale.Valid && i >= 0 {
+		return i
+	}
+	return len(locales)
+}
+
+// inLocale returns the variant of source best matching locales
+func (s *fakeStore) inLocale(source db.Page, locales []string) db.Page {
+	best := source
+	for _, page := range s.pages {
+		if page.SiteID != source.SiteID || !source.TranslationGroupID.Valid || page.TranslationGroupID != source.TranslationGroupID {
+			continue
+		}
+		if localeRank(page.Locale, locales) < localeRank(best.Locale, locales) {
+			best = page
+		}
+	}
+	return best
+}
+
+func (s *fakeStore) GetPageByUrlInLocale(_ context.Context, arg db.GetPageByUrlInLocaleParams) (db.Page, error) {
+	if s.err != nil {
+		return db.Page{}, s.err
+	}
+	pages := s.sortedPages(func(page db.Page) bool { return page.SiteID == arg.SiteID && page.Url == arg.Url })
+	if len(pages) == 0 {
+		return db.Page{}, sql.ErrNoRows
+	}
+	sort.SliceStable(pages, func(i, j int) bool {
+		if a, b := localeRank(pages[i].Locale, arg.Locales), localeRank(pages[j].Locale, arg.Locales); a != b {
+			return a < b
+		}
+		return pages[i].ID < pages[j].ID
+	})
+	return s.inLocale(pages[0], arg.Locales), nil
+}
+
+// AddPageTranslationTx mimics SQLStore: the group takes the source's id
+func (s *fakeStore) AddPageTranslationTx(ctx context.Context, args db.AddTranslationTxParams) (db.PageTranslationsTxResult, error) {
+	var result db.PageTranslationsTxResult
+	source, err := s.GetPages(ctx, db.GetPagesParams{SiteID: args.SiteID, ID: args.SourceID})
+	if err != nil {
+		return result, err
+	}
+	page, err := s.GetPages(ctx, db.GetPagesParams{SiteID: args.SiteID, ID: args.ID})
+	if err != nil {
+		return result, err
+	}
+	if page.ID == source.ID {
+		return result, apperr.Validation("content can not be its own translation", nil)
+	}
+
+	if !source.TranslationGroupID.Valid {
+		source.TranslationGroupID = sql.NullInt64{Int64: source.ID, Valid: true}
+		if !source.Locale.Valid {
+			source.Locale = sql.NullString{String: args.SourceLocale, Valid: true}
+		}
+		s.pages[source.ID] = source
+	}
+	for _, other := range s.pages {
+		if other.ID != page.ID && other.TranslationGroupID == source.TranslationGroupID && other.Locale.String == args.Locale {
+			return result, apperr.Conflict("locale is already translated", nil)
+		}
+	}
+	page.TranslationGroupID = source.TranslationGroupID
+	page.Locale = sql.NullString{String: args.Locale, Valid: true}
+	s.pages[page.ID] = page
+
+	result.Pages, err = s.ListPageTranslations(ctx, db.ListPageTranslationsParams{SiteID: args.SiteID, TranslationGroupID: source.TranslationGroupID})
+	return result, err
+}
+
+func (s *fakeStore) LeavePageTranslationGroup(ctx context.Context, arg db.LeavePageTranslationGroupParams) (db.Page, error) {
+	page, err := s.GetPages(ctx, db.GetPagesParams{SiteID: arg.SiteID, ID: arg.ID})
+	if err != nil {
+		return db.Page{}, err
+	}
+	page.TranslationGroupID = sql.NullInt64{}
+	s.pages[page.ID] = page
+	return page, nil
+}
+
+func (s *fakeStore) ListPostTranslations(_ context.Context, arg db.ListPostTranslationsParams) ([]db.Post, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var posts []db.Post
+	for _, post := range s.posts {
+		if post.SiteID == arg.SiteID && post.TranslationGroupID == arg.TranslationGroupID {
+			posts = append(posts, post)
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		if posts[i].Locale.String != posts[j].Locale.String {
+			return posts[i].Locale.String < posts[j].Locale.String
+		}
+		return posts[i].ID < posts[j].ID
+	})
+	return posts, nil
+}
+
+func (s *fakeStore) GetPostInLocale(ctx context.Context, arg db.GetPostInLocaleParams) (db.Post, error) {
+	source, err := s.GetPosts(ctx, db.GetPostsParams{SiteID: arg.SiteID, ID: arg.ID})
+	if err != nil {
+		return db.Post{}, err
+	}
+	best := source
+	for _, post := range s.posts {
+		if post.SiteID != source.SiteID || !source.TranslationGroupID.Valid || post.TranslationGroupID != source.TranslationGroupID {
+			continue
+		}
+		if localeRank(post.Locale, arg.Locales) < localeRank(best.Locale, arg.Locales) {
+			best = post
+		}
+	}
+	return best, nil
+}
+
+// AddPostTranslationTx mimics SQLStore like AddPageTranslationTx
+func (s *fakeStore) AddPostTranslationTx(ctx context.Context, args db.AddTranslationTxParams) (db.PostTranslationsTxResult, error) {
+	var result db.PostTranslationsTxResult
+	source, err := s.GetPosts(ctx, db.GetPostsParams{SiteID: args.SiteID, ID: args.SourceID})
+	if err != nil {
+		return result, err
+	}
+	post, err := s.GetPosts(ctx, db.GetPostsParams{SiteID: args.SiteID, ID: args.ID})
+	if err != nil {
+		return result, err
+	}
+	if post.ID == source.ID {
+		return result, apperr.Validation("content can not be its own translation", nil)
+	}
+
+	if !source.TranslationGroupID.Valid {
+		source.TranslationGroupID = sql.NullInt64{Int64: source.ID, Valid: true}
+		if !source.Locale.Valid {
+			source.Locale = sql.NullString{String: args.SourceLocale, Valid: true}
+		}
+		s.posts[source.ID] = source
+	}
+	for _, other := range s.posts {
+		if other.ID != post.ID && other.TranslationGroupID == source.TranslationGroupID && other.Locale.String == args.Locale {
+			return result, apperr.Conflict("locale is already translated", nil)
+		}
+	}
+	post.TranslationGroupID = source.TranslationGroupID
+	post.Locale = sql.NullString{String: args.Locale, Valid: true}
+	s.posts[post.ID] = post
+
+	result.Posts, err = s.ListPostTranslations(ctx, db.ListPostTranslationsParams{SiteID: args.SiteID, TranslationGroupID: source.TranslationGroupID})
+	return result, err
+}
+
+func (s *fakeStore) LeavePostTranslationGroup(ctx context.Context, arg db.LeavePostTranslationGroupParams) (db.Post, error) {
+	post, err := s.GetPosts(ctx, db.GetPostsParams{SiteID: arg.SiteID, ID: arg.ID})
+	if err != nil {
+		return db.Post{}, err
+	}
+	post.TranslationGroupID = sql.NullInt64{}
+	s.posts[post.ID] = post
+	return post, nil
+}
+
+func (s *fakeStore) CreateMediaTx(ctx context.Context, args db.CreateMediaParams) (db.CreateMediaTxResult, error) {
+	if _, err := s.siteAuthor(ctx, args.SiteID, args.UploaderID); err != nil {
+		return db.CreateMediaTxResult{}, err
+	}
+	for _, item := range s.media {
+		if item.SiteID == args.SiteID && item.Checksum == args.Checksum {
+			return db.CreateMediaTxResult{Media: item}, nil
+		}
+	}
+
+	item := db.Media{
+		ID:         s.id(),
+		SiteID:     args.SiteID,
+		UploaderID: args.UploaderID,
+		FileName:   args.FileName,
+		MimeType:   args.MimeType,
+		Size:       args.Size,
+		Checksum:   args.Checksum,
+		StorageKey: args.StorageKey,
+		Width:      args.Width,
+		Height:     args.Height,
+		AltText:    args.AltText,
+		CreatedAt:  fixedTime,
+	}
+	s.media[item.ID] = item
+	return db.CreateMediaTxResult{Media: item, Created: true}, nil
+}
+
+func (s *fakeStore) GetMedia(_ context.Context, arg db.GetMediaParams) (db.Media, error) {
+	if s.err != nil {
+		return db.Media{}, s.err
+	}
+	item, ok := s.media[arg.ID]
+	if !ok || item.SiteID != arg.SiteID {
+		return db.Media{}, sql.ErrNoRows
+	}
+	return item, nil
+}
+
+func (s *fakeStore) ListMedia(_ context.Context, arg db.ListMediaParams) ([]db.Media, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var items []db.Media
+	for _, item := range s.media {
+		if item.SiteID == arg.SiteID {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID > items[j].ID })
+	if int(arg.Offset) >= len(items) {
+		return nil, nil
+	}
+	items = items[arg.Offset:]
+	if len(items) > int(arg.Limit) {
+		items = items[:arg.Limit]
+	}
+	return items, nil
+}
+
+func (s *fakeStore) UpdateMediaAltText(ctx context.Context, arg db.UpdateMediaAltTextParams) (db.Media, error) {
+	item, err := s.GetMedia(ctx, db.GetMediaParams{SiteID: arg.SiteID, ID: arg.ID})
+	if err != nil {
+		return db.Media{}, err
+	}
+	item.AltText = arg.AltText
+	s.media[item.ID] = item
+	return item, nil
+}
+
+func (s *fakeStore) DeleteMediaTx(ctx context.Context, arg db.DeleteMediaParams) (db.Media, error) {
+	item, err := s.GetMedia(ctx, db.GetMediaParams{SiteID: arg.SiteID, ID: arg.ID})
+	if err != nil {
+		return db.Media{}, err
+	}
+	if s.mediaInUse[item.ID] {
+		return db.Media{}, apperr.Conflict("media is in use", nil)
+	}
+	delete(s.media, item.ID)
+	return item, nil
+}
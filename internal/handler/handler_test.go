@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/cache"
+	"github.com/reflection/frog_blossom_db/internal/components"
+	"github.com/reflection/frog_blossom_db/internal/content"
+	"github.com/reflection/frog_blossom_db/internal/health"
+	"github.com/reflection/frog_blossom_db/internal/imaging"
+	"github.com/reflection/frog_blossom_db/internal/middleware"
+	"github.com/reflection/frog_blossom_db/internal/storage"
+	"github.com/reflection/frog_blossom_db/internal/validation"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata with the actual responses")
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	validation.Register()
+	os.Exit(m.Run())
+}
+
+// apiCase is one request against the handlers and the response it must produce.
+// The response body is compared with testdata/<golden>.golden.json.
+type apiCase struct {
+	name   string
+	method string
+	path   string
+	// host defaults to testSite's domain
+	host string
+	// body is sent as JSON, unless it is a rawBody
+	body   any
+	header http.Header
+	setup  func(store *fakeStore)
+	// files are put into media storage by key before the request
+	files   map[string]string
+	checks  map[string]health.Check
+	status  int
+	headers map[string]string
+	golden  string
+}
+
+// rawBody is a request body sent as is, e.g. a multipart form
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+const (
+	testAdminToken = "test-admin-token"
+	// testMaxUploadBytes keeps the uploads of the tests small
+	testMaxUploadBytes = 4 << 10
+)
+
+// testSigner signs the variant URLs of the test router
+var testSigner = imaging.NewSigner("test-media-signing-key-0123456789")
+
+// newTestRouter mounts the handlers on the same routes as api.NewServer
+func newTestRouter(store db.Store, blobs storage.Storage, checker *health.Checker) *gin.Engine {
+	variants := imaging.NewVariants(blobs)
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(middleware.Errors())
+
+	router.GET("/healthz", HealthzHandler())
+	router.GET("/readyz", ReadyzHandler(checker))
+	router.GET("/debug/info", middleware.RequireAdminToken(testAdminToken), DebugInfoHandler(checker))
+
+	api := router.Group("api/v1")
+
+	sites := api.Group("/sites", middleware.RequireAdminToken(testAdminToken))
+	sites.POST("", CreateSiteHandler(store))
+	sites.GET("", ListSitesHandler(store))
+
+	subrouter := api.Group("", middleware.Site(store))
+	subrouter.POST("/users", middleware.IdentifyAdmin(testAdminToken), CreateUsersHandler(store))
+	subrouter.GET("/users/:id", GetUsersHandler(store))
+	subrouter.POST("/pages", CreatePagesHandler(store))
+	subrouter.GET("/pages/:id", GetPagesHandler(store))
+	subrouter.POST("/posts", CreatePostsHandler(store))
+	subrouter.GET("/posts/:id", GetPostsHandler(store, content.NewPipeline(cache.NewLRU(10), time.Minute)))
+	subrouter.GET("/posts/:id/translations", ListPostTranslationsHandler(store))
+	subrouter.PUT("/posts/:id/translations/:locale", PutPostTranslationHandler(store))
+	subrouter.DELETE("/posts/:id/translations", DeletePostTranslationHandler(store))
+	subrouter.PUT("/pages/:id", UpdatePagesHandler(store))
+	subrouter.GET("/pages/:id/tree", GetPageTreeHandler(store))
+	subrouter.PUT("/pages/:id/parent", MovePageHandler(store))
+	subrouter.GET("/pages/:id/translations", ListPageTranslationsHandler(store))
+	subrouter.PUT("/pages/:id/translations/:locale", PutPageTranslationHandler(store))
+	subrouter.DELETE("/pages/:id/translations", DeletePageTranslationHandler(store))
+	subrouter.GET("/navigation", GetNavigationHandler(store))
+	subrouter.PUT("/navigation/order", ReorderPagesHandler(store))
+	subrouter.GET("/component-types", ListComponentTypesHandler(components.Default))
+	subrouter.GET("/pages/:id/components", ListPageComponentsHandler(store))
+	subrouter.POST("/pages/:id/components", AddPageComponentHandler(store, components.Default))
+	subrouter.PUT("/pages/:id/components/:component_id/position", MovePageComponentHandler(store))
+	subrouter.DELETE("/pages/:id/components/:component_id", RemovePageComponentHandler(store))
+	subrouter.GET("/delivery/*path", DeliverPageHandler(store))
+	subrouter.POST("/media", UploadMediaHandler(store, blobs, testMaxUploadBytes))
+	subrouter.GET("/media", ListMediaHandler(store))
+	subrouter.GET("/media/:id", GetMediaHandler(store, testSigner))
+	subrouter.PUT("/media/:id", UpdateMediaHandler(store))
+	subrouter.DELETE("/media/:id", DeleteMediaHandler(store, blobs, variants))
+	subrouter.GET("/media/:id/file", ServeMediaFileHandler(store, blobs))
+	subrouter.GET("/media/:id/variants/:variant", ServeMediaVariantHandler(store, variants, testSigner))
+
+	site := subrouter.Group("/site", middleware.RequireAdminToken(testAdminToken))
+	site.GET("/settings", GetSiteSettingsHandler(store))
+	site.PUT("/settings/:key", PutSiteSettingHandler(store))
+	site.DELETE("/settings/:key", DeleteSiteSettingHandler(store))
+	site.GET("/members", ListSiteMembersHandler(store))
+	site.PUT("/members/:user_id", PutSiteMemberHandler(store))
+	site.DELETE("/members/:user_id", DeleteSiteMemberHandler(store))
+
+	return router
+}
+
+func runCases(t *testing.T, cases []apiCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newFakeStore()
+			if tc.setup != nil {
+				tc.setup(store)
+			}
+
+			blobs := storage.NewLocal(t.TempDir())
+			for key, content := range tc.files {
+				require.NoError(t, blobs.Put(context.Background(), key, strings.NewReader(content)))
+			}
+
+			var body io.Reader
+			contentType := "application/json"
+			if raw, ok := tc.body.(rawBody); ok {
+				body = bytes.NewReader(raw.data)
+				contentType = raw.contentType
+			} else if tc.body != nil {
+				raw, err := json.Marshal(tc.body)
+				require.NoError(t, err)
+				body = bytes.NewReader(raw)
+			}
+
+			checker := health.NewChecker(nil)
+			for name, check := range tc.checks {
+				checker.Add(name, check)
+			}
+
+			req := httptest.NewRequest(tc.method, tc.path, body)
+			req.Host = testSite.Domain
+			if tc.host != "" {
+				req.Host = tc.host
+			}
+			if body != nil {
+				req.Header.Set("Content-Type", contentType)
+			}
+			for k, v := range tc.header {
+				req.Header[k] = v
+			}
+			recorder := httptest.NewRecorder()
+
+			newTestRouter(store, blobs, checker).ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.status, recorder.Code)
+
+			headers := map[string]string{"Content-Type": "application/json; charset=utf-8"}
+			if tc.status >= http.StatusBadRequest && tc.status != http.StatusServiceUnavailable {
+				headers["Content-Type"] = middleware.ProblemContentType
+			}
+			for k, v := range tc.headers {
+				headers[k] = v
+			}
+			for k, v := range headers {
+				require.Equal(t, v, recorder.Header().Get(k), "header %s", k)
+			}
+
+			if tc.status >= http.StatusBadRequest && tc.status != http.StatusServiceUnavailable {
+				requireProblem(t, tc.status, recorder.Body.Bytes())
+			}
+			requireGolden(t, tc.golden, recorder.Body.Bytes())
+		})
+	}
+}
+
+// requireProblem checks an error body against the RFC 7807 schema the
+// errors middleware renders
+func requireProblem(t *testing.T, status int, body []byte) {
+	t.Helper()
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(body, &payload), "error body must be a single JSON object")
+
+	for _, key := range []string{"type", "title", "detail", "code", "instance"} {
+		value, ok := payload[key].(string)
+		require.True(t, ok, "%s must be a string", key)
+		require.NotEmpty(t, value, "%s must not be empty", key)
+	}
+	require.Equal(t, float64(status), payload["status"])
+
+	var problem middleware.Problem
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	require.NoError(t, decoder.Decode(&problem), "error body has fields outside the problem schema")
+}
+
+func requireGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden.json")
+
+	if *update {
+		var pretty bytes.Buffer
+		require.NoError(t, json.Indent(&pretty, actual, "", "  "), "response is not valid JSON: %s", actual)
+		pretty.WriteByte('\n')
+		require.NoError(t, os.WriteFile(path, pretty.Bytes(), 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden file, run go test with -update")
+	require.JSONEq(t, string(expected), string(actual))
+}
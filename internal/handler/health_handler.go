@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reflection/frog_blossom_db/internal/health"
+)
+
+// HealthzHandler reports liveness: the process is up and serving HTTP
+func HealthzHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+	}
+}
+
+// ReadyzHandler reports whether the instance can take traffic
+func ReadyzHandler(checker *health.Checker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		report := checker.Ready(ctx)
+
+		status := http.StatusOK
+		if report.Status != health.StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		ctx.JSON(status, report)
+	}
+}
+
+// DebugInfoHandler shows build, migration, pool and uptime diagnostics
+func DebugInfoHandler(checker *health.Checker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, checker.Info(ctx))
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/reflection/frog_blossom_db/internal/health"
+)
+
+func TestHealthzHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/healthz",
+			checks: map[string]health.Check{
+				"database": failingCheck,
+			},
+			status: http.StatusOK,
+			golden: "healthz_ok",
+		},
+	})
+}
+
+func TestReadyzHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "Ready",
+			method: http.MethodGet,
+			path:   "/readyz",
+			checks: map[string]health.Check{
+				"database":   passingCheck,
+				"migrations": passingCheck,
+			},
+			status: http.StatusOK,
+			golden: "readyz_ready",
+		},
+		{
+			name:   "DatabaseDown",
+			method: http.MethodGet,
+			path:   "/readyz",
+			checks: map[string]health.Check{
+				"database":   failingCheck,
+				"migrations": passingCheck,
+			},
+			status: http.StatusServiceUnavailable,
+			golden: "readyz_database_down",
+		},
+	})
+}
+
+func TestDebugInfoHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "NoToken",
+			method: http.MethodGet,
+			path:   "/debug/info",
+			status: http.StatusForbidden,
+			golden: "debug_info_forbidden",
+		},
+		{
+			name:   "WrongToken",
+			method: http.MethodGet,
+			path:   "/debug/info",
+			header: http.Header{"Authorization": {"Bearer not-the-token"}},
+			status: http.StatusForbidden,
+			golden: "debug_info_forbidden",
+		},
+	})
+}
+
+func passingCheck(ctx context.Context) error {
+	return nil
+}
+
+func failingCheck(ctx context.Context) error {
+	return errors.New("dial tcp 127.0.0.1:5432: connect: connection refused")
+}
@@ -0,0 +1,415 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/imaging"
+	"github.com/reflection/frog_blossom_db/internal/media"
+	"github.com/reflection/frog_blossom_db/internal/storage"
+	"github.com/reflection/frog_blossom_db/internal/validation"
+)
+
+type uploadMediaRequest struct {
+	File       *multipart.FileHeader `form:"file" binding:"required"`
+	UploaderID int64                 `form:"uploader_id" binding:"required,min=1"`
+	AltText    string                `form:"alt_text" binding:"max=1000"`
+}
+
+// fileName is the base name the client gave the file, cut to fit the column
+func fileName(header *multipart.FileHeader) string {
+	name := path.Base(strings.ReplaceAll(header.Filename, "\\", "/"))
+	if name == "." || name == "/" {
+		name = "upload"
+	}
+	for utf8.RuneCountInString(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// putMissing writes r under key unless a file is there already. Keys name
+// their content and Put never leaves a partial file, so one that is there
+// is complete and concurrent writes of the same key agree.
+func putMissing(ctx context.Context, blobs storage.Storage, key string, r io.Reader) error {
+	existing, err := blobs.Open(ctx, key)
+	if err == nil {
+		return existing.Close()
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+	return blobs.Put(ctx, key, r)
+}
+
+// UploadMediaHandler takes a multipart upload of one file of at most
+// maxBytes. A file the site already has gives the existing media item.
+func UploadMediaHandler(store db.Store, blobs storage.Storage, maxBytes int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytes)
+		var req uploadMediaRequest
+		if err := ctx.ShouldBindWith(&req, binding.FormMultipart); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				ctx.Error(apperr.TooLarge(fmt.Sprintf("uploads are limited to %d bytes", maxBytes), err))
+				return
+			}
+
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		file, err := req.File.Open()
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		defer file.Close()
+
+		info, err := media.Inspect(file)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if !media.Allowed(info.MimeType) {
+			ctx.Error(apperr.InvalidFields([]apperr.FieldError{{
+				Field:   "file",
+				Rule:    "mime_type",
+				Message: fmt.Sprintf("files of type %s can not be uploaded", info.MimeType),
+			}}, nil))
+			return
+		}
+
+		args := db.CreateMediaParams{
+			SiteID:     site.ID,
+			UploaderID: req.UploaderID,
+			FileName:   fileName(req.File),
+			MimeType:   info.MimeType,
+			Size:       info.Size,
+			Checksum:   info.Checksum,
+			StorageKey: media.Key(site.ID, info.Checksum),
+			AltText:    req.AltText,
+		}
+		if info.Width > 0 {
+			args.Width = sql.NullInt32{Int32: int32(info.Width), Valid: true}
+			args.Height = sql.NullInt32{Int32: int32(info.Height), Valid: true}
+		}
+
+		// The file comes before the row, so neither this upload nor a
+		// concurrent one of the same content gets the item before its file
+		// is complete. A rejected upload leaves its file for the next
+		// upload of the same content.
+		if err := putMissing(ctx, blobs, args.StorageKey, file); err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		result, err := store.CreateMediaTx(ctx, args)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.InvalidFields([]apperr.FieldError{{
+					Field:   "uploader_id",
+					Rule:    "exists",
+					Message: "must be a member of the site",
+				}}, err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	}
+}
+
+func ListMediaHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		req := listRequest{Limit: 50}
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		items, err := store.ListMedia(ctx, db.ListMediaParams{SiteID: site.ID, Limit: req.Limit, Offset: req.Offset})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if items == nil {
+			items = []db.Media{}
+		}
+		ctx.JSON(http.StatusOK, items)
+	}
+}
+
+type getMediaRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getMedia binds the URI and loads the media item it names
+func getMedia(ctx *gin.Context, store db.Store) (db.Media, bool) {
+	site, ok := requireSite(ctx)
+	if !ok {
+		return db.Media{}, false
+	}
+
+	var req getMediaRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.Error(validation.FromBinding(err))
+		return db.Media{}, false
+	}
+
+	item, err := store.GetMedia(ctx, db.GetMediaParams{SiteID: site.ID, ID: req.ID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.Error(apperr.NotFound("media not found", err))
+			return db.Media{}, false
+		}
+
+		ctx.Error(err)
+		return db.Media{}, false
+	}
+	return item, true
+}
+
+// mediaResponse adds the URLs to render a media item with. Images of known
+// size have a srcset of signed variants and an Open Graph variant.
+type mediaResponse struct {
+	db.Media
+	URL          string `json:"url"`
+	Srcset       string `json:"srcset,omitempty"`
+	OpenGraphURL string `json:"og_image_url,omitempty"`
+}
+
+func GetMediaHandler(store db.Store, signer *imaging.Signer) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		item, ok := getMedia(ctx, store)
+		if !ok {
+			return
+		}
+
+		res := mediaResponse{Media: item, URL: fmt.Sprintf("/api/v1/media/%d/file", item.ID)}
+		if strings.HasPrefix(item.MimeType, "image/") && item.Width.Valid {
+			res.Srcset = signer.Srcset(item, nil, "jpeg")
+			res.OpenGraphURL = signer.URL(item, imaging.OpenGraph)
+		}
+		ctx.JSON(http.StatusOK, res)
+	}
+}
+
+type updateMediaRequest struct {
+	AltText *string `json:"alt_text" binding:"required,max=1000"`
+}
+
+func UpdateMediaHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri getMediaRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		var req updateMediaRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		item, err := store.UpdateMediaAltText(ctx, db.UpdateMediaAltTextParams{
+			SiteID:  site.ID,
+			ID:      uri.ID,
+			AltText: *req.AltText,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("media not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, item)
+	}
+}
+
+// DeleteMediaHandler deletes a media item, its file and its variants.
+// Media a post or meta refers to is a conflict.
+func DeleteMediaHandler(store db.Store, blobs storage.Storage, variants *imaging.Variants) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri getMediaRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		item, err := store.DeleteMediaTx(ctx, db.DeleteMediaParams{SiteID: site.ID, ID: uri.ID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("media not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+
+		if err := blobs.Delete(ctx, item.StorageKey); err != nil {
+			ctx.Error(err)
+			return
+		}
+		if err := variants.Delete(ctx, item); err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, item)
+	}
+}
+
+// inlineTypes are shown by the browser; anything else is downloaded
+var inlineTypes = []string{"image/", "video/", "audio/"}
+
+// ServeMediaFileHandler streams the file of a media item. Range and
+// conditional requests are answered by http.ServeContent, with the
+// checksum as the ETag.
+func ServeMediaFileHandler(store db.Store, blobs storage.Storage) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		item, ok := getMedia(ctx, store)
+		if !ok {
+			return
+		}
+
+		file, err := blobs.Open(ctx, item.StorageKey)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				ctx.Error(apperr.NotFound("media file not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		defer file.Close()
+
+		disposition := "attachment"
+		for _, prefix := range inlineTypes {
+			if strings.HasPrefix(item.MimeType, prefix) {
+				disposition = "inline"
+			}
+		}
+
+		header := ctx.Writer.Header()
+		header.Set("Content-Type", item.MimeType)
+		header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": item.FileName}))
+		header.Set("ETag", `"`+item.Checksum+`"`)
+		header.Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(ctx.Writer, ctx.Request, item.FileName, item.CreatedAt, file)
+	}
+}
+
+type mediaVariantRequest struct {
+	ID      int64  `uri:"id" binding:"required,min=1"`
+	Variant string `uri:"variant" binding:"required"`
+}
+
+// ServeMediaVariantHandler streams a variant of an image, see
+// imaging.ParseVariant, rendering it on first request. The URL must carry
+// the signature imaging.Signer gave it.
+func ServeMediaVariantHandler(store db.Store, variants *imaging.Variants, signer *imaging.Signer) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var req mediaVariantRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		variant, err := imaging.ParseVariant(req.Variant)
+		if err != nil {
+			ctx.Error(apperr.InvalidFields([]apperr.FieldError{{
+				Field:   "variant",
+				Rule:    "variant",
+				Message: err.Error(),
+			}}, err))
+			return
+		}
+		if !signer.Verify(site.ID, req.ID, variant, ctx.Query("s")) {
+			ctx.Error(apperr.Forbidden("variant URL signature is invalid", nil))
+			return
+		}
+
+		item, ok := getMedia(ctx, store)
+		if !ok {
+			return
+		}
+
+		file, err := variants.Open(ctx, item, variant)
+		if err != nil {
+			if errors.Is(err, imaging.ErrNotImage) {
+				ctx.Error(apperr.NotFound("media has no image variants", err))
+				return
+			}
+			if errors.Is(err, storage.ErrNotFound) {
+				ctx.Error(apperr.NotFound("media file not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		defer file.Close()
+
+		header := ctx.Writer.Header()
+		header.Set("Content-Type", variant.ContentType())
+		// the content of a media item, and so of its variants, never changes
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+		header.Set("ETag", `"`+item.Checksum+"-"+variant.String()+`"`)
+		header.Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(ctx.Writer, ctx.Request, variant.String(), item.CreatedAt, file)
+	}
+}
@@ -0,0 +1,528 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"image"
+	_ "image/jpeg"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/health"
+	"github.com/reflection/frog_blossom_db/internal/imaging"
+	"github.com/reflection/frog_blossom_db/internal/media"
+	"github.com/reflection/frog_blossom_db/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// testPNG is a 2x1 grayscale PNG
+const testPNG = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x02\x00\x00\x00\x01\b\x00\x00\x00\x00\xd1I V\x00\x00\x00\x10IDATx\x9c\x00\x03\x00\xfc\xff\x02\x00\x00\x03\x00\x00\t\x00\x03\b\xba\xbeH\x00\x00\x00\x00IEND\xaeB`\x82"
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// testPNGKey is where testSite keeps testPNG
+var testPNGKey = media.Key(testSite.ID, checksum(testPNG))
+
+// uploadBody is a multipart form with the fields and, unless fileName is
+// empty, a file
+func uploadBody(t *testing.T, fields map[string]string, fileName, content string) rawBody {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	if fileName != "" {
+		part, err := writer.CreateFormFile("file", fileName)
+		require.NoError(t, err)
+		_, err = io.WriteString(part, content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return rawBody{contentType: writer.FormDataContentType(), data: buf.Bytes()}
+}
+
+// seedMedia stores testPNG as media 30 of testSite, and a file of
+// otherSite as media 31
+func seedMedia(store *fakeStore) {
+	seedAuthor(store)
+	store.media[30] = db.Media{
+		ID:         30,
+		SiteID:     testSite.ID,
+		UploaderID: 7,
+		FileName:   "pond.png",
+		MimeType:   "image/png",
+		Size:       int64(len(testPNG)),
+		Checksum:   checksum(testPNG),
+		StorageKey: testPNGKey,
+		Width:      sql.NullInt32{Int32: 2, Valid: true},
+		Height:     sql.NullInt32{Int32: 1, Valid: true},
+		AltText:    "A pond",
+		CreatedAt:  fixedTime,
+	}
+	store.media[31] = db.Media{
+		ID:         31,
+		SiteID:     otherSite.ID,
+		UploaderID: 7,
+		FileName:   "report.pdf",
+		MimeType:   "application/pdf",
+		Size:       5,
+		Checksum:   checksum("%PDF-"),
+		StorageKey: media.Key(otherSite.ID, checksum("%PDF-")),
+		CreatedAt:  fixedTime,
+	}
+	store.nextID = 32
+}
+
+func TestUploadMediaHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodPost,
+			path:   "/api/v1/media",
+			body:   uploadBody(t, map[string]string{"uploader_id": "7", "alt_text": "A pond"}, "../photos/pond.png", testPNG),
+			setup:  seedAuthor,
+			status: http.StatusOK,
+			golden: "upload_media_ok",
+		},
+		{
+			name:   "Duplicate",
+			method: http.MethodPost,
+			path:   "/api/v1/media",
+			body:   uploadBody(t, map[string]string{"uploader_id": "7"}, "copy.png", testPNG),
+			setup:  seedMedia,
+			status: http.StatusOK,
+			golden: "upload_media_duplicate",
+		},
+		{
+			name:   "NotMember",
+			method: http.MethodPost,
+			path:   "/api/v1/media",
+			body:   uploadBody(t, map[string]string{"uploader_id": "99"}, "pond.png", testPNG),
+			setup:  seedAuthor,
+			status: http.StatusBadRequest,
+			golden: "upload_media_not_member",
+		},
+		{
+			name:   "MissingFile",
+			method: http.MethodPost,
+			path:   "/api/v1/media",
+			body:   uploadBody(t, map[string]string{"uploader_id": "7"}, "", ""),
+			setup:  seedAuthor,
+			status: http.StatusBadRequest,
+			golden: "upload_media_missing_file",
+		},
+		{
+			name:   "DisallowedType",
+			method: http.MethodPost,
+			path:   "/api/v1/media",
+			body:   uploadBody(t, map[string]string{"uploader_id": "7"}, "pond.png", "<html><script>alert(1)</script></html>"),
+			setup:  seedAuthor,
+			status: http.StatusBadRequest,
+			golden: "upload_media_disallowed_type",
+		},
+		{
+			name:   "TooLarge",
+			method: http.MethodPost,
+			path:   "/api/v1/media",
+			body:   uploadBody(t, map[string]string{"uploader_id": "7"}, "big.png", testPNG+strings.Repeat("x", testMaxUploadBytes)),
+			setup:  seedAuthor,
+			status: http.StatusRequestEntityTooLarge,
+			golden: "upload_media_too_large",
+		},
+	})
+}
+
+func TestUploadMediaStoresFile(t *testing.T) {
+	store := newFakeStore()
+	seedAuthor(store)
+	blobs := storage.NewLocal(t.TempDir())
+	router := newTestRouter(store, blobs, health.NewChecker(nil))
+
+	for range 2 {
+		body := uploadBody(t, map[string]string{"uploader_id": "7"}, "pond.png", testPNG)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/media", bytes.NewReader(body.data))
+		req.Host = testSite.Domain
+		req.Header.Set("Content-Type", body.contentType)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+	}
+	require.Len(t, store.media, 1)
+
+	file, err := blobs.Open(context.Background(), testPNGKey)
+	require.NoError(t, err)
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.Equal(t, testPNG, string(content))
+}
+
+// gatedStorage holds Put until release is closed, after telling putting
+type gatedStorage struct {
+	storage.Storage
+	putting chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (g *gatedStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	g.putting <- struct{}{}
+	<-g.release
+	if g.err != nil {
+		return g.err
+	}
+	return g.Storage.Put(ctx, key, r)
+}
+
+func TestUploadMediaWritesFileBeforeRow(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		status int
+		rows   int
+	}{
+		{name: "Written", status: http.StatusOK, rows: 1},
+		{name: "WriteFailed", err: errors.New("disk full"), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newFakeStore()
+			seedAuthor(store)
+			blobs := &gatedStorage{
+				Storage: storage.NewLocal(t.TempDir()),
+				putting: make(chan struct{}),
+				release: make(chan struct{}),
+				err:     tc.err,
+			}
+			router := newTestRouter(store, blobs, health.NewChecker(nil))
+
+			body := uploadBody(t, map[string]string{"uploader_id": "7"}, "pond.png", testPNG)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/media", bytes.NewReader(body.data))
+			req.Host = testSite.Domain
+			req.Header.Set("Content-Type", body.contentType)
+			recorder := httptest.NewRecorder()
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				router.ServeHTTP(recorder, req)
+			}()
+
+			// a concurrent upload of the same file finds no row to return
+			// while the file is being written
+			<-blobs.putting
+			require.Empty(t, store.media)
+			close(blobs.release)
+			<-done
+
+			require.Equal(t, tc.status, recorder.Code)
+			require.Len(t, store.media, tc.rows)
+		})
+	}
+}
+
+func TestListMediaHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/media",
+			setup:  seedMedia,
+			status: http.StatusOK,
+			golden: "list_media_ok",
+		},
+		{
+			name:   "Empty",
+			method: http.MethodGet,
+			path:   "/api/v1/media?offset=10",
+			setup:  seedMedia,
+			status: http.StatusOK,
+			golden: "list_media_empty",
+		},
+	})
+}
+
+func TestGetMediaHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/media/30",
+			setup:  seedMedia,
+			status: http.StatusOK,
+			golden: "get_media_ok",
+		},
+		{
+			name:   "OtherSite",
+			method: http.MethodGet,
+			path:   "/api/v1/media/31",
+			setup:  seedMedia,
+			status: http.StatusNotFound,
+			golden: "get_media_not_found",
+		},
+	})
+}
+
+func TestUpdateMediaHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodPut,
+			path:   "/api/v1/media/30",
+			body:   map[string]any{"alt_text": "Lily pads on a pond"},
+			setup:  seedMedia,
+			status: http.StatusOK,
+			golden: "update_media_ok",
+		},
+		{
+			name:   "MissingAltText",
+			method: http.MethodPut,
+			path:   "/api/v1/media/30",
+			body:   map[string]any{},
+			setup:  seedMedia,
+			status: http.StatusBadRequest,
+			golden: "update_media_missing_alt_text",
+		},
+		{
+			name:   "OtherSite",
+			method: http.MethodPut,
+			path:   "/api/v1/media/31",
+			body:   map[string]any{"alt_text": "defaced"},
+			setup:  seedMedia,
+			status: http.StatusNotFound,
+			golden: "get_media_not_found",
+		},
+	})
+}
+
+func TestDeleteMediaHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodDelete,
+			path:   "/api/v1/media/30",
+			setup:  seedMedia,
+			files:  map[string]string{testPNGKey: testPNG},
+			status: http.StatusOK,
+			golden: "delete_media_ok",
+		},
+		{
+			name:   "InUse",
+			method: http.MethodDelete,
+			path:   "/api/v1/media/30",
+			setup: func(store *fakeStore) {
+				seedMedia(store)
+				store.mediaInUse[30] = true
+			},
+			status: http.StatusConflict,
+			golden: "delete_media_in_use",
+		},
+		{
+			name:   "OtherSite",
+			method: http.MethodDelete,
+			path:   "/api/v1/media/31",
+			setup:  seedMedia,
+			status: http.StatusNotFound,
+			golden: "get_media_not_found",
+		},
+	})
+}
+
+func TestServeMediaVariantHandler(t *testing.T) {
+	pngURL := testSigner.URL(db.Media{ID: 30, SiteID: testSite.ID}, imaging.Variant{Width: 1, Format: "png"})
+
+	testCases := []struct {
+		name   string
+		path   string
+		setup  func(store *fakeStore)
+		status int
+		// size is the size of the image served
+		size image.Point
+	}{
+		{
+			name:   "Resized",
+			path:   pngURL,
+			status: http.StatusOK,
+			size:   image.Pt(1, 1),
+		},
+		{
+			name:   "OpenGraph",
+			path:   testSigner.URL(db.Media{ID: 30, SiteID: testSite.ID}, imaging.OpenGraph),
+			status: http.StatusOK,
+			size:   image.Pt(1200, 630),
+		},
+		{
+			name:   "UnsignedVariant",
+			path:   strings.Replace(pngURL, "1w.png", "2w.png", 1),
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "SignedForAnotherSite",
+			path:   testSigner.URL(db.Media{ID: 31, SiteID: otherSite.ID}, imaging.OpenGraph),
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "InvalidVariant",
+			path:   "/api/v1/media/30/variants/1w.gif?s=x",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "NotAnImage",
+			path: testSigner.URL(db.Media{ID: 32, SiteID: testSite.ID}, imaging.OpenGraph),
+			setup: func(store *fakeStore) {
+				store.media[32] = db.Media{ID: 32, SiteID: testSite.ID, MimeType: "application/pdf", StorageKey: testPNGKey}
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			store := newFakeStore()
+			seedMedia(store)
+			if tc.setup != nil {
+				tc.setup(store)
+			}
+			blobs := storage.NewLocal(t.TempDir())
+			require.NoError(t, blobs.Put(context.Background(), testPNGKey, strings.NewReader(testPNG)))
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Host = testSite.Domain
+			recorder := httptest.NewRecorder()
+
+			// Act
+			newTestRouter(store, blobs, health.NewChecker(nil)).ServeHTTP(recorder, req)
+
+			// Assert
+			require.Equal(t, tc.status, recorder.Code, recorder.Body.String())
+			if tc.status != http.StatusOK {
+				requireProblem(t, tc.status, recorder.Body.Bytes())
+				return
+			}
+			require.Equal(t, "public, max-age=31536000, immutable", recorder.Header().Get("Cache-Control"))
+			config, format, err := image.DecodeConfig(recorder.Body)
+			require.NoError(t, err)
+			require.Equal(t, strings.TrimPrefix(recorder.Header().Get("Content-Type"), "image/"), format)
+			require.Equal(t, tc.size, image.Pt(config.Width, config.Height))
+		})
+	}
+}
+
+func TestServeMediaVariantIsKept(t *testing.T) {
+	store := newFakeStore()
+	seedMedia(store)
+	blobs := storage.NewLocal(t.TempDir())
+	require.NoError(t, blobs.Put(context.Background(), testPNGKey, strings.NewReader(testPNG)))
+	router := newTestRouter(store, blobs, health.NewChecker(nil))
+
+	req := httptest.NewRequest(http.MethodGet, testSigner.URL(store.media[30], imaging.OpenGraph), nil)
+	req.Host = testSite.Domain
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// with the original gone, the variant is still served from storage
+	require.NoError(t, blobs.Delete(context.Background(), testPNGKey))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// deleting the media deletes its variants
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/media/30", nil)
+	req.Host = testSite.Domain
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	_, err := blobs.Open(context.Background(), imaging.Key(db.Media{SiteID: testSite.ID, Checksum: checksum(testPNG)}, imaging.OpenGraph))
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestServeMediaFileHandler(t *testing.T) {
+	testCases := []struct {
+		name    string
+		header  http.Header
+		files   bool
+		status  int
+		body    string
+		headers map[string]string
+	}{
+		{
+			name:   "Full",
+			files:  true,
+			status: http.StatusOK,
+			body:   testPNG,
+			headers: map[string]string{
+				"Content-Type":           "image/png",
+				"Content-Disposition":    `inline; filename=pond.png`,
+				"Accept-Ranges":          "bytes",
+				"ETag":                   `"` + checksum(testPNG) + `"`,
+				"X-Content-Type-Options": "nosniff",
+			},
+		},
+		{
+			name:    "Range",
+			header:  http.Header{"Range": {"bytes=1-3"}},
+			files:   true,
+			status:  http.StatusPartialContent,
+			body:    "PNG",
+			headers: map[string]string{"Content-Range": "bytes 1-3/73", "Content-Length": "3"},
+		},
+		{
+			name:   "NotModified",
+			header: http.Header{"If-None-Match": {`"` + checksum(testPNG) + `"`}},
+			files:  true,
+			status: http.StatusNotModified,
+		},
+		{
+			name:   "RangeNotSatisfiable",
+			header: http.Header{"Range": {"bytes=100-"}},
+			files:  true,
+			status: http.StatusRequestedRangeNotSatisfiable,
+			body:   "invalid range: failed to overlap\n",
+		},
+		{
+			name:   "MissingFile",
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			store := newFakeStore()
+			seedMedia(store)
+			blobs := storage.NewLocal(t.TempDir())
+			if tc.files {
+				require.NoError(t, blobs.Put(context.Background(), testPNGKey, strings.NewReader(testPNG)))
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/media/30/file", nil)
+			req.Host = testSite.Domain
+			for k, v := range tc.header {
+				req.Header[k] = v
+			}
+			recorder := httptest.NewRecorder()
+
+			// Act
+			newTestRouter(store, blobs, health.NewChecker(nil)).ServeHTTP(recorder, req)
+
+			// Assert
+			require.Equal(t, tc.status, recorder.Code)
+			if tc.body != "" {
+				require.Equal(t, tc.body, recorder.Body.String())
+			}
+			for k, v := range tc.headers {
+				require.Equal(t, v, recorder.Header().Get(k), "header %s", k)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/validation"
+)
+
+// navNode is a page in the navigation tree with its children in menu order
+type navNode struct {
+	ID             int64     `json:"id"`
+	Title          string    `json:"title"`
+	Url            string    `json:"url"`
+	MenuOrder      int64     `json:"menu_order"`
+	PageIdentifier string    `json:"page_identifier"`
+	Children       []navNode `json:"children"`
+}
+
+// navigationTree nests pages under their parents starting from the pages
+// isRoot picks. Pages must be sorted by menu_order within their parent.
+func navigationTree(pages []db.Page, isRoot func(db.Page) bool) []navNode {
+	var roots []db.Page
+	children := make(map[int64][]db.Page)
+	for _, page := range pages {
+		switch {
+		case isRoot(page):
+			roots = append(roots, page)
+		case page.ParentID.Valid:
+			children[page.ParentID.Int64] = append(children[page.ParentID.Int64], page)
+		}
+	}
+
+	var build func(pages []db.Page) []navNode
+	build = func(pages []db.Page) []navNode {
+		nodes := make([]navNode, 0, len(pages))
+		for _, page := range pages {
+			nodes = append(nodes, navNode{
+				ID:             page.ID,
+				Title:          page.Title,
+				Url:            page.Url,
+				MenuOrder:      page.MenuOrder,
+				PageIdentifier: page.PageIdentifier,
+				Children:       build(children[page.ID]),
+			})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+func GetNavigationHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		pages, err := store.ListSitePages(ctx, site.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{
+			"domain": site.Domain,
+			"pages":  navigationTree(pages, func(page db.Page) bool { return !page.ParentID.Valid }),
+		})
+	}
+}
+
+func GetPageTreeHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var req getPagesRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		pages, err := store.GetPageSubtree(ctx, db.GetPageSubtreeParams{SiteID: site.ID, ID: req.ID})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if len(pages) == 0 {
+			ctx.Error(apperr.NotFound("page not found", nil))
+			return
+		}
+		ctx.JSON(http.StatusOK, navigationTree(pages, func(page db.Page) bool { return page.ID == req.ID })[0])
+	}
+}
+
+type movePageRequest struct {
+	// ParentID is the new parent; null moves the page to the top level
+	ParentID  *int64 `json:"parent_id" binding:"omitempty,min=1"`
+	MenuOrder int64  `json:"menu_order" binding:"min=0"`
+}
+
+func MovePageHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri getPagesRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		var req movePageRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		result, err := store.MovePageTx(ctx, db.MovePageTxParams{
+			SiteID:    site.ID,
+			PageID:    uri.ID,
+			ParentID:  req.ParentID,
+			MenuOrder: req.MenuOrder,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("page not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, result.Page)
+	}
+}
+
+type reorderPagesRequest struct {
+	// ParentID selects whose children are reordered; null reorders the top level
+	ParentID *int64  `json:"parent_id" binding:"omitempty,min=1"`
+	PageIDs  []int64 `json:"page_ids" binding:"required,min=1,dive,min=1"`
+}
+
+func ReorderPagesHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var req reorderPagesRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		result, err := store.ReorderPagesTx(ctx, db.ReorderPagesTxParams{
+			SiteID:   site.ID,
+			ParentID: req.ParentID,
+			PageIDs:  req.PageIDs,
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+)
+
+// seedPageTree adds Home and About with its children Team and History on
+// testSite, and a page on otherSite
+func seedPageTree(store *fakeStore) {
+	seedAuthor(store)
+	parent := func(id int64) sql.NullInt64 { return sql.NullInt64{Int64: id, Valid: true} }
+	for _, page := range []db.Page{
+		{ID: 10, SiteID: testSite.ID, Title: "About", Url: "/about", MenuOrder: 1, PageIdentifier: "about"},
+		{ID: 11, SiteID: testSite.ID, Title: "Home", Url: "/", MenuOrder: 0, PageIdentifier: "home"},
+		{ID: 12, SiteID: testSite.ID, Title: "History", Url: "/about/history", MenuOrder: 1, PageIdentifier: "history", ParentID: parent(10)},
+		{ID: 13, SiteID: testSite.ID, Title: "Team", Url: "/about/team", MenuOrder: 0, PageIdentifier: "team", ParentID: parent(10)},
+		{ID: 14, SiteID: otherSite.ID, Title: "Elsewhere", Url: "/", PageIdentifier: "home"},
+	} {
+		page.AuthorID = 7
+		page.PageAuthor = "frog"
+		store.addPage(page, nil)
+	}
+}
+
+func TestGetNavigationHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/navigation",
+			setup:  seedPageTree,
+			status: http.StatusOK,
+			golden: "get_navigation_ok",
+		},
+		{
+			name:   "OtherSite",
+			method: http.MethodGet,
+			path:   "/api/v1/navigation",
+			host:   "Other.Example:8080",
+			setup:  seedPageTree,
+			status: http.StatusOK,
+			golden: "get_navigation_other_site",
+		},
+		{
+			name:   "UnknownHost",
+			method: http.MethodGet,
+			path:   "/api/v1/navigation",
+			host:   "nowhere.example",
+			setup:  seedPageTree,
+			status: http.StatusNotFound,
+			golden: "get_navigation_unknown_host",
+		},
+	})
+}
+
+func TestGetPageTreeHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/10/tree",
+			setup:  seedPageTree,
+			status: http.StatusOK,
+			golden: "get_page_tree_ok",
+		},
+		{
+			name:   "OtherSite",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/14/tree",
+			setup:  seedPageTree,
+			status: http.StatusNotFound,
+			golden: "get_page_tree_other_site",
+		},
+		{
+			name:   "NotFound",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/42/tree",
+			setup:  seedPageTree,
+			status: http.StatusNotFound,
+			golden: "get_page_tree_not_found",
+		},
+	})
+}
+
+func TestMovePageHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "UnderParent",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/11/parent",
+			body:   map[string]any{"parent_id": 10, "menu_order": 2},
+			setup:  seedPageTree,
+			status: http.StatusOK,
+			golden: "move_page_under_parent",
+		},
+		{
+			name:   "ToTopLevel",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/13/parent",
+			body:   map[string]any{"parent_id": nil, "menu_order": 2},
+			setup:  seedPageTree,
+			status: http.StatusOK,
+			golden: "move_page_to_top_level",
+		},
+		{
+			name:   "InvalidParent",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/13/parent",
+			body:   map[string]any{"parent_id": 0, "menu_order": -1},
+			setup:  seedPageTree,
+			status: http.StatusBadRequest,
+			golden: "move_page_invalid",
+		},
+		{
+			name:   "NotFound",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/42/parent",
+			body:   map[string]any{"parent_id": 10},
+			setup:  seedPageTree,
+			status: http.StatusNotFound,
+			golden: "move_page_not_found",
+		},
+	})
+}
+
+func TestReorderPagesHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodPut,
+			path:   "/api/v1/navigation/order",
+			body:   map[string]any{"parent_id": 10, "page_ids": []int64{12, 13}},
+			setup:  seedPageTree,
+			status: http.StatusOK,
+			golden: "reorder_pages_ok",
+		},
+		{
+			name:   "MissingPageIDs",
+			method: http.MethodPut,
+			path:   "/api/v1/navigation/order",
+			body:   map[string]any{"page_ids": []int64{}},
+			setup:  seedPageTree,
+			status: http.StatusBadRequest,
+			golden: "reorder_pages_missing_ids",
+		},
+		{
+			name:   "NotAllSiblings",
+			method: http.MethodPut,
+			path:   "/api/v1/navigation/order",
+			body:   map[string]any{"page_ids": []int64{11}},
+			setup: func(store *fakeStore) {
+				store.err = apperr.Validation("page_ids must list all 2 sibling pages", nil)
+			},
+			status: http.StatusBadRequest,
+			golden: "reorder_pages_not_all_siblings",
+		},
+	})
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/validation"
+)
+
+// Length limits follow the varchar(255) columns of the pages and page_options tables
+type pageOptionRequest struct {
+	Name     string          `json:"name" binding:"required,max=255"`
+	Type     string          `json:"type" binding:"required,enum=option_type"`
+	Value    json.RawMessage `json:"value" binding:"required"`
+	Required bool            `json:"required"`
+}
+
+// pageRequest is a page on the site of the request
+type pageRequest struct {
+	AuthorID       int64  `json:"author_id" binding:"required,min=1"`
+	PageAuthor     string `json:"page_author" binding:"required,max=255"`
+	Title          string `json:"title" binding:"required,max=255"`
+	Url            string `json:"url" binding:"required,max=255"`
+	MenuOrder      int64  `json:"menu_order" binding:"min=0"`
+	PageIdentifier string `json:"page_identifier" binding:"required,max=255"`
+	// Options are stored in this order. On update, leaving them out keeps
+	// the page's options and an empty array removes them.
+	Options []pageOptionRequest `json:"options" binding:"omitempty,max=100,dive"`
+}
+
+// pageResponse is a page with its options
+type pageResponse struct {
+	db.Page
+	Options []db.PageOption `json:"options"`
+}
+
+func newPageResponse(page db.Page, options []db.PageOption) pageResponse {
+	if options == nil {
+		options = []db.PageOption{}
+	}
+	return pageResponse{Page: page, Options: options}
+}
+
+// bindPageRequest binds the body and checks what the binding rules can't:
+// option values must match their type and option names must be unique
+func bindPageRequest(ctx *gin.Context) (pageRequest, bool) {
+	var req pageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(validation.FromBinding(err))
+		return req, false
+	}
+
+	var fields []apperr.FieldError
+	seen := make(map[string]bool, len(req.Options))
+	for i, option := range req.Options {
+		if seen[option.Name] {
+			fields = append(fields, apperr.FieldError{
+				Field:   fmt.Sprintf("options[%d].name", i),
+				Rule:    "unique",
+				Message: "must be unique within the page",
+			})
+		}
+		seen[option.Name] = true
+
+		if !optionValueMatches(db.OptionType(option.Type), option.Value) {
+			message := "must be a " + option.Type
+			if db.OptionType(option.Type) == db.OptionTypeJson {
+				message = "must not be null"
+			}
+			fields = append(fields, apperr.FieldError{
+				Field:   fmt.Sprintf("options[%d].value", i),
+				Rule:    "type",
+				Message: message,
+			})
+		}
+	}
+	if len(fields) > 0 {
+		ctx.Error(apperr.InvalidFields(fields, nil))
+		return req, false
+	}
+	return req, true
+}
+
+// optionValueMatches mirrors the check constraint on page_options.value;
+// null is rejected for every type
+func optionValueMatches(optionType db.OptionType, value json.RawMessage) bool {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+		return false
+	}
+	switch optionType {
+	case db.OptionTypeString:
+		return value[0] == '"'
+	case db.OptionTypeNumber:
+		return value[0] == '-' || (value[0] >= '0' && value[0] <= '9')
+	case db.OptionTypeBoolean:
+		return bytes.Equal(value, []byte("true")) || bytes.Equal(value, []byte("false"))
+	}
+	return true
+}
+
+// pageOptionParams converts the request options, keeping nil apart from empty
+func pageOptionParams(options []pageOptionRequest) []db.PageOptionParams {
+	if options == nil {
+		return nil
+	}
+	params := make([]db.PageOptionParams, 0, len(options))
+	for _, option := range options {
+		params = append(params, db.PageOptionParams{
+			Name:     option.Name,
+			Type:     db.OptionType(option.Type),
+			Value:    option.Value,
+			Required: option.Required,
+		})
+	}
+	return params
+}
+
+func CreatePagesHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		req, ok := bindPageRequest(ctx)
+		if !ok {
+			return
+		}
+
+		args := db.CreateContentTxParams{
+			SiteID:   site.ID,
+			UserId:   req.AuthorID,
+			Username: req.PageAuthor,
+			Pages: []db.CreatePagesParams{{
+				SiteID:         site.ID,
+				AuthorID:       req.AuthorID,
+				PageAuthor:     req.PageAuthor,
+				Title:          req.Title,
+				Url:            req.Url,
+				MenuOrder:      req.MenuOrder,
+				PageIdentifier: req.PageIdentifier,
+			}},
+			PageOptions: [][]db.PageOptionParams{pageOptionParams(req.Options)},
+		}
+
+		result, err := store.CreatePageTx(ctx, args)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.InvalidFields([]apperr.FieldError{{
+					Field:   "author_id",
+					Rule:    "exists",
+					Message: "must be a member of the site",
+				}}, err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, newPageResponse(result.Pages[0], result.PageOptions[0]))
+	}
+}
+
+type getPagesRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func GetPagesHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var req getPagesRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		page, err := store.GetPages(ctx, db.GetPagesParams{SiteID: site.ID, ID: req.ID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("page not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+
+		options, err := store.ListPageOptions(ctx, req.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, newPageResponse(page, options))
+	}
+}
+
+func UpdatePagesHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri getPagesRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		req, ok := bindPageRequest(ctx)
+		if !ok {
+			return
+		}
+
+		args := db.UpdateContentTxParams{
+			SiteID:   site.ID,
+			UserId:   req.AuthorID,
+			Username: req.PageAuthor,
+			PageId:   &uri.ID,
+			Pages: []db.UpdatePagesParams{{
+				SiteID:         site.ID,
+				ID:             uri.ID,
+				AuthorID:       req.AuthorID,
+				PageAuthor:     req.PageAuthor,
+				Title:          req.Title,
+				Url:            req.Url,
+				MenuOrder:      req.MenuOrder,
+				PageIdentifier: req.PageIdentifier,
+			}},
+			PageOptions: [][]db.PageOptionParams{pageOptionParams(req.Options)},
+		}
+
+		result, err := store.UpdatePageTx(ctx, args)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("page or author not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, newPageResponse(result.Pages[0], result.PageOptions[0]))
+	}
+}
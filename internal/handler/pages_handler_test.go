@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+func seedAuthor(store *fakeStore) {
+	store.addUser(db.User{ID: 7, Username: "frog", Email: "frog@example.com", Role: "user"})
+}
+
+func seedPage(store *fakeStore) {
+	seedAuthor(store)
+	store.addPage(db.Page{
+		ID:             3,
+		SiteID:         testSite.ID,
+		AuthorID:       7,
+		PageAuthor:     "frog",
+		Title:          "Home",
+		Url:            "/",
+		PageIdentifier: "home",
+	}, []db.PageOptionParams{
+		{Name: "site_title", Type: db.OptionTypeString, Value: json.RawMessage(`"My Website"`), Required: true},
+		{Name: "show_sidebar", Type: db.OptionTypeBoolean, Value: json.RawMessage(`false`)},
+	})
+}
+
+func validPageRequest() pageRequest {
+	return pageRequest{
+		AuthorID:       7,
+		PageAuthor:     "frog",
+		Title:          "Home",
+		Url:            "/",
+		MenuOrder:      1,
+		PageIdentifier: "home",
+		Options: []pageOptionRequest{
+			{Name: "site_title", Type: "string", Value: json.RawMessage(`"My Website"`), Required: true},
+			{Name: "posts_per_page", Type: "number", Value: json.RawMessage(`10`)},
+			{Name: "theme", Type: "json", Value: json.RawMessage(`{"color":"green"}`)},
+		},
+	}
+}
+
+func TestCreatePagesHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodPost,
+			path:   "/api/v1/pages",
+			body:   validPageRequest(),
+			setup:  seedAuthor,
+			status: http.StatusOK,
+			golden: "create_pages_ok",
+		},
+		{
+			name:   "InvalidOptions",
+			method: http.MethodPost,
+			path:   "/api/v1/pages",
+			body: func() pageRequest {
+				req := validPageRequest()
+				req.Options = []pageOptionRequest{
+					{Name: "site_title", Type: "string", Value: json.RawMessage(`42`)},
+					{Name: "site_title", Type: "boolean", Value: json.RawMessage(`true`)},
+					{Name: "theme", Type: "json", Value: json.RawMessage(`null`)},
+				}
+				return req
+			}(),
+			setup:  seedAuthor,
+			status: http.StatusBadRequest,
+			golden: "create_pages_invalid_options",
+		},
+		{
+			name:   "UnknownOptionType",
+			method: http.MethodPost,
+			path:   "/api/v1/pages",
+			body: func() pageRequest {
+				req := validPageRequest()
+				req.Options[1].Type = "color"
+				return req
+			}(),
+			setup:  seedAuthor,
+			status: http.StatusBadRequest,
+			golden: "create_pages_unknown_option_type",
+		},
+		{
+			name:   "UnknownAuthor",
+			method: http.MethodPost,
+			path:   "/api/v1/pages",
+			body:   validPageRequest(),
+			status: http.StatusBadRequest,
+			golden: "create_pages_unknown_author",
+		},
+	})
+}
+
+func TestGetPagesHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/3",
+			setup:  seedPage,
+			status: http.StatusOK,
+			golden: "get_pages_ok",
+		},
+		{
+			name:   "NotFound",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/42",
+			status: http.StatusNotFound,
+			golden: "get_pages_not_found",
+		},
+	})
+}
+
+func TestUpdatePagesHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "ReplacesOptions",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/3",
+			body:   validPageRequest(),
+			setup:  seedPage,
+			status: http.StatusOK,
+			golden: "update_pages_replaces_options",
+		},
+		{
+			name:   "KeepsOptions",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/3",
+			body: func() pageRequest {
+				req := validPageRequest()
+				req.Title = "Start"
+				req.Options = nil
+				return req
+			}(),
+			setup:  seedPage,
+			status: http.StatusOK,
+			golden: "update_pages_keeps_options",
+		},
+		{
+			name:   "NotFound",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/42",
+			body:   validPageRequest(),
+			setup:  seedAuthor,
+			status: http.StatusNotFound,
+			golden: "update_pages_not_found",
+		},
+	})
+}
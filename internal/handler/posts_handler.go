@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/content"
+	"github.com/reflection/frog_blossom_db/internal/locale"
+	"github.com/reflection/frog_blossom_db/internal/sitesettings"
+	"github.com/reflection/frog_blossom_db/internal/validation"
+)
+
+// Length limits follow the varchar(255) columns of the posts and meta tables
+type postMetaRequest struct {
+	MetaTitle       string `json:"meta_title" binding:"max=255"`
+	MetaDescription string `json:"meta_description"`
+	MetaRobots      string `json:"meta_robots" binding:"max=255"`
+	MetaOgImage     string `json:"meta_og_image" binding:"omitempty,http_url,max=255"`
+	MetaKey         string `json:"meta_key" binding:"required,max=255"`
+	MetaValue       string `json:"meta_value" binding:"required,max=255"`
+}
+
+// createPostsRequest is a post on the site of the request, with its meta
+// when there is one
+type createPostsRequest struct {
+	AuthorID     int64            `json:"author_id" binding:"required,min=1"`
+	PostAuthor   string           `json:"post_author" binding:"required,max=255"`
+	Title        string           `json:"title" binding:"required,max=255"`
+	Content      string           `json:"content" binding:"required"`
+	Url          string           `json:"url" binding:"required,max=255"`
+	Status       string           `json:"status" binding:"required,enum=post_status"`
+	PostMimeType string           `json:"post_mime_type" binding:"required,max=255"`
+	Meta         *postMetaRequest `json:"meta"`
+}
+
+// createdPostResponse is a post with its meta, null without one
+type createdPostResponse struct {
+	db.Post
+	Meta *db.Meta `json:"meta"`
+}
+
+// nullString stores an empty string as null
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func CreatePostsHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var req createPostsRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		now := time.Now().UTC()
+		args := db.CreateContentTxParams{
+			SiteID:   site.ID,
+			UserId:   req.AuthorID,
+			Username: req.PostAuthor,
+			Posts: []db.CreatePostsParams{{
+				SiteID:       site.ID,
+				Title:        req.Title,
+				Content:      req.Content,
+				AuthorID:     req.AuthorID,
+				Url:          req.Url,
+				UpdatedAt:    now,
+				Status:       db.PostStatus(req.Status),
+				PublishedAt:  now,
+				EditedAt:     now,
+				PostAuthor:   req.PostAuthor,
+				PostMimeType: req.PostMimeType,
+				PublishedBy:  req.PostAuthor,
+				UpdatedBy:    req.PostAuthor,
+			}},
+		}
+		if req.Meta != nil {
+			args.Metas = []db.CreateMetaParams{{
+				SiteID:          site.ID,
+				MetaTitle:       nullString(req.Meta.MetaTitle),
+				MetaDescription: nullString(req.Meta.MetaDescription),
+				MetaRobots:      nullString(req.Meta.MetaRobots),
+				MetaOgImage:     nullString(req.Meta.MetaOgImage),
+				MetaKey:         req.Meta.MetaKey,
+				MetaValue:       req.Meta.MetaValue,
+			}}
+		}
+
+		result, err := store.CreatePostsTx(ctx, args)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.InvalidFields([]apperr.FieldError{{
+					Field:   "author_id",
+					Rule:    "exists",
+					Message: "must be a member of the site",
+				}}, err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+
+		res := createdPostResponse{Post: result.Posts[0]}
+		if len(result.Metas) > 0 {
+			res.Meta = &result.Metas[0]
+		}
+		ctx.JSON(http.StatusOK, res)
+	}
+}
+
+type getPostsRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type getPostsQuery struct {
+	Locale string `form:"locale" binding:"omitempty,max=35,locale"`
+}
+
+// postResponse is a post in its locale, with its translations and rendered
+// content. Rendered is null for mime types there is no renderer for; the
+// raw content is in the post either way.
+type postResponse struct {
+	Locale     string            `json:"locale"`
+	Post       db.Post           `json:"post"`
+	Alternates []alternate       `json:"alternates"`
+	Rendered   *content.Rendered `json:"rendered"`
+}
+
+// GetPostsHandler serves the post, or its translation in the best locale
+// there is for the visitor: the locale query parameter, or else
+// Accept-Language, falling back to the base language and then the site
+// language. Its content is rendered by mime type, see content.Pipeline.
+func GetPostsHandler(store db.Store, pipeline *content.Pipeline) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var req getPostsRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		var query getPostsQuery
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		settings, err := sitesettings.Load(ctx, store, site.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		siteLocale := settings.String("site_language")
+
+		var requested []string
+		if query.Locale != "" {
+			tag, _ := locale.Parse(query.Locale)
+			requested = []string{tag}
+		} else {
+			ctx.Header("Vary", "Accept-Language")
+			requested = locale.FromAcceptLanguage(ctx.GetHeader("Accept-Language"))
+		}
+
+		post, err := store.GetPostInLocale(ctx, db.GetPostInLocaleParams{
+			SiteID:  site.ID,
+			ID:      req.ID,
+			Locales: locale.Chain(requested, siteLocale),
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("post not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+
+		variants, err := postTranslations(ctx, store, post)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		res := postResponse{
+			Post:       post,
+			Locale:     contentLocale(post.Locale, siteLocale),
+			Alternates: postAlternates(ctx, site, siteLocale, variants),
+		}
+		rendered, err := pipeline.Render(ctx, post.PostMimeType, post.Content)
+		switch {
+		case errors.Is(err, content.ErrUnsupported):
+			slog.WarnContext(ctx, "post content not rendered", slog.Int64("post_id", post.ID), slog.Any("error", err))
+		case err != nil:
+			ctx.Error(err)
+			return
+		default:
+			res.Rendered = &rendered
+		}
+		ctx.Header("Content-Language", res.Locale)
+		ctx.Header("Link", linkHeader(res.Alternates))
+		ctx.JSON(http.StatusOK, res)
+	}
+}
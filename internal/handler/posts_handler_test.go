@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// seedPosts stores post 40 of testSite as mimeType, and post 41 of otherSite
+func seedPosts(mimeType, body string) func(store *fakeStore) {
+	return func(store *fakeStore) {
+		seedAuthor(store)
+		post := db.Post{
+			ID:           40,
+			SiteID:       testSite.ID,
+			Title:        "Pond life",
+			Content:      body,
+			AuthorID:     7,
+			Url:          "/pond-life",
+			CreatedAt:    fixedTime,
+			UpdatedAt:    fixedTime,
+			Status:       db.PostStatusPublish,
+			PublishedAt:  fixedTime,
+			EditedAt:     fixedTime,
+			PostAuthor:   "frog",
+			PostMimeType: mimeType,
+			PublishedBy:  "frog",
+			UpdatedBy:    "frog",
+		}
+		store.posts[post.ID] = post
+		post.ID, post.SiteID = 41, otherSite.ID
+		store.posts[post.ID] = post
+	}
+}
+
+const spawningLinks = `<http://example.com/de/laichen>; rel="alternate"; hreflang="de", ` +
+	`<http://example.com/spawning>; rel="alternate"; hreflang="en", ` +
+	`<http://example.com/fr/le-frai>; rel="alternate"; hreflang="fr", ` +
+	`<http://example.com/spawning>; rel="alternate"; hreflang="x-default"`
+
+const testMarkdown = "# Pond life\n\nFrogs **hop** between [lily pads](https://example.com/lilies).\n\n<script>alert(1)</script>\n\n## Tadpoles\n\n- eggs\n- tadpoles\n"
+
+func validCreatePostsRequest() createPostsRequest {
+	return createPostsRequest{
+		AuthorID:     7,
+		PostAuthor:   "frog",
+		Title:        "Pond life",
+		Content:      testMarkdown,
+		Url:          "/pond-life",
+		Status:       "draft",
+		PostMimeType: "text/markdown",
+		Meta: &postMetaRequest{
+			MetaTitle:   "Pond life",
+			MetaOgImage: "https://example.com/pond.jpg",
+			MetaKey:     "og",
+			MetaValue:   "article",
+		},
+	}
+}
+
+func TestCreatePostsHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodPost,
+			path:   "/api/v1/posts",
+			body:   validCreatePostsRequest(),
+			setup:  seedAuthor,
+			status: http.StatusOK,
+			golden: "create_posts_ok",
+		},
+		{
+			name:   "WithoutMeta",
+			method: http.MethodPost,
+			path:   "/api/v1/posts",
+			body: func() createPostsRequest {
+				req := validCreatePostsRequest()
+				req.Meta = nil
+				return req
+			}(),
+			setup:  seedAuthor,
+			status: http.StatusOK,
+			golden: "create_posts_without_meta",
+		},
+		{
+			name:   "InvalidFields",
+			method: http.MethodPost,
+			path:   "/api/v1/posts",
+			body: func() createPostsRequest {
+				req := validCreatePostsRequest()
+				req.Status = "admin"
+				req.Meta.MetaOgImage = "javascript:alert(1)"
+				return req
+			}(),
+			setup:  seedAuthor,
+			status: http.StatusBadRequest,
+			golden: "create_posts_invalid_fields",
+		},
+		{
+			name:   "UnknownAuthor",
+			method: http.MethodPost,
+			path:   "/api/v1/posts",
+			body:   validCreatePostsRequest(),
+			status: http.StatusBadRequest,
+			golden: "create_posts_unknown_author",
+		},
+	})
+}
+
+func TestGetPostsHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "Markdown",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/40",
+			setup:  seedPosts("text/markdown", testMarkdown),
+			status: http.StatusOK,
+			golden: "get_posts_markdown",
+		},
+		{
+			name:   "HTML",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/40",
+			setup:  seedPosts("text/html; charset=utf-8", `<h2 onclick="x()">Spawn</h2><p>In <b>spring</b>.</p><iframe src="https://example.com"></iframe>`),
+			status: http.StatusOK,
+			golden: "get_posts_html",
+		},
+		{
+			name:   "Plain",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/40",
+			setup:  seedPosts("text/plain", "Ribbit <b>ribbit</b>\n\nribbit"),
+			status: http.StatusOK,
+			golden: "get_posts_plain",
+		},
+		{
+			name:   "UnsupportedMimeType",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/40",
+			setup:  seedPosts("application/rtf", `{\rtf1 frog}`),
+			status: http.StatusOK,
+			golden: "get_posts_unsupported",
+		},
+		{
+			name:   "OtherSite",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/41",
+			setup:  seedPosts("text/plain", "ribbit"),
+			status: http.StatusNotFound,
+			golden: "get_posts_not_found",
+		},
+		{
+			name:    "SiteLanguage",
+			method:  http.MethodGet,
+			path:    "/api/v1/posts/51",
+			setup:   seedPostTranslations,
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "en", "Vary": "Accept-Language", "Link": spawningLinks},
+			golden:  "get_posts_site_language",
+		},
+		{
+			name:    "AcceptLanguage",
+			method:  http.MethodGet,
+			path:    "/api/v1/posts/50",
+			header:  http.Header{"Accept-Language": {"ja;q=0.9, de-AT, en;q=0.5"}},
+			setup:   seedPostTranslations,
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "de", "Vary": "Accept-Language"},
+			golden:  "get_posts_accept_language",
+		},
+		{
+			name:    "LocaleQueryFallsBackToLanguage",
+			method:  http.MethodGet,
+			path:    "/api/v1/posts/50?locale=fr-CA",
+			header:  http.Header{"Accept-Language": {"de"}},
+			setup:   seedPostTranslations,
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "fr", "Vary": "", "Link": spawningLinks},
+			golden:  "get_posts_locale_query",
+		},
+		{
+			name:    "Untranslated",
+			method:  http.MethodGet,
+			path:    "/api/v1/posts/53?locale=fr",
+			setup:   seedPostTranslations,
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Language": "en"},
+			golden:  "get_posts_untranslated",
+		},
+		{
+			name:   "InvalidLocale",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/50?locale=klingon",
+			setup:  seedPostTranslations,
+			status: http.StatusBadRequest,
+			golden: "get_posts_invalid_locale",
+		},
+		{
+			name:   "InvalidID",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/0",
+			status: http.StatusBadRequest,
+			golden: "get_posts_invalid_id",
+		},
+	})
+}
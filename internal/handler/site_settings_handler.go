@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/sitesettings"
+	"github.com/reflection/frog_blossom_db/internal/validation"
+)
+
+type siteSettingURI struct {
+	Key string `uri:"key" binding:"required,max=255"`
+}
+
+func GetSiteSettingsHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		settings, err := sitesettings.Load(ctx, store, site.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{
+			"domain":   site.Domain,
+			"settings": settings.Values(),
+		})
+	}
+}
+
+type putSiteSettingRequest struct {
+	Value json.RawMessage `json:"value" binding:"required"`
+}
+
+func PutSiteSettingHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri siteSettingURI
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		var req putSiteSettingRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		value, err := sitesettings.Decode(uri.Key, req.Value)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		// store the decoded value so e.g. 10.0 is kept as 10
+		raw, err := json.Marshal(value)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		_, err = store.UpsertSiteSetting(ctx, db.UpsertSiteSettingParams{
+			SiteID: site.ID,
+			Key:    uri.Key,
+			Value:  raw,
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		def, _ := sitesettings.Lookup(uri.Key)
+		ctx.JSON(http.StatusOK, def.Value(value))
+	}
+}
+
+// DeleteSiteSettingHandler resets a setting to its default
+func DeleteSiteSettingHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri siteSettingURI
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		def, ok := sitesettings.Lookup(uri.Key)
+		if !ok {
+			ctx.Error(apperr.NotFound("unknown setting "+uri.Key, nil))
+			return
+		}
+
+		err := store.DeleteSiteSetting(ctx, db.DeleteSiteSettingParams{
+			SiteID: site.ID,
+			Key:    uri.Key,
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, def.Value(nil))
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+var adminHeader = http.Header{"Authorization": {"Bearer " + testAdminToken}}
+
+func seedSiteSettings(store *fakeStore) {
+	for _, setting := range []db.UpsertSiteSettingParams{
+		{SiteID: testSite.ID, Key: "page_amount", Value: []byte(`25`)},
+		{SiteID: testSite.ID, Key: "site_title", Value: []byte(`"Frog Blossom"`)},
+		{SiteID: otherSite.ID, Key: "site_language", Value: []byte(`"fr"`)},
+	} {
+		if _, err := store.UpsertSiteSetting(context.Background(), setting); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func TestGetSiteSettingsHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/site/settings",
+			header: adminHeader,
+			setup:  seedSiteSettings,
+			status: http.StatusOK,
+			golden: "get_site_settings_ok",
+		},
+		{
+			name:   "Forbidden",
+			method: http.MethodGet,
+			path:   "/api/v1/site/settings",
+			status: http.StatusForbidden,
+			golden: "get_site_settings_forbidden",
+		},
+	})
+}
+
+func TestPutSiteSettingHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodPut,
+			path:   "/api/v1/site/settings/site_language",
+			header: adminHeader,
+			body:   map[string]any{"value": "pt-BR"},
+			status: http.StatusOK,
+			golden: "put_site_setting_ok",
+		},
+		{
+			name:   "NormalisesLanguage",
+			method: http.MethodPut,
+			path:   "/api/v1/site/settings/site_language",
+			header: adminHeader,
+			body:   map[string]any{"value": "pt-br"},
+			status: http.StatusOK,
+			golden: "put_site_setting_normalised",
+		},
+		{
+			name:   "InvalidValue",
+			method: http.MethodPut,
+			path:   "/api/v1/site/settings/page_amount",
+			header: adminHeader,
+			body:   map[string]any{"value": 1000},
+			status: http.StatusBadRequest,
+			golden: "put_site_setting_invalid_value",
+		},
+		{
+			name:   "WrongType",
+			method: http.MethodPut,
+			path:   "/api/v1/site/settings/comments_enabled",
+			header: adminHeader,
+			body:   map[string]any{"value": "yes"},
+			status: http.StatusBadRequest,
+			golden: "put_site_setting_wrong_type",
+		},
+		{
+			name:   "UnknownKey",
+			method: http.MethodPut,
+			path:   "/api/v1/site/settings/theme",
+			header: adminHeader,
+			body:   map[string]any{"value": "dark"},
+			status: http.StatusNotFound,
+			golden: "put_site_setting_unknown_key",
+		},
+	})
+}
+
+func TestDeleteSiteSettingHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "ResetsToDefault",
+			method: http.MethodDelete,
+			path:   "/api/v1/site/settings/page_amount",
+			header: adminHeader,
+			setup:  seedSiteSettings,
+			status: http.StatusOK,
+			golden: "delete_site_setting_ok",
+		},
+		{
+			name:   "UnknownKey",
+			method: http.MethodDelete,
+			path:   "/api/v1/site/settings/theme",
+			header: adminHeader,
+			status: http.StatusNotFound,
+			golden: "delete_site_setting_unknown_key",
+		},
+	})
+}
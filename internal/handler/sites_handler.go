@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/middleware"
+	"github.com/reflection/frog_blossom_db/internal/validation"
+)
+
+// requireSite returns the site middleware.Site scoped the request to
+func requireSite(ctx *gin.Context) (db.Site, bool) {
+	site, ok := db.SiteFrom(ctx)
+	if !ok {
+		ctx.Error(errors.New("route is not scoped to a site"))
+	}
+	return site, ok
+}
+
+type createSiteRequest struct {
+	Domain string `json:"domain" binding:"required,max=255,hostname_rfc1123"`
+	Name   string `json:"name" binding:"required,max=255"`
+}
+
+func CreateSiteHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		var req createSiteRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		site, err := store.CreateSite(ctx, db.CreateSiteParams{
+			Domain: middleware.Domain(req.Domain),
+			Name:   req.Name,
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, site)
+	}
+}
+
+type listRequest struct {
+	Limit  int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int32 `form:"offset" binding:"omitempty,min=0"`
+}
+
+func ListSitesHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		req := listRequest{Limit: 50}
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		sites, err := store.ListSites(ctx, db.ListSitesParams{Limit: req.Limit, Offset: req.Offset})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if sites == nil {
+			sites = []db.Site{}
+		}
+		ctx.JSON(http.StatusOK, sites)
+	}
+}
+
+func ListSiteMembersHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		members, err := store.ListSiteMembers(ctx, site.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if members == nil {
+			members = []db.SiteMember{}
+		}
+		ctx.JSON(http.StatusOK, members)
+	}
+}
+
+type siteMemberURI struct {
+	UserID int64 `uri:"user_id" binding:"required,min=1"`
+}
+
+type putSiteMemberRequest struct {
+	Role string `json:"role" binding:"required,enum=site_role"`
+}
+
+// PutSiteMemberHandler adds a user to the site or changes their role
+func PutSiteMemberHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri siteMemberURI
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		var req putSiteMemberRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		if _, err := store.GetUsers(ctx, uri.UserID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("user not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+
+		member, err := store.UpsertSiteMember(ctx, db.UpsertSiteMemberParams{
+			SiteID: site.ID,
+			UserID: uri.UserID,
+			Role:   db.SiteRole(req.Role),
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, member)
+	}
+}
+
+func DeleteSiteMemberHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri siteMemberURI
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		member, err := store.DeleteSiteMember(ctx, db.DeleteSiteMemberParams{SiteID: site.ID, UserID: uri.UserID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("user is not a member of the site", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, member)
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+func TestCreateSiteHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodPost,
+			path:   "/api/v1/sites",
+			host:   "admin.example",
+			header: adminHeader,
+			body:   map[string]any{"domain": "Pond.Example", "name": "Pond"},
+			status: http.StatusOK,
+			golden: "create_site_ok",
+		},
+		{
+			name:   "InvalidDomain",
+			method: http.MethodPost,
+			path:   "/api/v1/sites",
+			header: adminHeader,
+			body:   map[string]any{"domain": "not a domain", "name": "Pond"},
+			status: http.StatusBadRequest,
+			golden: "create_site_invalid_domain",
+		},
+		{
+			name:   "DomainTaken",
+			method: http.MethodPost,
+			path:   "/api/v1/sites",
+			header: adminHeader,
+			body:   map[string]any{"domain": testSite.Domain, "name": "Again"},
+			status: http.StatusConflict,
+			golden: "create_site_domain_taken",
+		},
+		{
+			name:   "Forbidden",
+			method: http.MethodPost,
+			path:   "/api/v1/sites",
+			body:   map[string]any{"domain": "pond.example", "name": "Pond"},
+			status: http.StatusForbidden,
+			golden: "create_site_forbidden",
+		},
+	})
+}
+
+func TestListSitesHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/sites?limit=1&offset=1",
+			header: adminHeader,
+			status: http.StatusOK,
+			golden: "list_sites_ok",
+		},
+	})
+}
+
+func TestSiteMembersHandlers(t *testing.T) {
+	seedMembers := func(store *fakeStore) {
+		seedAuthor(store)
+		store.addUser(db.User{ID: 8, Username: "toad", Email: "toad@example.com", Role: "user"})
+		store.UpsertSiteMember(context.Background(), db.UpsertSiteMemberParams{SiteID: otherSite.ID, UserID: 8, Role: db.SiteRoleOwner})
+	}
+
+	runCases(t, []apiCase{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			path:   "/api/v1/site/members",
+			header: adminHeader,
+			setup:  seedMembers,
+			status: http.StatusOK,
+			golden: "list_site_members_ok",
+		},
+		{
+			name:   "PutChangesRole",
+			method: http.MethodPut,
+			path:   "/api/v1/site/members/7",
+			header: adminHeader,
+			body:   map[string]any{"role": "viewer"},
+			setup:  seedMembers,
+			status: http.StatusOK,
+			golden: "put_site_member_ok",
+		},
+		{
+			name:   "PutUnknownRole",
+			method: http.MethodPut,
+			path:   "/api/v1/site/members/7",
+			header: adminHeader,
+			body:   map[string]any{"role": "admin"},
+			setup:  seedMembers,
+			status: http.StatusBadRequest,
+			golden: "put_site_member_unknown_role",
+		},
+		{
+			name:   "PutUnknownUser",
+			method: http.MethodPut,
+			path:   "/api/v1/site/members/42",
+			header: adminHeader,
+			body:   map[string]any{"role": "editor"},
+			status: http.StatusNotFound,
+			golden: "put_site_member_unknown_user",
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   "/api/v1/site/members/7",
+			header: adminHeader,
+			setup:  seedMembers,
+			status: http.StatusOK,
+			golden: "delete_site_member_ok",
+		},
+		{
+			name:   "DeleteNotAMember",
+			method: http.MethodDelete,
+			path:   "/api/v1/site/members/7",
+			host:   otherSite.Domain,
+			header: adminHeader,
+			setup:  seedMembers,
+			status: http.StatusNotFound,
+			golden: "delete_site_member_not_a_member",
+		},
+	})
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/locale"
+	"github.com/reflection/frog_blossom_db/internal/sitesettings"
+	"github.com/reflection/frog_blossom_db/internal/validation"
+)
+
+// pageTranslations returns the pages of the page's translation group, or
+// just the page when it has no translations
+func pageTranslations(ctx *gin.Context, store db.Store, page db.Page) ([]db.Page, error) {
+	if !page.TranslationGroupID.Valid {
+		return []db.Page{page}, nil
+	}
+	return store.ListPageTranslations(ctx, db.ListPageTranslationsParams{
+		SiteID:             page.SiteID,
+		TranslationGroupID: page.TranslationGroupID,
+	})
+}
+
+// postTranslations returns the posts of the post's translation group, or
+// just the post when it has no translations
+func postTranslations(ctx *gin.Context, store db.Store, post db.Post) ([]db.Post, error) {
+	if !post.TranslationGroupID.Valid {
+		return []db.Post{post}, nil
+	}
+	return store.ListPostTranslations(ctx, db.ListPostTranslationsParams{
+		SiteID:             post.SiteID,
+		TranslationGroupID: post.TranslationGroupID,
+	})
+}
+
+func ListPageTranslationsHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri getPagesRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		page, err := store.GetPages(ctx, db.GetPagesParams{SiteID: site.ID, ID: uri.ID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("page not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+
+		pages, err := pageTranslations(ctx, store, page)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"translations": pages})
+	}
+}
+
+type translationURI struct {
+	ID     int64  `uri:"id" binding:"required,min=1"`
+	Locale string `uri:"locale" binding:"required,max=35,locale"`
+}
+
+type putPageTranslationRequest struct {
+	PageID int64 `json:"page_id" binding:"required,min=1"`
+}
+
+// PutPageTranslationHandler makes page_id the translation of the page in
+// the locale of the URI. A page without a locale is taken to be in the
+// site language.
+func PutPageTranslationHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri translationURI
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		var req putPageTranslationRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		settings, err := sitesettings.Load(ctx, store, site.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		tag, _ := locale.Parse(uri.Locale)
+
+		result, err := store.AddPageTranslationTx(ctx, db.AddTranslationTxParams{
+			SiteID:       site.ID,
+			SourceID:     uri.ID,
+			SourceLocale: settings.String("site_language"),
+			ID:           req.PageID,
+			Locale:       tag,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("page not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"translations": result.Pages})
+	}
+}
+
+// DeletePageTranslationHandler takes the page out of its translation
+// group. It keeps its locale.
+func DeletePageTranslationHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri getPagesRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		page, err := store.LeavePageTranslationGroup(ctx, db.LeavePageTranslationGroupParams{SiteID: site.ID, ID: uri.ID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("page not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, page)
+	}
+}
+
+func ListPostTranslationsHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri getPostsRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		post, err := store.GetPosts(ctx, db.GetPostsParams{SiteID: site.ID, ID: uri.ID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("post not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+
+		posts, err := postTranslations(ctx, store, post)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"translations": posts})
+	}
+}
+
+type putPostTranslationRequest struct {
+	PostID int64 `json:"post_id" binding:"required,min=1"`
+}
+
+// PutPostTranslationHandler makes post_id the translation of the post in
+// the locale of the URI. A post without a locale is taken to be in the
+// site language.
+func PutPostTranslationHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri translationURI
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		var req putPostTranslationRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		settings, err := sitesettings.Load(ctx, store, site.ID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		tag, _ := locale.Parse(uri.Locale)
+
+		result, err := store.AddPostTranslationTx(ctx, db.AddTranslationTxParams{
+			SiteID:       site.ID,
+			SourceID:     uri.ID,
+			SourceLocale: settings.String("site_language"),
+			ID:           req.PostID,
+			Locale:       tag,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("post not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"translations": result.Posts})
+	}
+}
+
+// DeletePostTranslationHandler takes the post out of its translation
+// group. It keeps its locale.
+func DeletePostTranslationHandler(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
+		var uri getPostsRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		post, err := store.LeavePostTranslationGroup(ctx, db.LeavePostTranslationGroupParams{SiteID: site.ID, ID: uri.ID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("post not found", err))
+				return
+			}
+
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, post)
+	}
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// seedTranslations adds About in en, fr and de, the untranslated Contact
+// and Kontakt pages on testSite, and an About page on otherSite
+func seedTranslations(store *fakeStore) {
+	seedAuthor(store)
+	group := sql.NullInt64{Int64: 20, Valid: true}
+	in := func(locale string) sql.NullString { return sql.NullString{String: locale, Valid: true} }
+	for _, page := range []db.Page{
+		{ID: 20, SiteID: testSite.ID, Title: "About", Url: "/about", PageIdentifier: "about", Locale: in("en"), TranslationGroupID: group},
+		{ID: 21, SiteID: testSite.ID, Title: "À propos", Url: "/a-propos", PageIdentifier: "about-fr", Locale: in("fr"), TranslationGroupID: group},
+		{ID: 22, SiteID: testSite.ID, Title: "Über uns", Url: "/uber-uns", PageIdentifier: "about-de", Locale: in("de"), TranslationGroupID: group},
+		{ID: 23, SiteID: testSite.ID, Title: "Contact", Url: "/contact", PageIdentifier: "contact"},
+		{ID: 24, SiteID: testSite.ID, Title: "Kontakt", Url: "/kontakt", PageIdentifier: "contact-de"},
+		{ID: 25, SiteID: otherSite.ID, Title: "Elsewhere", Url: "/about", PageIdentifier: "about"},
+	} {
+		page.AuthorID = 7
+		page.PageAuthor = "frog"
+		store.addPage(page, nil)
+	}
+}
+
+func TestListPageTranslationsHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/21/translations",
+			setup:  seedTranslations,
+			status: http.StatusOK,
+			golden: "list_page_translations_ok",
+		},
+		{
+			name:   "Untranslated",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/23/translations",
+			setup:  seedTranslations,
+			status: http.StatusOK,
+			golden: "list_page_translations_untranslated",
+		},
+		{
+			name:   "OtherSite",
+			method: http.MethodGet,
+			path:   "/api/v1/pages/25/translations",
+			setup:  seedTranslations,
+			status: http.StatusNotFound,
+			golden: "list_page_translations_other_site",
+		},
+	})
+}
+
+func TestPutPageTranslationHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "NewGroup",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/23/translations/de-at",
+			body:   map[string]any{"page_id": 24},
+			setup:  seedTranslations,
+			status: http.StatusOK,
+			golden: "put_page_translation_new_group",
+		},
+		{
+			name:   "ExistingGroup",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/20/translations/fr-CA",
+			body:   map[string]any{"page_id": 23},
+			setup:  seedTranslations,
+			status: http.StatusOK,
+			golden: "put_page_translation_existing_group",
+		},
+		{
+			name:   "LocaleTaken",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/20/translations/fr",
+			body:   map[string]any{"page_id": 23},
+			setup:  seedTranslations,
+			status: http.StatusConflict,
+			golden: "put_page_translation_locale_taken",
+		},
+		{
+			name:   "InvalidLocale",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/20/translations/klingon",
+			body:   map[string]any{"page_id": 23},
+			setup:  seedTranslations,
+			status: http.StatusBadRequest,
+			golden: "put_page_translation_invalid_locale",
+		},
+		{
+			name:   "OwnTranslation",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/23/translations/de",
+			body:   map[string]any{"page_id": 23},
+			setup:  seedTranslations,
+			status: http.StatusBadRequest,
+			golden: "put_page_translation_own_translation",
+		},
+		{
+			name:   "TranslationOnOtherSite",
+			method: http.MethodPut,
+			path:   "/api/v1/pages/20/translations/it",
+			body:   map[string]any{"page_id": 25},
+			setup:  seedTranslations,
+			status: http.StatusNotFound,
+			golden: "put_page_translation_other_site",
+		},
+	})
+}
+
+func TestDeletePageTranslationHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodDelete,
+			path:   "/api/v1/pages/22/translations",
+			setup:  seedTranslations,
+			status: http.StatusOK,
+			golden: "delete_page_translation_ok",
+		},
+		{
+			name:   "NotFound",
+			method: http.MethodDelete,
+			path:   "/api/v1/pages/42/translations",
+			setup:  seedTranslations,
+			status: http.StatusNotFound,
+			golden: "delete_page_translation_not_found",
+		},
+	})
+}
+
+// seedPostTranslations adds the Spawning post in en, fr and de, the
+// untranslated Tadpoles and Kaulquappen posts on testSite, and a post on
+// otherSite
+func seedPostTranslations(store *fakeStore) {
+	seedAuthor(store)
+	group := sql.NullInt64{Int64: 50, Valid: true}
+	in := func(locale string) sql.NullString { return sql.NullString{String: locale, Valid: true} }
+	for _, post := range []db.Post{
+		{ID: 50, SiteID: testSite.ID, Title: "Spawning", Content: "Eggs.", Url: "/spawning", Locale: in("en"), TranslationGroupID: group},
+		{ID: 51, SiteID: testSite.ID, Title: "Le frai", Content: "Des œufs.", Url: "/le-frai", Locale: in("fr"), TranslationGroupID: group},
+		{ID: 52, SiteID: testSite.ID, Title: "Laichen", Content: "Eier.", Url: "/laichen", Locale: in("de"), TranslationGroupID: group},
+		{ID: 53, SiteID: testSite.ID, Title: "Tadpoles", Content: "Tails.", Url: "/tadpoles"},
+		{ID: 54, SiteID: testSite.ID, Title: "Kaulquappen", Content: "Schwänze.", Url: "/kaulquappen"},
+		{ID: 55, SiteID: otherSite.ID, Title: "Elsewhere", Content: "Away.", Url: "/spawning"},
+	} {
+		post.AuthorID = 7
+		post.PostAuthor = "frog"
+		post.PostMimeType = "text/plain"
+		post.Status = "user"
+		post.CreatedAt, post.UpdatedAt, post.PublishedAt, post.EditedAt = fixedTime, fixedTime, fixedTime, fixedTime
+		store.posts[post.ID] = post
+	}
+}
+
+func TestListPostTranslationsHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/51/translations",
+			setup:  seedPostTranslations,
+			status: http.StatusOK,
+			golden: "list_post_translations_ok",
+		},
+		{
+			name:   "Untranslated",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/53/translations",
+			setup:  seedPostTranslations,
+			status: http.StatusOK,
+			golden: "list_post_translations_untranslated",
+		},
+		{
+			name:   "OtherSite",
+			method: http.MethodGet,
+			path:   "/api/v1/posts/55/translations",
+			setup:  seedPostTranslations,
+			status: http.StatusNotFound,
+			golden: "list_post_translations_other_site",
+		},
+	})
+}
+
+func TestPutPostTranslationHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "NewGroup",
+			method: http.MethodPut,
+			path:   "/api/v1/posts/53/translations/de",
+			body:   map[string]any{"post_id": 54},
+			setup:  seedPostTranslations,
+			status: http.StatusOK,
+			golden: "put_post_translation_new_group",
+		},
+		{
+			name:   "ExistingGroup",
+			method: http.MethodPut,
+			path:   "/api/v1/posts/50/translations/fr-CA",
+			body:   map[string]any{"post_id": 53},
+			setup:  seedPostTranslations,
+			status: http.StatusOK,
+			golden: "put_post_translation_existing_group",
+		},
+		{
+			name:   "LocaleTaken",
+			method: http.MethodPut,
+			path:   "/api/v1/posts/50/translations/fr",
+			body:   map[string]any{"post_id": 53},
+			setup:  seedPostTranslations,
+			status: http.StatusConflict,
+			golden: "put_post_translation_locale_taken",
+		},
+		{
+			name:   "InvalidLocale",
+			method: http.MethodPut,
+			path:   "/api/v1/posts/50/translations/klingon",
+			body:   map[string]any{"post_id": 53},
+			setup:  seedPostTranslations,
+			status: http.StatusBadRequest,
+			golden: "put_post_translation_invalid_locale",
+		},
+		{
+			name:   "TranslationOnOtherSite",
+			method: http.MethodPut,
+			path:   "/api/v1/posts/50/translations/it",
+			body:   map[string]any{"post_id": 55},
+			setup:  seedPostTranslations,
+			status: http.StatusNotFound,
+			golden: "put_post_translation_other_site",
+		},
+	})
+}
+
+func TestDeletePostTranslationHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodDelete,
+			path:   "/api/v1/posts/52/translations",
+			setup:  seedPostTranslations,
+			status: http.StatusOK,
+			golden: "delete_post_translation_ok",
+		},
+		{
+			name:   "NotFound",
+			method: http.MethodDelete,
+			path:   "/api/v1/posts/55/translations",
+			setup:  seedPostTranslations,
+			status: http.StatusNotFound,
+			golden: "delete_post_translation_not_found",
+		},
+	})
+}
@@ -2,79 +2,119 @@ package handler
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/middleware"
+	"github.com/reflection/frog_blossom_db/internal/validation"
 )
 
 // CreateUsers handler
 
+// Length limits follow the varchar(255) columns of the users table
 type createUsersRequest struct {
-	Username    string `json:"username" binding:"required"`
-	Email       string `json:"email" binding:"required"`
-	Password    string `json:"password" binding:"required"`
-	Role        string `json:"role" binding:"required"`
-	FirstName   string `json:"first_name" binding:"required"`
-	LastName    string `json:"last_name" binding:"required"`
-	UserUrl     string `json:"user_url" binding:"required"`
+	Username    string `json:"username" binding:"required,max=255"`
+	Email       string `json:"email" binding:"required,email,max=255"`
+	Password    string `json:"password" binding:"required,max=255"`
+	FirstName   string `json:"first_name" binding:"required,max=255"`
+	LastName    string `json:"last_name" binding:"required,max=255"`
+	UserUrl     string `json:"user_url" binding:"required,url"`
 	Description string `json:"description" binding:"required"`
+	// Role defaults to user. Only admins can create admins.
+	Role string `json:"role" binding:"omitempty,enum=access"`
+	// SiteRole is the role on the site the user is created through. Only
+	// admins can give another role than editor.
+	SiteRole string `json:"site_role" binding:"omitempty,enum=site_role"`
 }
 
-func CreateUsersHandler(store *db.Store) gin.HandlerFunc {
+// CreateUsersHandler signs a user up to the site. It is open to anyone, so
+// self-signups always become editors and users, see middleware.IdentifyAdmin.
+func CreateUsersHandler(store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
 		var req createUsersRequest
 		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			ctx.Error(validation.FromBinding(err))
+			return
+		}
+
+		role := db.SiteRoleEditor
+		if req.SiteRole != "" {
+			role = db.SiteRole(req.SiteRole)
+		}
+		if role != db.SiteRoleEditor && !middleware.IsAdmin(ctx) {
+			ctx.Error(apperr.Forbidden("admin token required to give a site role other than editor", nil))
+			return
+		}
+
+		if req.Role == "" {
+			req.Role = "user"
+		}
+		if req.Role != "user" && !middleware.IsAdmin(ctx) {
+			ctx.Error(apperr.Forbidden("admin token required to create an admin", nil))
 			return
 		}
 
-		args := db.CreateUsersParams{
-			Username:    req.Username,
-			Email:       req.Email,
-			Password:    req.Password,
-			Role:        req.Role,
-			FirstName:   req.FirstName,
-			LastName:    req.LastName,
-			UserUrl:     sql.NullString{String: req.UserUrl, Valid: true},
-			Description: sql.NullString{String: req.Description, Valid: true},
+		args := db.CreateSiteUserTxParams{
+			SiteID: site.ID,
+			User: db.CreateUsersParams{
+				Username:    req.Username,
+				Email:       req.Email,
+				Password:    req.Password,
+				Role:        req.Role,
+				FirstName:   req.FirstName,
+				LastName:    req.LastName,
+				UserUrl:     sql.NullString{String: req.UserUrl, Valid: true},
+				Description: sql.NullString{String: req.Description, Valid: true},
+			},
+			Role: role,
 		}
 
-		user, err := store.CreateUsers(ctx, args)
+		result, err := store.CreateSiteUserTx(ctx, args)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			ctx.Error(err)
 			return
 		}
-		ctx.JSON(http.StatusOK, user)
+		ctx.JSON(http.StatusOK, result.User)
 	}
 }
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
-}
-
 type getUsersRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
-func GetUsersHandler(store *db.Store) gin.HandlerFunc {
+func GetUsersHandler(store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
+		site, ok := requireSite(ctx)
+		if !ok {
+			return
+		}
+
 		var req getUsersRequest
 		if err := ctx.ShouldBindUri(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			ctx.Error(validation.FromBinding(err))
 			return
 		}
 
-		user, err := store.GetUsers(ctx, req.ID)
+		// users of other sites are not found
+		user, err := store.GetSiteUser(ctx, db.GetSiteUserParams{SiteID: site.ID, ID: req.ID})
 		if err != nil {
-			if err == sql.ErrNoRows {
-				ctx.JSON(http.StatusNotFound, errorResponse(err))
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.Error(apperr.NotFound("user not found", err))
 				return
 			}
 
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			ctx.Error(err)
+			return
 		}
 		ctx.JSON(http.StatusOK, user)
 	}
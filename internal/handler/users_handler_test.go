@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/health"
+	"github.com/reflection/frog_blossom_db/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func validCreateUsersRequest() createUsersRequest {
+	return createUsersRequest{
+		Username:    "frog",
+		Email:       "frog@example.com",
+		Password:    "secret-password",
+		Role:        "user",
+		FirstName:   "Fro",
+		LastName:    "Blossom",
+		UserUrl:     "https://example.com/frog",
+		Description: "Writes about ponds.",
+	}
+}
+
+func TestCreateUsersHandler(t *testing.T) {
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodPost,
+			path:   "/api/v1/users",
+			body:   validCreateUsersRequest(),
+			status: http.StatusOK,
+			golden: "create_users_ok",
+		},
+		{
+			name:   "SelfSignupAsOwner",
+			method: http.MethodPost,
+			path:   "/api/v1/users",
+			body: func() createUsersRequest {
+				req := validCreateUsersRequest()
+				req.SiteRole = string(db.SiteRoleOwner)
+				return req
+			}(),
+			status: http.StatusForbidden,
+			golden: "create_users_owner_forbidden",
+		},
+		{
+			name:   "MissingFields",
+			method: http.MethodPost,
+			path:   "/api/v1/users",
+			body:   map[string]string{"username": "frog"},
+			status: http.StatusBadRequest,
+			golden: "create_users_missing_fields",
+		},
+		{
+			name:   "InvalidFields",
+			method: http.MethodPost,
+			path:   "/api/v1/users",
+			body: func() createUsersRequest {
+				req := validCreateUsersRequest()
+				req.Email = "not-an-email"
+				req.Username = strings.Repeat("f", 256)
+				req.Role = "superuser"
+				req.UserUrl = "frog blossom"
+				return req
+			}(),
+			status: http.StatusBadRequest,
+			golden: "create_users_invalid_fields",
+		},
+		{
+			name:   "WrongFieldType",
+			method: http.MethodPost,
+			path:   "/api/v1/users",
+			body:   map[string]any{"username": 42},
+			status: http.StatusBadRequest,
+			golden: "create_users_wrong_type",
+		},
+		{
+			name:   "DuplicateUsername",
+			method: http.MethodPost,
+			path:   "/api/v1/users",
+			body:   validCreateUsersRequest(),
+			setup: func(store *fakeStore) {
+				store.err = &pq.Error{Code: "23505", Constraint: "users_username_key"}
+			},
+			status: http.StatusConflict,
+			golden: "create_users_conflict",
+		},
+		{
+			name:   "StoreError",
+			method: http.MethodPost,
+			path:   "/api/v1/users",
+			body:   validCreateUsersRequest(),
+			setup: func(store *fakeStore) {
+				store.err = sql.ErrConnDone
+			},
+			status: http.StatusInternalServerError,
+			golden: "create_users_store_error",
+		},
+	})
+}
+
+func TestGetUsersHandler(t *testing.T) {
+	seedUser := func(store *fakeStore) {
+		store.addUser(db.User{
+			ID:        7,
+			Username:  "frog",
+			Email:     "frog@example.com",
+			Password:  "secret-password",
+			Role:      "user",
+			FirstName: "Fro",
+			LastName:  "Blossom",
+			UserUrl:   sql.NullString{String: "https://example.com/frog", Valid: true},
+			IsDeleted: sql.NullBool{Bool: false, Valid: true},
+		})
+	}
+
+	runCases(t, []apiCase{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			path:   "/api/v1/users/7",
+			setup:  seedUser,
+			status: http.StatusOK,
+			golden: "get_users_ok",
+		},
+		{
+			name:   "NotAMember",
+			method: http.MethodGet,
+			path:   "/api/v1/users/7",
+			host:   otherSite.Domain,
+			setup:  seedUser,
+			status: http.StatusNotFound,
+			golden: "get_users_not_a_member",
+		},
+		{
+			name:   "InvalidID",
+			method: http.MethodGet,
+			path:   "/api/v1/users/0",
+			status: http.StatusBadRequest,
+			golden: "get_users_invalid_id",
+		},
+		{
+			name:   "NotFound",
+			method: http.MethodGet,
+			path:   "/api/v1/users/42",
+			status: http.StatusNotFound,
+			golden: "get_users_not_found",
+		},
+		{
+			name:   "StoreError",
+			method: http.MethodGet,
+			path:   "/api/v1/users/7",
+			setup: func(store *fakeStore) {
+				store.err = errors.New("connection reset by peer")
+			},
+			status: http.StatusInternalServerError,
+			golden: "get_users_store_error",
+		},
+	})
+}
+
+func TestCreateUsersRoles(t *testing.T) {
+	testCases := []struct {
+		name     string
+		userRole string
+		siteRole db.SiteRole
+		header   http.Header
+		status   int
+		wantUser string
+		role     db.SiteRole
+	}{
+		{name: "SelfSignup", status: http.StatusOK, wantUser: "user", role: db.SiteRoleEditor},
+		{name: "SelfSignupAsUser", userRole: "user", status: http.StatusOK, wantUser: "user", role: db.SiteRoleEditor},
+		{name: "SelfSignupAsViewer", siteRole: db.SiteRoleViewer, status: http.StatusForbidden},
+		{name: "SelfSignupAsAdmin", userRole: "admin", status: http.StatusForbidden},
+		{name: "WrongToken", siteRole: db.SiteRoleOwner, header: http.Header{"Authorization": {"Bearer not-the-token"}}, status: http.StatusForbidden},
+		{name: "WrongTokenAsAdmin", userRole: "admin", header: http.Header{"Authorization": {"Bearer not-the-token"}}, status: http.StatusForbidden},
+		{name: "Admin", siteRole: db.SiteRoleOwner, header: adminHeader, status: http.StatusOK, wantUser: "user", role: db.SiteRoleOwner},
+		{name: "AdminCreatesAdmin", userRole: "admin", header: adminHeader, status: http.StatusOK, wantUser: "admin", role: db.SiteRoleEditor},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newFakeStore()
+			router := newTestRouter(store, storage.NewLocal(t.TempDir()), health.NewChecker(nil))
+
+			body := validCreateUsersRequest()
+			body.Role = tc.userRole
+			body.SiteRole = string(tc.siteRole)
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users", bytes.NewReader(data))
+			req.Host = testSite.Domain
+			req.Header.Set("Content-Type", "application/json")
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.status, recorder.Code)
+			if tc.status != http.StatusOK {
+				require.Empty(t, store.users)
+				return
+			}
+			var user db.User
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &user))
+			require.Equal(t, tc.wantUser, store.users[user.ID].Role)
+			require.Equal(t, tc.role, store.members[testSite.ID][user.ID].Role)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Package health runs the readiness checks behind /readyz and gathers the
+// diagnostics shown on /debug/info.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reflection/frog_blossom_db/db/migration"
+	"github.com/reflection/frog_blossom_db/internal/buildinfo"
+	"github.com/reflection/frog_blossom_db/internal/lifecycle"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// checkTimeout bounds a single check so a hung dependency can't hang /readyz
+const checkTimeout = 2 * time.Second
+
+// Check reports whether a dependency is usable; nil means healthy
+type Check func(ctx context.Context) error
+
+// Checker holds the readiness checks of the process
+type Checker struct {
+	db      *sql.DB
+	started time.Time
+
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// NewChecker creates a checker for the given database pool.
+// Checks are registered with Add.
+func NewChecker(conn *sql.DB) *Checker {
+	return &Checker{
+		db:      conn,
+		started: time.Now(),
+		checks:  map[string]Check{},
+	}
+}
+
+// Add registers a named readiness check
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the outcome of every check; Status is ok only if all checks are
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready runs every check concurrently
+func (c *Checker) Ready(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			result := Result{Status: StatusOK}
+			if err := check(ctx); err != nil {
+				result = Result{Status: StatusUnavailable, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// DatabaseCheck pings the pool; sql.Open alone never connects
+func DatabaseCheck(conn *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return conn.PingContext(ctx)
+	}
+}
+
+// MigrationsCheck fails while the database is behind the migrations
+// embedded in the binary or a migration was left dirty
+func MigrationsCheck(conn *sql.DB) Check {
+	return func(ctx context.Context) error {
+		latest, err := migration.Latest()
+		if err != nil {
+			return err
+		}
+		version, dirty, err := migration.Version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if version < latest {
+			return fmt.Errorf("%d pending migrations (at %d, want %d)", latest-version, version, latest)
+		}
+		return nil
+	}
+}
+
+// WorkersCheck fails when a background worker is not running
+func WorkersCheck(workers func() []lifecycle.WorkerStatus) Check {
+	return func(ctx context.Context) error {
+		var down []string
+		for _, w := range workers() {
+			if w.State != lifecycle.WorkerRunning {
+				down = append(down, fmt.Sprintf("%s is %s", w.Name, w.State))
+			}
+		}
+		if len(down) > 0 {
+			sort.Strings(down)
+			return fmt.Errorf("%s", strings.Join(down, ", "))
+		}
+		return nil
+	}
+}
+
+// PoolStats mirrors sql.DBStats with JSON names
+type PoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+// Info is the payload of /debug/info
+type Info struct {
+	Build            buildinfo.Info `json:"build"`
+	MigrationVersion uint           `json:"migration_version"`
+	MigrationDirty   bool           `json:"migration_dirty"`
+	MigrationError   string         `json:"migration_error,omitempty"`
+	Pool             PoolStats      `json:"pool"`
+	StartedAt        time.Time      `json:"started_at"`
+	Uptime           string         `json:"uptime"`
+}
+
+// Info gathers build, migration, pool and uptime diagnostics
+func (c *Checker) Info(ctx context.Context) Info {
+	info := Info{
+		Build:     buildinfo.Read(),
+		StartedAt: c.started.UTC(),
+		Uptime:    time.Since(c.started).Round(time.Second).String(),
+	}
+
+	version, dirty, err := migration.Version(ctx, c.db)
+	info.MigrationVersion = version
+	info.MigrationDirty = dirty
+	if err != nil {
+		info.MigrationError = err.Error()
+	}
+
+	stats := c.db.Stats()
+	info.Pool = PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+
+	return info
+}
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/reflection/frog_blossom_db/internal/lifecycle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReady(t *testing.T) {
+	checker := NewChecker(nil)
+	checker.Add("database", func(ctx context.Context) error { return nil })
+	checker.Add("cache", func(ctx context.Context) error { return errors.New("cache unreachable") })
+
+	report := checker.Ready(context.Background())
+	require.Equal(t, StatusUnavailable, report.Status)
+	require.Equal(t, Result{Status: StatusOK}, report.Checks["database"])
+	require.Equal(t, Result{Status: StatusUnavailable, Error: "cache unreachable"}, report.Checks["cache"])
+}
+
+func TestReadyTimesOutHungCheck(t *testing.T) {
+	checker := NewChecker(nil)
+	checker.Add("hung", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report := checker.Ready(ctx)
+	require.Equal(t, StatusUnavailable, report.Status)
+}
+
+func TestWorkersCheck(t *testing.T) {
+	statuses := []lifecycle.WorkerStatus{
+		{Name: "mailer", State: lifecycle.WorkerRunning},
+	}
+	check := WorkersCheck(func() []lifecycle.WorkerStatus { return statuses })
+	require.NoError(t, check(context.Background()))
+
+	statuses = append(statuses,
+		lifecycle.WorkerStatus{Name: "indexer", State: lifecycle.WorkerFailed, Error: "boom"},
+		lifecycle.WorkerStatus{Name: "cleanup", State: lifecycle.WorkerStopped},
+	)
+	require.EqualError(t, check(context.Background()), "cleanup is stopped, indexer is failed")
+}
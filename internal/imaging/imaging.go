@@ -0,0 +1,155 @@
+// Package imaging renders variants of uploaded images: resized to a width,
+// cropped to a size or converted to another format. Variants are rendered
+// on first request and kept in storage next to the original; their URLs
+// are signed so clients can only ask for the variants the API handed out.
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"regexp"
+	"strconv"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxDimension bounds the width and height of a variant
+	MaxDimension = 4096
+	// MaxPixels bounds the size of the images variants are rendered from,
+	// so a small file can't make the server decode a huge image
+	MaxPixels = 50_000_000
+)
+
+// ErrNotImage is returned for media that can't be decoded as an image
+var ErrNotImage = errors.New("imaging: media is not a supported image")
+
+// Formats variants can be encoded in, by the name a variant uses for them
+var contentTypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+}
+
+// Variant describes how an image is rendered. With only Width it is
+// scaled down to that width; with Height too it is cropped around the
+// center to exactly that size.
+type Variant struct {
+	Width  int
+	Height int
+	Format string
+}
+
+// OpenGraph is the variant social networks show for a link, see
+// https://ogp.me/#structured
+var OpenGraph = Variant{Width: 1200, Height: 630, Format: "jpeg"}
+
+var variantName = regexp.MustCompile(`^([1-9][0-9]*)(?:w|x([1-9][0-9]*))\.([a-z]+)$`)
+
+// ParseVariant parses the name of a variant: 640w.jpeg is 640 pixels wide,
+// 1200x630.png is cropped to 1200 by 630 pixels
+func ParseVariant(name string) (Variant, error) {
+	match := variantName.FindStringSubmatch(name)
+	if match == nil {
+		return Variant{}, fmt.Errorf("variant %q is not of the form 640w.jpeg or 1200x630.jpeg", name)
+	}
+
+	var v Variant
+	v.Width, _ = strconv.Atoi(match[1])
+	if match[2] != "" {
+		v.Height, _ = strconv.Atoi(match[2])
+	}
+	v.Format = match[3]
+	return v, v.Validate()
+}
+
+// Validate checks the size and format of the variant
+func (v Variant) Validate() error {
+	if v.Width < 1 || v.Width > MaxDimension || v.Height < 0 || v.Height > MaxDimension {
+		return fmt.Errorf("variant sizes must be between 1 and %d", MaxDimension)
+	}
+	if _, ok := contentTypes[v.Format]; !ok {
+		return fmt.Errorf("variant format must be jpeg or png, got %q", v.Format)
+	}
+	return nil
+}
+
+// String is the name of the variant, see ParseVariant
+func (v Variant) String() string {
+	if v.Height > 0 {
+		return fmt.Sprintf("%dx%d.%s", v.Width, v.Height, v.Format)
+	}
+	return fmt.Sprintf("%dw.%s", v.Width, v.Format)
+}
+
+// ContentType is the media type of the encoded variant
+func (v Variant) ContentType() string {
+	return contentTypes[v.Format]
+}
+
+// Render decodes the image in r and writes the variant of it to w.
+// Width-only variants are never scaled up.
+func Render(r io.ReadSeeker, v Variant, w io.Writer) error {
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+	if config.Width*config.Height > MaxPixels {
+		return fmt.Errorf("%w: %dx%d is too large to render", ErrNotImage, config.Width, config.Height)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("imaging: %w", err)
+	}
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+
+	from := src.Bounds()
+	size := image.Pt(v.Width, v.Height)
+	if v.Height == 0 {
+		size.X = min(v.Width, from.Dx())
+		size.Y = max(1, (from.Dy()*size.X+from.Dx()/2)/from.Dx())
+	} else {
+		from = cropTo(from, size)
+	}
+
+	dst := image.NewRGBA(image.Rectangle{Max: size})
+	op := draw.Src
+	if v.Format == "jpeg" {
+		// JPEG has no transparency; show it as white rather than black
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		op = draw.Over
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, from, op, nil)
+
+	switch v.Format {
+	case "jpeg":
+		err = jpeg.Encode(w, dst, &jpeg.Options{Quality: 85})
+	case "png":
+		err = png.Encode(w, dst)
+	}
+	if err != nil {
+		return fmt.Errorf("imaging: encode %s: %w", v, err)
+	}
+	return nil
+}
+
+// cropTo returns the largest part of bounds, centered, with the aspect
+// ratio of size
+func cropTo(bounds image.Rectangle, size image.Point) image.Rectangle {
+	w, h := bounds.Dx(), bounds.Dy()
+	if w*size.Y > h*size.X {
+		w = (h*size.X + size.Y/2) / size.Y
+	} else {
+		h = (w*size.Y + size.X/2) / size.X
+	}
+	origin := bounds.Min.Add(image.Pt((bounds.Dx()-w)/2, (bounds.Dy()-h)/2))
+	return image.Rectangle{Min: origin, Max: origin.Add(image.Pt(w, h))}
+}
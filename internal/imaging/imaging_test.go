@@ -0,0 +1,96 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testImage is a w by h PNG, red on the left half and blue on the right
+func testImage(t *testing.T, w, h int) *bytes.Reader {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestParseVariant(t *testing.T) {
+	for _, name := range []string{"640w.jpeg", "1200x630.jpeg", "1w.png", "4096x4096.png"} {
+		v, err := ParseVariant(name)
+		require.NoError(t, err, name)
+		require.Equal(t, name, v.String())
+	}
+
+	v, err := ParseVariant("1200x630.png")
+	require.NoError(t, err)
+	require.Equal(t, Variant{Width: 1200, Height: 630, Format: "png"}, v)
+	require.Equal(t, "image/png", v.ContentType())
+
+	for _, name := range []string{"", "640", "640w", "0640w.jpeg", "0w.jpeg", "4097w.jpeg", "640x0.jpeg", "640w.gif", "640W.JPEG", "640w.jpeg/x"} {
+		_, err := ParseVariant(name)
+		require.Error(t, err, name)
+	}
+}
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name    string
+		variant Variant
+		size    image.Point
+	}{
+		{name: "Width", variant: Variant{Width: 100, Format: "png"}, size: image.Pt(100, 50)},
+		{name: "NoUpscale", variant: Variant{Width: 800, Format: "png"}, size: image.Pt(400, 200)},
+		{name: "Crop", variant: Variant{Width: 100, Height: 100, Format: "jpeg"}, size: image.Pt(100, 100)},
+		{name: "CropUp", variant: OpenGraph, size: image.Pt(1200, 630)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var out bytes.Buffer
+
+			// Act
+			err := Render(testImage(t, 400, 200), tc.variant, &out)
+
+			// Assert
+			require.NoError(t, err)
+			img, format, err := image.Decode(&out)
+			require.NoError(t, err)
+			require.Equal(t, tc.variant.Format, format)
+			require.Equal(t, tc.size, img.Bounds().Size())
+		})
+	}
+}
+
+func TestRenderCropsAroundCenter(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Render(testImage(t, 400, 100), Variant{Width: 10, Height: 10, Format: "png"}, &out))
+
+	img, err := png.Decode(&out)
+	require.NoError(t, err)
+	r, _, _, _ := img.At(1, 5).RGBA()
+	_, _, b, _ := img.At(8, 5).RGBA()
+	require.Greater(t, r, uint32(0xf000), "left of the crop is red")
+	require.Greater(t, b, uint32(0xf000), "right of the crop is blue")
+}
+
+func TestRenderRejectsNonImages(t *testing.T) {
+	err := Render(strings.NewReader("%PDF-1.7"), OpenGraph, &bytes.Buffer{})
+	require.ErrorIs(t, err, ErrNotImage)
+}
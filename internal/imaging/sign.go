@@ -0,0 +1,72 @@
+package imaging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// DefaultWidths are the widths Srcset offers when none are given
+var DefaultWidths = []int{320, 640, 960, 1280, 1920}
+
+// Signer signs and checks the URLs of variants
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key string) *Signer {
+	return &Signer{key: []byte(key)}
+}
+
+// Sign returns the signature of the variant of a site's media item
+func (s *Signer) Sign(siteID, mediaID int64, v Variant) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%d/%d/%s", siteID, mediaID, v)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Verify reports whether signature was made by Sign for the variant
+func (s *Signer) Verify(siteID, mediaID int64, v Variant, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(s.Sign(siteID, mediaID, v)))
+}
+
+// URL is the path the variant of item is served at, relative to the site
+func (s *Signer) URL(item db.Media, v Variant) string {
+	return fmt.Sprintf("/api/v1/media/%d/variants/%s?s=%s", item.ID, v, s.Sign(item.SiteID, item.ID, v))
+}
+
+// Srcset lists variants of item in format for the srcset attribute of an
+// img element, e.g. "/…/320w.jpeg?s=… 320w, /…/640w.jpeg?s=… 640w".
+// Widths beyond the image's own are left out in favour of its own width.
+func (s *Signer) Srcset(item db.Media, widths []int, format string) string {
+	if len(widths) == 0 {
+		widths = DefaultWidths
+	}
+	widths = slices.Clone(widths)
+	slices.Sort(widths)
+
+	var candidates []string
+	add := func(width int) {
+		candidates = append(candidates, fmt.Sprintf("%s %dw", s.URL(item, Variant{Width: width, Format: format}), width))
+	}
+	largest := 0
+	for _, width := range widths {
+		if width < 1 || width > MaxDimension || width <= largest {
+			continue
+		}
+		if item.Width.Valid && width >= int(item.Width.Int32) {
+			break
+		}
+		add(width)
+		largest = width
+	}
+	if item.Width.Valid && int(item.Width.Int32) > largest && int(item.Width.Int32) <= MaxDimension {
+		add(int(item.Width.Int32))
+	}
+	return strings.Join(candidates, ", ")
+}
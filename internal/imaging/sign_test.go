@@ -0,0 +1,46 @@
+package imaging
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner("0123456789abcdef0123456789abcdef")
+	v := Variant{Width: 640, Format: "jpeg"}
+	signature := signer.Sign(1, 30, v)
+
+	require.True(t, signer.Verify(1, 30, v, signature))
+	require.False(t, signer.Verify(2, 30, v, signature), "another site")
+	require.False(t, signer.Verify(1, 31, v, signature), "another media item")
+	require.False(t, signer.Verify(1, 30, Variant{Width: 641, Format: "jpeg"}, signature), "another variant")
+	require.False(t, signer.Verify(1, 30, v, ""))
+	require.False(t, NewSigner("another key of at least 32 bytes.").Verify(1, 30, v, signature))
+
+	require.Equal(t, "/api/v1/media/30/variants/640w.jpeg?s="+signature, signer.URL(db.Media{ID: 30, SiteID: 1}, v))
+}
+
+func TestSrcset(t *testing.T) {
+	signer := NewSigner("0123456789abcdef0123456789abcdef")
+	widths := func(srcset string) []string {
+		var widths []string
+		for _, candidate := range strings.Split(srcset, ", ") {
+			widths = append(widths, candidate[strings.LastIndexByte(candidate, ' ')+1:])
+		}
+		return widths
+	}
+
+	item := db.Media{ID: 30, SiteID: 1, Width: sql.NullInt32{Int32: 1000, Valid: true}}
+	require.Equal(t, []string{"320w", "640w", "960w", "1000w"}, widths(signer.Srcset(item, nil, "jpeg")))
+	require.Equal(t, []string{"200w", "500w", "1000w"}, widths(signer.Srcset(item, []int{500, 200, 1000, 2000}, "png")))
+
+	srcset := signer.Srcset(item, []int{320}, "png")
+	require.True(t, strings.HasPrefix(srcset, signer.URL(item, Variant{Width: 320, Format: "png"})+" 320w, "))
+
+	// without a known width every width is offered
+	require.Equal(t, []string{"320w", "640w"}, widths(signer.Srcset(db.Media{ID: 30, SiteID: 1}, []int{640, 320}, "jpeg")))
+}
@@ -0,0 +1,95 @@
+package imaging
+
+import (
+	"context"
+	"log/slog"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// Store wraps a Store so that meta written through it with a
+// meta_og_image_id has the OpenGraph variant of that media rendered right
+// away, before a crawler asks for it. A failed render is logged and leaves
+// the write as it is; the variant is then rendered on first request.
+func Store(next db.Store, variants *Variants) db.Store {
+	return &openGraphStore{Store: next, variants: variants}
+}
+
+type openGraphStore struct {
+	db.Store
+	variants *Variants
+}
+
+// render prepares the OpenGraph variant for every meta once the write
+// succeeded
+func (s *openGraphStore) render(ctx context.Context, err error, metas ...db.Meta) {
+	if err != nil {
+		return
+	}
+	for _, meta := range metas {
+		if !meta.MetaOgImageID.Valid {
+			continue
+		}
+		if err := s.prepare(ctx, meta); err != nil {
+			slog.WarnContext(ctx, "open graph image not rendered",
+				slog.Int64("meta_id", meta.ID),
+				slog.Int64("media_id", meta.MetaOgImageID.Int64),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+func (s *openGraphStore) prepare(ctx context.Context, meta db.Meta) error {
+	item, err := s.Store.GetMedia(ctx, db.GetMediaParams{SiteID: meta.SiteID, ID: meta.MetaOgImageID.Int64})
+	if err != nil {
+		return err
+	}
+	file, err := s.variants.Open(ctx, item, OpenGraph)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func (s *openGraphStore) CreateMeta(ctx context.Context, arg db.CreateMetaParams) (db.Meta, error) {
+	meta, err := s.Store.CreateMeta(ctx, arg)
+	s.render(ctx, err, meta)
+	return meta, err
+}
+
+func (s *openGraphStore) UpdateMeta(ctx context.Context, arg db.UpdateMetaParams) (db.Meta, error) {
+	meta, err := s.Store.UpdateMeta(ctx, arg)
+	s.render(ctx, err, meta)
+	return meta, err
+}
+
+func (s *openGraphStore) InitSetupConfigTx(ctx context.Context, args db.InitSetupConfigTxParams) (db.InitSetupConfigTxResult, error) {
+	result, err := s.Store.InitSetupConfigTx(ctx, args)
+	s.render(ctx, err, result.Metas...)
+	return result, err
+}
+
+func (s *openGraphStore) CreatePostsTx(ctx context.Context, args db.CreateContentTxParams) (db.CreateContentTxResult, error) {
+	result, err := s.Store.CreatePostsTx(ctx, args)
+	s.render(ctx, err, result.Metas...)
+	return result, err
+}
+
+func (s *openGraphStore) CreatePageTx(ctx context.Context, args db.CreateContentTxParams) (db.CreateContentTxResult, error) {
+	result, err := s.Store.CreatePageTx(ctx, args)
+	s.render(ctx, err, result.Metas...)
+	return result, err
+}
+
+func (s *openGraphStore) UpdatePostsTx(ctx context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	result, err := s.Store.UpdatePostsTx(ctx, args)
+	s.render(ctx, err, result.Metas...)
+	return result, err
+}
+
+func (s *openGraphStore) UpdatePageTx(ctx context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	result, err := s.Store.UpdatePageTx(ctx, args)
+	s.render(ctx, err, result.Metas...)
+	return result, err
+}
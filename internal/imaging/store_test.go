@@ -0,0 +1,147 @@
+package imaging
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"io"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore holds media and writes meta as given
+type fakeStore struct {
+	db.Store
+	media map[int64]db.Media
+	err   error
+}
+
+func (f *fakeStore) GetMedia(_ context.Context, arg db.GetMediaParams) (db.Media, error) {
+	item, ok := f.media[arg.ID]
+	if !ok || item.SiteID != arg.SiteID {
+		return db.Media{}, sql.ErrNoRows
+	}
+	return item, nil
+}
+
+func (f *fakeStore) CreateMeta(_ context.Context, arg db.CreateMetaParams) (db.Meta, error) {
+	return db.Meta{ID: 1, SiteID: arg.SiteID, MetaOgImageID: arg.MetaOgImageID}, f.err
+}
+
+func (f *fakeStore) UpdatePageTx(_ context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	var result db.UpdateContentTxResult
+	for _, meta := range args.Metas {
+		result.Metas = append(result.Metas, db.Meta{ID: meta.ID, SiteID: meta.SiteID, MetaOgImageID: meta.MetaOgImageID})
+	}
+	return result, f.err
+}
+
+// newOpenGraphStore has media 5, an image, and 6, a PDF, on site 1
+func newOpenGraphStore(t *testing.T) (*fakeStore, storage.Storage, db.Store) {
+	t.Helper()
+
+	blobs := storage.NewLocal(t.TempDir())
+	require.NoError(t, blobs.Put(context.Background(), "original.png", testImage(t, 400, 200)))
+	require.NoError(t, blobs.Put(context.Background(), "original.pdf", testImage(t, 1, 1)))
+	fake := &fakeStore{media: map[int64]db.Media{
+		5: {ID: 5, SiteID: 1, MimeType: "image/png", Checksum: "aa", StorageKey: "original.png"},
+		6: {ID: 6, SiteID: 1, MimeType: "application/pdf", Checksum: "bb", StorageKey: "original.pdf"},
+	}}
+	return fake, blobs, Store(fake, NewVariants(blobs))
+}
+
+func openGraphSize(t *testing.T, blobs storage.Storage, item db.Media) (image.Point, error) {
+	t.Helper()
+
+	file, err := blobs.Open(context.Background(), Key(item, OpenGraph))
+	if err != nil {
+		return image.Point{}, err
+	}
+	defer file.Close()
+	config, _, err := image.DecodeConfig(file)
+	require.NoError(t, err)
+	return image.Pt(config.Width, config.Height), nil
+}
+
+func TestStoreRendersOpenGraphImage(t *testing.T) {
+	// Arrange
+	fake, blobs, store := newOpenGraphStore(t)
+
+	// Act
+	_, err := store.CreateMeta(context.Background(), db.CreateMetaParams{
+		SiteID:        1,
+		MetaOgImageID: sql.NullInt64{Int64: 5, Valid: true},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	size, err := openGraphSize(t, blobs, fake.media[5])
+	require.NoError(t, err)
+	require.Equal(t, image.Pt(1200, 630), size)
+}
+
+func TestStoreRendersOpenGraphImageOfTx(t *testing.T) {
+	fake, blobs, store := newOpenGraphStore(t)
+
+	_, err := store.UpdatePageTx(context.Background(), db.UpdateContentTxParams{
+		Metas: []db.UpdateMetaParams{
+			{ID: 1, SiteID: 1},
+			{ID: 2, SiteID: 1, MetaOgImageID: sql.NullInt64{Int64: 5, Valid: true}},
+		},
+	})
+	require.NoError(t, err)
+	_, err = openGraphSize(t, blobs, fake.media[5])
+	require.NoError(t, err)
+}
+
+func TestStoreKeepsWritesWithoutOpenGraphImage(t *testing.T) {
+	fake, blobs, store := newOpenGraphStore(t)
+
+	// not an image, and media of another site: the writes still succeed
+	for _, arg := range []db.CreateMetaParams{
+		{SiteID: 1, MetaOgImageID: sql.NullInt64{Int64: 6, Valid: true}},
+		{SiteID: 2, MetaOgImageID: sql.NullInt64{Int64: 5, Valid: true}},
+	} {
+		_, err := store.CreateMeta(context.Background(), arg)
+		require.NoError(t, err)
+	}
+	_, err := openGraphSize(t, blobs, fake.media[6])
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	// a failed write renders nothing
+	fake.err = errors.New("boom")
+	_, err = store.CreateMeta(context.Background(), db.CreateMetaParams{SiteID: 1, MetaOgImageID: sql.NullInt64{Int64: 5, Valid: true}})
+	require.Error(t, err)
+	_, err = openGraphSize(t, blobs, fake.media[5])
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestVariantsOpen(t *testing.T) {
+	blobs := storage.NewLocal(t.TempDir())
+	require.NoError(t, blobs.Put(context.Background(), "original.png", testImage(t, 400, 200)))
+	item := db.Media{ID: 5, SiteID: 1, MimeType: "image/png", Checksum: "aa", StorageKey: "original.png"}
+	variants := NewVariants(blobs)
+
+	file, err := variants.Open(context.Background(), item, Variant{Width: 100, Format: "png"})
+	require.NoError(t, err)
+	first, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	// rendered once, served from storage afterwards
+	require.NoError(t, blobs.Delete(context.Background(), item.StorageKey))
+	file, err = variants.Open(context.Background(), item, Variant{Width: 100, Format: "png"})
+	require.NoError(t, err)
+	again, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, first, again)
+
+	require.NoError(t, variants.Delete(context.Background(), item))
+	_, err = variants.Open(context.Background(), item, Variant{Width: 100, Format: "png"})
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
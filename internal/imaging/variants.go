@@ -0,0 +1,80 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/storage"
+	"golang.org/x/sync/singleflight"
+)
+
+// Variants renders variants of media into storage and serves them from
+// there afterwards
+type Variants struct {
+	blobs storage.Storage
+	group singleflight.Group
+}
+
+func NewVariants(blobs storage.Storage) *Variants {
+	return &Variants{blobs: blobs}
+}
+
+// prefix is where the variants of item are kept. Like the original they
+// are keyed by checksum, of which a site has one media item, and they are
+// deleted with it, see Delete.
+func prefix(item db.Media) string {
+	return fmt.Sprintf("sites/%d/variants/%s", item.SiteID, item.Checksum)
+}
+
+// Key is where the variant of item is kept
+func Key(item db.Media, v Variant) string {
+	return prefix(item) + "/" + v.String()
+}
+
+// Open returns the variant of item, rendering it first if it is not in
+// storage yet. Concurrent requests for a missing variant render it once.
+func (vs *Variants) Open(ctx context.Context, item db.Media, v Variant) (io.ReadSeekCloser, error) {
+	if !strings.HasPrefix(item.MimeType, "image/") {
+		return nil, ErrNotImage
+	}
+
+	key := Key(item, v)
+	file, err := vs.blobs.Open(ctx, key)
+	if !errors.Is(err, storage.ErrNotFound) {
+		return file, err
+	}
+
+	// a client going away must not fail the render for the others waiting
+	renderCtx := context.WithoutCancel(ctx)
+	_, err, _ = vs.group.Do(key, func() (any, error) {
+		return nil, vs.render(renderCtx, item, v, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vs.blobs.Open(ctx, key)
+}
+
+func (vs *Variants) render(ctx context.Context, item db.Media, v Variant, key string) error {
+	original, err := vs.blobs.Open(ctx, item.StorageKey)
+	if err != nil {
+		return fmt.Errorf("open original err: %w", err)
+	}
+	defer original.Close()
+
+	var buf bytes.Buffer
+	if err := Render(original, v, &buf); err != nil {
+		return err
+	}
+	return vs.blobs.Put(ctx, key, &buf)
+}
+
+// Delete removes every variant of item
+func (vs *Variants) Delete(ctx context.Context, item db.Media) error {
+	return vs.blobs.DeleteAll(ctx, prefix(item))
+}
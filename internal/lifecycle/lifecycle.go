@@ -0,0 +1,207 @@
+// Package lifecycle starts the application's components and shuts them down
+// in a fixed order when the process is asked to stop:
+//
+//  1. the HTTP server stops accepting connections and drains in-flight
+//     requests, bounded by the shutdown timeout
+//  2. background workers are cancelled and waited for, bounded by the
+//     shutdown timeout again, so a slow drain doesn't eat their time
+//  3. resources (e.g. the database pool) are closed, last registered first
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Manager owns the HTTP server, background workers and closers of the process
+type Manager struct {
+	shutdownTimeout time.Duration
+
+	server  *http.Server
+	workers []*worker
+	closers []closer
+}
+
+// WorkerState is the state a background worker is in
+type WorkerState string
+
+const (
+	WorkerPending WorkerState = "pending"
+	WorkerRunning WorkerState = "running"
+	WorkerStopped WorkerState = "stopped"
+	WorkerFailed  WorkerState = "failed"
+)
+
+// WorkerStatus is a snapshot of a background worker
+type WorkerStatus struct {
+	Name  string      `json:"name"`
+	State WorkerState `json:"state"`
+	Error string      `json:"error,omitempty"`
+}
+
+type worker struct {
+	name string
+	run  func(ctx context.Context) error
+
+	mu    sync.Mutex
+	state WorkerState
+	err   error
+}
+
+func (w *worker) set(state WorkerState, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.state = state
+	w.err = err
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+// New creates a manager that gives in-flight requests, and then workers,
+// up to shutdownTimeout each to finish once shutdown starts
+func New(shutdownTimeout time.Duration) *Manager {
+	return &Manager{shutdownTimeout: shutdownTimeout}
+}
+
+// Serve registers the HTTP server. It is started by Run and is the first
+// component to be shut down.
+func (m *Manager) Serve(server *http.Server) {
+	m.server = server
+}
+
+// Go registers a background worker. run must return once ctx is cancelled;
+// a worker returning an error before that triggers shutdown.
+func (m *Manager) Go(name string, run func(ctx context.Context) error) {
+	m.workers = append(m.workers, &worker{name: name, run: run, state: WorkerPending})
+}
+
+// Workers reports the state of every registered worker
+func (m *Manager) Workers() []WorkerStatus {
+	statuses := make([]WorkerStatus, 0, len(m.workers))
+	for _, w := range m.workers {
+		w.mu.Lock()
+		status := WorkerStatus{Name: w.name, State: w.state}
+		if w.err != nil {
+			status.Error = w.err.Error()
+		}
+		w.mu.Unlock()
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// OnClose registers a resource to close after the server and workers have
+// stopped. Closers run in reverse registration order.
+func (m *Manager) OnClose(name string, close func() error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Run starts every component and blocks until ctx is done, SIGINT or SIGTERM
+// is received, or a component fails. It then shuts everything down and
+// returns the failure, if any, joined with shutdown errors.
+func (m *Manager) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failures := make(chan error, len(m.workers)+1)
+
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+
+	var workers sync.WaitGroup
+	for _, w := range m.workers {
+		workers.Add(1)
+		w.set(WorkerRunning, nil)
+		go func(w *worker) {
+			defer workers.Done()
+			err := w.run(workerCtx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				w.set(WorkerFailed, err)
+				failures <- fmt.Errorf("worker %s: %w", w.name, err)
+				return
+			}
+			w.set(WorkerStopped, nil)
+		}(w)
+	}
+
+	if m.server != nil {
+		go func() {
+			slog.Info("listening", slog.String("address", m.server.Addr))
+			err := m.server.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				failures <- fmt.Errorf("http server: %w", err)
+			}
+		}()
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	case runErr = <-failures:
+		slog.Error("shutting down", slog.Any("error", runErr))
+	}
+
+	return errors.Join(runErr, m.shutdown(cancelWorkers, &workers))
+}
+
+func (m *Manager) shutdown(cancelWorkers context.CancelFunc, workers *sync.WaitGroup) error {
+	var errs []error
+
+	if m.server != nil {
+		if err := m.drain(); err != nil {
+			errs = append(errs, fmt.Errorf("drain http server: %w", err))
+			m.server.Close()
+		}
+	}
+
+	cancelWorkers()
+	if err := m.wait(workers); err != nil {
+		errs = append(errs, fmt.Errorf("stop workers: %w", err))
+	}
+
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		c := m.closers[i]
+		if err := c.close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", c.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// drain shuts the server down within its own shutdown timeout
+func (m *Manager) drain() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+	return m.server.Shutdown(ctx)
+}
+
+// wait waits for the cancelled workers within its own shutdown timeout
+func (m *Manager) wait(workers *sync.WaitGroup) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,144 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recorder collects the order in which components stop
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestRunShutsDownInOrder(t *testing.T) {
+	var rec recorder
+
+	server := &http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()}
+	server.RegisterOnShutdown(func() { rec.add("server") })
+
+	m := New(time.Second)
+	m.Serve(server)
+	m.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		rec.add("worker")
+		return ctx.Err()
+	})
+	m.OnClose("database", func() error {
+		rec.add("database")
+		return nil
+	})
+	m.OnClose("cache", func() error {
+		rec.add("cache")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	require.NoError(t, m.Run(ctx))
+	require.Equal(t, []string{"server", "worker", "cache", "database"}, rec.events)
+}
+
+func TestRunStopsOnWorkerFailure(t *testing.T) {
+	var closed bool
+	failure := errors.New("queue unreachable")
+
+	m := New(time.Second)
+	m.Go("indexer", func(ctx context.Context) error {
+		return failure
+	})
+	m.OnClose("database", func() error {
+		closed = true
+		return nil
+	})
+
+	err := m.Run(context.Background())
+	require.ErrorIs(t, err, failure)
+	require.True(t, closed)
+	require.Equal(t, []WorkerStatus{
+		{Name: "indexer", State: WorkerFailed, Error: "queue unreachable"},
+	}, m.Workers())
+}
+
+func TestSlowDrainLeavesWorkersTheirTimeout(t *testing.T) {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})}
+
+	m := New(100 * time.Millisecond)
+	m.Serve(server)
+	m.Go("flusher", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond) // flushing takes part of the timeout
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- m.Run(ctx) }()
+	go func() {
+		// retry until the server listens; the request then hangs in the handler
+		for {
+			if resp, err := http.Get("http://" + addr); err == nil {
+				resp.Body.Close()
+				return
+			}
+			select {
+			case <-started:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+	<-started
+
+	// Act
+	cancel()
+	err = <-result
+
+	// Assert
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "drain http server")
+	require.NotContains(t, err.Error(), "stop workers")
+	require.Equal(t, []WorkerStatus{{Name: "flusher", State: WorkerStopped}}, m.Workers())
+}
+
+func TestRunReportsStuckWorker(t *testing.T) {
+	m := New(20 * time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+	m.Go("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := m.Run(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,91 @@
+// Package locale negotiates the locale content is delivered in and builds
+// the fallback chain the store tries in order, e.g. fr-CA, fr, then the
+// site's default language.
+package locale
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// pathTag is what a locale prefix of a URL path looks like. It keeps
+// ordinary path segments that happen to parse as tags, e.g. /blog, out.
+var pathTag = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// wildcard is what language makes of the * range of Accept-Language
+var wildcard = language.Make("mul")
+
+// Parse returns the canonical form of a BCP 47 tag, e.g. fr-CA for fr-ca
+func Parse(s string) (string, error) {
+	tag, err := language.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if tag == language.Und {
+		return "", errors.New("locale is undetermined")
+	}
+	return tag.String(), nil
+}
+
+// FromPath splits a leading locale segment off path: /fr-CA/about is fr-CA
+// and /about. ok is false when the first segment is not a locale.
+func FromPath(path string) (tag, rest string, ok bool) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if !pathTag.MatchString(segment) {
+		return "", path, false
+	}
+	tag, err := Parse(segment)
+	if err != nil {
+		return "", path, false
+	}
+	return tag, "/" + rest, true
+}
+
+// FromAcceptLanguage returns the locales of an Accept-Language header, most
+// preferred first. Wildcards, refused (q=0) and malformed entries are dropped.
+func FromAcceptLanguage(header string) []string {
+	tags, weights, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+	locales := make([]string, 0, len(tags))
+	for i, tag := range tags {
+		if tag == language.Und || tag == wildcard || weights[i] <= 0 {
+			continue
+		}
+		locales = append(locales, tag.String())
+	}
+	return locales
+}
+
+// Chain returns each requested locale followed by its language, then the
+// site default, without repeats: fr-CA and en give fr-CA, fr, en.
+func Chain(requested []string, siteDefault string) []string {
+	var chain []string
+	add := func(locale string) {
+		for _, l := range chain {
+			if l == locale {
+				return
+			}
+		}
+		chain = append(chain, locale)
+	}
+
+	for _, locale := range requested {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			continue
+		}
+		add(tag.String())
+		if base, confidence := tag.Base(); confidence != language.No {
+			add(base.String())
+		}
+	}
+	if siteDefault != "" {
+		add(siteDefault)
+	}
+	return chain
+}
@@ -0,0 +1,56 @@
+package locale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tag, err := Parse("fr-ca")
+	require.NoError(t, err)
+	require.Equal(t, "fr-CA", tag)
+
+	_, err = Parse("not a locale")
+	require.Error(t, err)
+	_, err = Parse("und")
+	require.Error(t, err)
+}
+
+func TestFromPath(t *testing.T) {
+	testCases := []struct {
+		path string
+		tag  string
+		rest string
+		ok   bool
+	}{
+		{path: "/fr-CA/about", tag: "fr-CA", rest: "/about", ok: true},
+		{path: "/de/", tag: "de", rest: "/", ok: true},
+		{path: "/pt-br", tag: "pt-BR", rest: "/", ok: true},
+		{path: "/about", rest: "/about"},
+		{path: "/blog/post", rest: "/blog/post"},
+		{path: "/", rest: "/"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			tag, rest, ok := FromPath(tc.path)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.tag, tag)
+			require.Equal(t, tc.rest, rest)
+		})
+	}
+}
+
+func TestFromAcceptLanguage(t *testing.T) {
+	require.Equal(t, []string{"fr-CA", "fr", "en"}, FromAcceptLanguage("en;q=0.5, fr-CA, fr;q=0.8, *;q=0.1, de;q=0"))
+	require.Empty(t, FromAcceptLanguage(""))
+	require.Empty(t, FromAcceptLanguage("%%%"))
+}
+
+func TestChain(t *testing.T) {
+	require.Equal(t, []string{"fr-CA", "fr", "en"}, Chain([]string{"fr-CA"}, "en"))
+	require.Equal(t, []string{"fr-CA", "fr", "de", "en"}, Chain([]string{"fr-CA", "fr", "de"}, "en"))
+	require.Equal(t, []string{"en-GB", "en"}, Chain([]string{"en-GB"}, "en"))
+	require.Equal(t, []string{"en"}, Chain(nil, "en"))
+}
@@ -0,0 +1,84 @@
+// Package logging configures log/slog for the server and carries the
+// request ID through contexts so every log line of a request can be joined.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Supported output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New builds a logger writing format ("json" or "text") to w. The returned
+// LevelVar controls the minimum level and can be changed at runtime.
+func New(w io.Writer, format, level string) (*slog.Logger, *slog.LevelVar, error) {
+	lvl := new(slog.LevelVar)
+	if err := SetLevel(lvl, level); err != nil {
+		return nil, nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatJSON, "":
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, nil, fmt.Errorf("unknown log format %q, want json or text", format)
+	}
+
+	return slog.New(contextHandler{handler}), lvl, nil
+}
+
+// SetLevel parses level (debug, info, warn, error) into lvl
+func SetLevel(lvl *slog.LevelVar, level string) error {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("unknown log level %q, want debug, info, warn or error", level)
+	}
+	lvl.Set(parsed)
+	return nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID of the context to every record
+// logged with the *Context variants (slog.InfoContext, ...)
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
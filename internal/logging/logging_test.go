@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		lines = append(lines, record)
+	}
+	return lines
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, level, err := New(&buf, FormatText, "warn")
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	require.Empty(t, buf.String())
+
+	require.NoError(t, SetLevel(level, "debug"))
+	logger.Debug("shown")
+	require.Contains(t, buf.String(), "msg=shown")
+
+	_, _, err = New(&buf, "xml", "info")
+	require.Error(t, err)
+	_, _, err = New(&buf, FormatJSON, "loud")
+	require.Error(t, err)
+}
+
+func TestContextRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _, err := New(&buf, FormatJSON, "info")
+	require.NoError(t, err)
+
+	logger.InfoContext(WithRequestID(context.Background(), "abc"), "with id")
+	logger.InfoContext(context.Background(), "without id")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	require.Equal(t, "abc", lines[0]["request_id"])
+	require.NotContains(t, lines[1], "request_id")
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger, _, err := New(&buf, FormatJSON, "info")
+	require.NoError(t, err)
+
+	var seen string
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(Middleware(logger))
+	router.GET("/users/:id", func(ctx *gin.Context) {
+		seen = RequestID(ctx)
+		ctx.Status(http.StatusNoContent)
+	})
+
+	t.Run("generates an ID", func(t *testing.T) {
+		buf.Reset()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+		id := recorder.Header().Get(RequestIDHeader)
+		require.Len(t, id, 32)
+		require.Equal(t, id, seen)
+
+		lines := decodeLines(t, &buf)
+		require.Len(t, lines, 1)
+		require.Equal(t, id, lines[0]["request_id"])
+		require.Equal(t, "/users/:id", lines[0]["route"])
+		require.Equal(t, float64(http.StatusNoContent), lines[0]["status"])
+	})
+
+	t.Run("keeps the client's ID", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		request.Header.Set(RequestIDHeader, "from-client")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		require.Equal(t, "from-client", recorder.Header().Get(RequestIDHeader))
+		require.Equal(t, "from-client", seen)
+	})
+
+	t.Run("replaces an oversized ID", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		request.Header.Set(RequestIDHeader, strings.Repeat("x", maxRequestIDLength+1))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		require.Len(t, recorder.Header().Get(RequestIDHeader), 32)
+	})
+}
+
+type slowDBTX struct {
+	db.DBTX
+	delay time.Duration
+}
+
+func (s slowDBTX) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	time.Sleep(s.delay)
+	return nil, nil
+}
+
+func TestSlowQueries(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _, err := New(&buf, FormatJSON, "info")
+	require.NoError(t, err)
+
+	query := "-- name: DeletePosts :exec\nDELETE FROM posts WHERE id = $1"
+	ctx := WithRequestID(context.Background(), "req-1")
+
+	_, err = SlowQueries(logger, time.Hour)(slowDBTX{}).ExecContext(ctx, query, 1)
+	require.NoError(t, err)
+	require.Empty(t, buf.String())
+
+	_, err = SlowQueries(logger, time.Millisecond)(slowDBTX{delay: 5 * time.Millisecond}).ExecContext(ctx, query, 1)
+	require.NoError(t, err)
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	require.Equal(t, "slow query", lines[0]["msg"])
+	require.Equal(t, slog.LevelWarn.String(), lines[0]["level"])
+	require.Equal(t, "DeletePosts", lines[0]["query"])
+	require.Equal(t, "req-1", lines[0]["request_id"])
+
+	require.Equal(t, slowDBTX{}, SlowQueries(logger, 0)(slowDBTX{}))
+}
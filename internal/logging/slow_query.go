@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// SlowQueries logs queries that take longer than threshold with their sqlc
+// name and the request ID of ctx; pass it to db.WithDBTXMiddleware.
+// A zero threshold disables the log.
+func SlowQueries(logger *slog.Logger, threshold time.Duration) db.DBTXMiddleware {
+	return func(next db.DBTX) db.DBTX {
+		if threshold <= 0 {
+			return next
+		}
+		return slowQueryDBTX{next: next, logger: logger, threshold: threshold}
+	}
+}
+
+type slowQueryDBTX struct {
+	next      db.DBTX
+	logger    *slog.Logger
+	threshold time.Duration
+}
+
+func (d slowQueryDBTX) observe(ctx context.Context, query string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	if elapsed < d.threshold {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("query", db.QueryName(query)),
+		slog.Duration("duration", elapsed),
+		slog.Duration("threshold", d.threshold),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	d.logger.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+}
+
+func (d slowQueryDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	result, err := d.next.ExecContext(ctx, query, args...)
+	d.observe(ctx, query, start, err)
+	return result, err
+}
+
+func (d slowQueryDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.next.PrepareContext(ctx, query)
+}
+
+func (d slowQueryDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := d.next.QueryContext(ctx, query, args...)
+	d.observe(ctx, query, start, err)
+	return rows, err
+}
+
+func (d slowQueryDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := d.next.QueryRowContext(ctx, query, args...)
+	d.observe(ctx, query, start, row.Err())
+	return row
+}
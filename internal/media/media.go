@@ -0,0 +1,86 @@
+// Package media inspects uploaded files: their SHA-256, the type their
+// content has regardless of what the client claims, and the dimensions of
+// images.
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strings"
+
+	_ "golang.org/x/image/webp"
+)
+
+// Info describes an uploaded file
+type Info struct {
+	MimeType string
+	Size     int64
+	// Checksum is the hex SHA-256 of the content
+	Checksum string
+	// Width and Height are set for images whose format is known
+	Width, Height int
+}
+
+// allowed lists the types that can be uploaded. Types a browser would run
+// as a document, e.g. HTML or SVG, are refused since media is served from
+// the site's own origin.
+var allowed = []string{"image/", "video/", "audio/", "application/pdf", "application/ogg"}
+
+// Allowed reports whether files of mimeType can be uploaded
+func Allowed(mimeType string) bool {
+	for _, prefix := range allowed {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Inspect reads r to the end and leaves it at the start again
+func Inspect(r io.ReadSeeker) (Info, error) {
+	var info Info
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return info, fmt.Errorf("read media err: %w", err)
+	}
+	info.MimeType, _, _ = strings.Cut(http.DetectContentType(head[:n]), ";")
+
+	hash := sha256.New()
+	hash.Write(head[:n])
+	rest, err := io.Copy(hash, r)
+	if err != nil {
+		return info, fmt.Errorf("read media err: %w", err)
+	}
+	info.Size = int64(n) + rest
+	info.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if strings.HasPrefix(info.MimeType, "image/") {
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return info, fmt.Errorf("read media err: %w", err)
+		}
+		// formats without a decoder simply have no dimensions
+		if config, _, err := image.DecodeConfig(r); err == nil {
+			info.Width, info.Height = config.Width, config.Height
+		}
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return info, fmt.Errorf("read media err: %w", err)
+	}
+	return info, nil
+}
+
+// Key is where a site keeps the file with checksum. It is derived from
+// the content, so a re-upload lands on the file already there.
+func Key(siteID int64, checksum string) string {
+	return fmt.Sprintf("sites/%d/sha256/%s/%s", siteID, checksum[:2], checksum)
+}
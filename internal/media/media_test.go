@@ -0,0 +1,57 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectImage(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 3, 2))))
+	r := bytes.NewReader(buf.Bytes())
+
+	info, err := Inspect(r)
+	require.NoError(t, err)
+	require.Equal(t, "image/png", info.MimeType)
+	require.Equal(t, int64(buf.Len()), info.Size)
+	require.Len(t, info.Checksum, 64)
+	require.Equal(t, 3, info.Width)
+	require.Equal(t, 2, info.Height)
+
+	// the file can be read again from the start
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, buf.Bytes(), content)
+}
+
+func TestInspectIgnoresClaimedType(t *testing.T) {
+	info, err := Inspect(strings.NewReader("<html><script>alert(1)</script></html>"))
+	require.NoError(t, err)
+	require.Equal(t, "text/html", info.MimeType)
+	require.False(t, Allowed(info.MimeType))
+
+	info, err = Inspect(strings.NewReader("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", info.Checksum)
+	require.Equal(t, int64(5), info.Size)
+	require.Zero(t, info.Width)
+}
+
+func TestAllowed(t *testing.T) {
+	require.True(t, Allowed("image/jpeg"))
+	require.True(t, Allowed("video/mp4"))
+	require.True(t, Allowed("application/pdf"))
+	require.False(t, Allowed("text/xml"))
+	require.False(t, Allowed("application/octet-stream"))
+}
+
+func TestKey(t *testing.T) {
+	require.Equal(t, "sites/7/sha256/2c/2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		Key(7, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"))
+}
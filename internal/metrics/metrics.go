@@ -0,0 +1,147 @@
+// Package metrics exposes Prometheus metrics for the HTTP and database layers.
+//
+// Metrics are registered on the default registry and served by Handler.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+const namespace = "frog_blossom"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by sqlc query name.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"query"})
+
+	dbQueryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Failed database queries by sqlc query name.",
+	}, []string{"query"})
+
+	dbTransactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_transactions_total",
+		Help:      "Store transactions by outcome (commit, rollback, retry).",
+	}, []string{"outcome"})
+
+	cacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Store cache lookups by sqlc query name and result (hit, miss).",
+	}, []string{"query", "result"})
+)
+
+// Handler serves the default registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterDBStats exports the sql.DB pool statistics as gauges, labelled
+// db_name="primary" or "replica_<n>"
+func RegisterDBStats(name string, conn *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(conn, name))
+}
+
+// Middleware records request counts and latency per route. Requests that
+// match no route are grouped under "unmatched" to keep cardinality bounded.
+func Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := ctx.Request.Method
+
+		httpRequests.WithLabelValues(method, route, strconv.Itoa(ctx.Writer.Status())).Inc()
+		httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveTx counts Store transaction outcomes; pass it to db.WithTxObserver
+func ObserveTx(event db.TxEvent) {
+	dbTransactions.WithLabelValues(string(event)).Inc()
+}
+
+// ObserveCache counts cache hits and misses; pass it as cache.Options.Observe
+func ObserveCache(query string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookups.WithLabelValues(query, result).Inc()
+}
+
+// DBTX instruments every query with its duration and errors, labelled with
+// the sqlc query name; pass it to db.WithDBTXMiddleware
+func DBTX(next db.DBTX) db.DBTX {
+	return instrumentedDBTX{next: next}
+}
+
+type instrumentedDBTX struct {
+	next db.DBTX
+}
+
+func observe(query string, start time.Time, err error) {
+	name := db.QueryName(query)
+	dbQueryDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		dbQueryErrors.WithLabelValues(name).Inc()
+	}
+}
+
+func (d instrumentedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	result, err := d.next.ExecContext(ctx, query, args...)
+	observe(query, start, err)
+	return result, err
+}
+
+func (d instrumentedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.next.PrepareContext(ctx, query)
+}
+
+func (d instrumentedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := d.next.QueryContext(ctx, query, args...)
+	observe(query, start, err)
+	return rows, err
+}
+
+func (d instrumentedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := d.next.QueryRowContext(ctx, query, args...)
+	observe(query, start, row.Err())
+	return row
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/api/v1/users/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNotFound)
+	})
+
+	before := promtest.ToFloat64(httpRequests.WithLabelValues("GET", "/api/v1/users/:id", "404"))
+	for _, path := range []string{"/api/v1/users/1", "/api/v1/users/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	after := promtest.ToFloat64(httpRequests.WithLabelValues("GET", "/api/v1/users/:id", "404"))
+	require.Equal(t, before+2, after)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope", nil))
+	require.Equal(t, float64(1), promtest.ToFloat64(httpRequests.WithLabelValues("GET", "unmatched", "404")))
+}
+
+type fakeDBTX struct {
+	db.DBTX
+	err error
+}
+
+func (f fakeDBTX) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, f.err
+}
+
+func TestDBTXCountsErrorsByQueryName(t *testing.T) {
+	query := "-- name: DeletePosts :exec\nDELETE FROM posts WHERE id = $1"
+
+	_, err := DBTX(fakeDBTX{}).ExecContext(context.Background(), query, 1)
+	require.NoError(t, err)
+	require.Equal(t, float64(0), promtest.ToFloat64(dbQueryErrors.WithLabelValues("DeletePosts")))
+
+	_, err = DBTX(fakeDBTX{err: errors.New("boom")}).ExecContext(context.Background(), query, 1)
+	require.Error(t, err)
+	require.Equal(t, float64(1), promtest.ToFloat64(dbQueryErrors.WithLabelValues("DeletePosts")))
+	require.Equal(t, 1, promtest.CollectAndCount(dbQueryDuration))
+}
+
+func TestObserveTx(t *testing.T) {
+	ObserveTx(db.TxCommit)
+	ObserveTx(db.TxRetry)
+	ObserveTx(db.TxCommit)
+
+	expected := `
+# HELP frog_blossom_db_transactions_total Store transactions by outcome (commit, rollback, retry).
+# TYPE frog_blossom_db_transactions_total counter
+frog_blossom_db_transactions_total{outcome="commit"} 2
+frog_blossom_db_transactions_total{outcome="retry"} 1
+`
+	require.NoError(t, promtest.CollectAndCompare(dbTransactions, strings.NewReader(expected)))
+}
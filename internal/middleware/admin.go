@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+)
+
+// adminKey marks requests that carry the admin token
+const adminKey = "admin"
+
+// hasAdminToken reports whether the request carries
+// "Authorization: Bearer <token>". An empty token never matches.
+func hasAdminToken(ctx *gin.Context, token string) bool {
+	given, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	return token != "" && ok && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// RequireAdminToken only lets requests through that carry
+// "Authorization: Bearer <token>". An empty token disables the route.
+func RequireAdminToken(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !hasAdminToken(ctx, token) {
+			ctx.Error(apperr.Forbidden("admin token required", nil))
+			ctx.Abort()
+			return
+		}
+		ctx.Set(adminKey, true)
+		ctx.Next()
+	}
+}
+
+// IdentifyAdmin lets every request through and marks those carrying the
+// admin token, for routes that are open but allow admins more, see IsAdmin
+func IdentifyAdmin(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if hasAdminToken(ctx, token) {
+			ctx.Set(adminKey, true)
+		}
+		ctx.Next()
+	}
+}
+
+// IsAdmin reports whether the request carried the admin token
+func IsAdmin(ctx *gin.Context) bool {
+	return ctx.GetBool(adminKey)
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	corsAllowMethods  = "GET, POST, PUT, PATCH, DELETE"
+	corsAllowHeaders  = "Authorization, Content-Type, X-Request-ID"
+	corsExposeHeaders = "X-Request-ID"
+	corsMaxAge        = "600"
+)
+
+// CORS lets browsers call the API from the allowed origins and answers
+// their preflight requests. origins is called per request so the list can
+// change while serving; "*" allows any origin. Requests from other origins
+// pass through without CORS headers and are blocked by the browser.
+func CORS(origins func() []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		origin := ctx.GetHeader("Origin")
+		if origin == "" {
+			ctx.Next()
+			return
+		}
+
+		header := ctx.Writer.Header()
+		header.Add("Vary", "Origin")
+		if !originAllowed(origin, origins()) {
+			ctx.Next()
+			return
+		}
+		header.Set("Access-Control-Allow-Origin", origin)
+
+		if ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", corsAllowMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			header.Set("Access-Control-Max-Age", corsMaxAge)
+			ctx.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		header.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		ctx.Next()
+	}
+}
+
+func originAllowed(origin string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == "*" || a == origin {
+			return true
+		}
+	}
+	return false
+}
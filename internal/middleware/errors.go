@@ -0,0 +1,60 @@
+// Package middleware holds the Gin middleware shared by the API server
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+)
+
+// ProblemContentType is the media type of RFC 7807 error bodies
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Code is a stable identifier
+// clients can switch on; Detail is human readable and may change.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Code     string `json:"code"`
+	Instance string `json:"instance,omitempty"`
+
+	Errors []apperr.FieldError `json:"errors,omitempty"`
+}
+
+// Errors renders the last error a handler attached with ctx.Error as a
+// problem+json response. Handlers must not write a body when they fail.
+// Errors that are not domain errors become a 500 without their message.
+func Errors() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		appErr := apperr.From(ctx.Errors.Last().Err)
+		if appErr.Kind == apperr.KindInternal {
+			slog.ErrorContext(ctx.Request.Context(), "internal error",
+				slog.String("method", ctx.Request.Method),
+				slog.String("path", ctx.Request.URL.Path),
+				slog.Any("error", appErr.Err),
+			)
+		}
+
+		status := appErr.Kind.Status()
+		ctx.Header("Content-Type", ProblemContentType)
+		ctx.JSON(status, Problem{
+			Type:     "about:blank",
+			Title:    http.StatusText(status),
+			Status:   status,
+			Detail:   appErr.Detail,
+			Code:     appErr.Kind.Code(),
+			Instance: ctx.Request.URL.Path,
+			Errors:   appErr.Fields,
+		})
+	}
+}
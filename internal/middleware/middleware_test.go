@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	origins := []string{"https://admin.example.com"}
+	router := gin.New()
+	router.Use(CORS(func() []string { return origins }))
+	router.GET("/users", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	request := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/users", nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	allowed := request(http.MethodGet, "https://admin.example.com")
+	require.Equal(t, http.StatusOK, allowed.Code)
+	require.Equal(t, "https://admin.example.com", allowed.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "Origin", allowed.Header().Get("Vary"))
+
+	preflight := request(http.MethodOptions, "https://admin.example.com")
+	require.Equal(t, http.StatusNoContent, preflight.Code)
+	require.Contains(t, preflight.Header().Get("Access-Control-Allow-Methods"), http.MethodPost)
+
+	denied := request(http.MethodGet, "https://evil.example.com")
+	require.Equal(t, http.StatusOK, denied.Code)
+	require.Empty(t, denied.Header().Get("Access-Control-Allow-Origin"))
+
+	// the list is read per request
+	origins = []string{"*"}
+	require.Equal(t, "https://evil.example.com", request(http.MethodGet, "https://evil.example.com").Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := NewRateLimiter(1, 2)
+
+	ok, _ := limiter.allow("10.0.0.1", now)
+	require.True(t, ok)
+	ok, _ = limiter.allow("10.0.0.1", now)
+	require.True(t, ok)
+	ok, retryAfter := limiter.allow("10.0.0.1", now)
+	require.False(t, ok)
+	require.Equal(t, time.Second, retryAfter)
+
+	// other clients have their own bucket
+	ok, _ = limiter.allow("10.0.0.2", now)
+	require.True(t, ok)
+
+	// tokens refill over time
+	ok, _ = limiter.allow("10.0.0.1", now.Add(time.Second))
+	require.True(t, ok)
+
+	limiter.SetLimit(0, 0)
+	ok, _ = limiter.allow("10.0.0.1", now.Add(time.Second))
+	require.True(t, ok, "a zero limit disables limiting")
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Errors())
+	router.Use(NewRateLimiter(1, 1).Middleware())
+	router.GET("/users", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/users", nil))
+	require.Equal(t, http.StatusOK, first.Code)
+
+	second := httptest.NewRecorder()
+	router.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/users", nil))
+	require.Equal(t, http.StatusTooManyRequests, second.Code)
+	require.Equal(t, "1", second.Header().Get("Retry-After"))
+	require.Equal(t, ProblemContentType, second.Header().Get("Content-Type"))
+	require.Contains(t, second.Body.String(), `"code":"rate_limited"`)
+}
+
+type siteFinder map[string]db.Site
+
+func (f siteFinder) GetSiteByDomain(_ context.Context, domain string) (db.Site, error) {
+	site, ok := f[domain]
+	if !ok {
+		return db.Site{}, sql.ErrNoRows
+	}
+	return site, nil
+}
+
+func TestSite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Errors(), Site(siteFinder{"example.com": {ID: 7, Domain: "example.com"}}))
+	router.GET("/", func(ctx *gin.Context) {
+		site, ok := db.SiteFrom(ctx.Request.Context())
+		require.True(t, ok)
+		ctx.String(http.StatusOK, "%d", site.ID)
+	})
+
+	for host, status := range map[string]int{
+		"example.com":      http.StatusOK,
+		"Example.COM:8080": http.StatusOK,
+		"example.com.":     http.StatusOK,
+		"other.example":    http.StatusNotFound,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		require.Equal(t, status, recorder.Code, host)
+		if status == http.StatusOK {
+			require.Equal(t, "7", recorder.Body.String())
+		}
+	}
+}
+
+func TestIdentifyAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/open", IdentifyAdmin("admin-token"), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, IsAdmin(ctx))
+	})
+	router.GET("/disabled", IdentifyAdmin(""), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, IsAdmin(ctx))
+	})
+
+	request := func(path, authorization string) string {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		return recorder.Body.String()
+	}
+
+	require.Equal(t, "true", request("/open", "Bearer admin-token"))
+	require.Equal(t, "false", request("/open", "Bearer another-token"))
+	require.Equal(t, "false", request("/open", ""))
+	require.Equal(t, "false", request("/disabled", "Bearer "))
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"golang.org/x/time/rate"
+)
+
+// Idle clients are forgotten so the limiter's memory stays bounded
+const (
+	rateLimitSweepEvery = time.Minute
+	rateLimitIdleAfter  = 3 * time.Minute
+)
+
+// RateLimiter limits requests per client IP with a token bucket. The limit
+// can be changed while serving with SetLimit.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*rateClient
+	lastSweep time.Time
+}
+
+type rateClient struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// NewRateLimiter allows rps requests per second per client, in bursts of
+// up to burst. An rps of 0 lets every request through.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		limit:     rate.Limit(rps),
+		burst:     burst,
+		clients:   map[string]*rateClient{},
+		lastSweep: time.Now(),
+	}
+}
+
+// SetLimit changes the limit for new and already seen clients
+func (l *RateLimiter) SetLimit(rps float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit, l.burst = rate.Limit(rps), burst
+	for _, c := range l.clients {
+		c.limiter.SetLimit(l.limit)
+		c.limiter.SetBurst(l.burst)
+	}
+}
+
+func (l *RateLimiter) allow(key string, now time.Time) (ok bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit <= 0 {
+		return true, 0
+	}
+
+	if now.Sub(l.lastSweep) > rateLimitSweepEvery {
+		for k, c := range l.clients {
+			if now.Sub(c.seen) > rateLimitIdleAfter {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, found := l.clients[key]
+	if !found {
+		c = &rateClient{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.seen = now
+
+	reservation := c.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, time.Second
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// Middleware rejects clients over the limit with 429 Too Many Requests and
+// a Retry-After header
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ok, retryAfter := l.allow(ctx.ClientIP(), time.Now())
+		if !ok {
+			seconds := int(retryAfter.Seconds() + 0.999)
+			ctx.Header("Retry-After", strconv.Itoa(seconds))
+			ctx.Error(apperr.RateLimited("too many requests, retry later", nil))
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// ReadYourWrites pins each request to the primary database once it has
+// written, so it never reads its own changes back from a lagging replica
+func ReadYourWrites() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(db.WithReadYourWrites(ctx.Request.Context()))
+		ctx.Next()
+	}
+}
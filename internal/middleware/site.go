@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+)
+
+// SiteFinder is the part of db.Store sites are resolved with
+type SiteFinder interface {
+	GetSiteByDomain(ctx context.Context, domain string) (db.Site, error)
+}
+
+// Site scopes each request to the site whose domain is its Host header,
+// see db.SiteFrom. Requests for unknown hosts get a 404.
+func Site(store SiteFinder) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		domain := Domain(ctx.Request.Host)
+		site, err := store.GetSiteByDomain(ctx, domain)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = apperr.NotFound("no site is served on "+domain, err)
+			}
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(db.WithSite(ctx.Request.Context(), site))
+		ctx.Next()
+	}
+}
+
+// Domain normalises a Host header to the domain sites are stored under:
+// lower case, without port or trailing dot
+func Domain(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
@@ -0,0 +1,142 @@
+// Package settings serves the runtime settings, the config fields tagged
+// reload, and reloads them when the config files change, so log level,
+// rate limits, CORS origins and feature flags change without a restart.
+package settings
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/reflection/frog_blossom_db/config"
+)
+
+// debounce collapses the burst of events an editor save produces
+const debounce = 200 * time.Millisecond
+
+// Event is published after a reload applied at least one change
+type Event struct {
+	Previous config.Config
+	Current  config.Config
+	// Changed lists the keys of the applied settings
+	Changed []string
+}
+
+// Manager holds the current config and publishes reloads
+type Manager struct {
+	opts    config.Options
+	current atomic.Pointer[config.Config]
+
+	// mu serialises reloads so subscribers see events in order
+	mu          sync.Mutex
+	subscribers []func(Event)
+}
+
+// New starts from the config loaded at startup; opts are passed to
+// config.Load on every reload
+func New(initial config.Config, opts config.Options) *Manager {
+	m := &Manager{opts: opts}
+	m.current.Store(&initial)
+	return m
+}
+
+// Current returns the config in effect. Its slices must not be modified.
+func (m *Manager) Current() config.Config {
+	return *m.current.Load()
+}
+
+// Enabled reports whether the feature flag is in FEATURE_FLAGS
+func (m *Manager) Enabled(flag string) bool {
+	return slices.Contains(m.Current().FeatureFlags, flag)
+}
+
+// Subscribe calls fn after each reload that changed something. fn runs on
+// the reloading goroutine and must not block.
+func (m *Manager) Subscribe(fn func(Event)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Reload loads the config again and applies the runtime settings that
+// changed. An invalid config is rejected as a whole; changes to other
+// settings are ignored with a warning, as they need a restart.
+func (m *Manager) Reload() error {
+	next, err := config.Load(m.opts)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	previous := m.Current()
+	merged, applied, refused := previous.ApplyReload(next)
+	if len(refused) > 0 {
+		slog.Warn("config reload ignored settings that need a restart", slog.String("keys", strings.Join(refused, ",")))
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	m.current.Store(&merged)
+	slog.Info("config reloaded", slog.String("keys", strings.Join(applied, ",")))
+
+	event := Event{Previous: previous, Current: merged, Changed: applied}
+	for _, fn := range m.subscribers {
+		fn(event)
+	}
+	return nil
+}
+
+// Watch reloads whenever a config file in opts.Path changes, until ctx is
+// done. The directory is watched rather than the files so that editors
+// replacing a file by rename are noticed. Run it as a lifecycle worker.
+func (m *Manager) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	dir := m.opts.Path
+	if dir == "" {
+		dir = "."
+	}
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if config.IsConfigFile(filepath.Base(event.Name)) {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			slog.Warn("config watcher", slog.Any("error", err))
+		case <-timer.C:
+			if err := m.Reload(); err != nil {
+				slog.Error("config reload rejected", slog.Any("error", err))
+			}
+		}
+	}
+}
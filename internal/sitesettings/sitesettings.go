@@ -0,0 +1,291 @@
+// Package sitesettings defines the typed settings each site can change,
+// with their defaults and validation, and reads a site's effective values.
+// Overrides are stored as JSON in the site_settings table.
+package sitesettings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/locale"
+)
+
+// Kind is the JSON type of a setting's value
+type Kind string
+
+const (
+	KindString  Kind = "string"
+	KindInteger Kind = "integer"
+	KindBoolean Kind = "boolean"
+)
+
+// Definition describes a setting. Default must be a string, int64 or bool
+// matching Kind; Check, if set, rejects values with a message. Normalize,
+// if set, does the same and returns the value in its canonical form.
+type Definition struct {
+	Key         string
+	Kind        Kind
+	Default     any
+	Description string
+	Check       func(value any) error
+	Normalize   func(value any) (any, error)
+}
+
+var (
+	mu          sync.RWMutex
+	definitions = map[string]Definition{}
+)
+
+func init() {
+	Register(Definition{
+		Key:         "site_title",
+		Kind:        KindString,
+		Default:     "",
+		Description: "Name of the site shown in titles and feeds",
+		Check:       maxLength(255),
+	})
+	Register(Definition{
+		Key:         "site_language",
+		Kind:        KindString,
+		Default:     "en",
+		Description: "Language of the site as a tag such as en or pt-BR",
+		Normalize: func(value any) (any, error) {
+			tag, err := locale.Parse(value.(string))
+			if err != nil {
+				return nil, errors.New("must be a language tag such as en or pt-BR")
+			}
+			return tag, nil
+		},
+	})
+	Register(Definition{
+		Key:         "page_amount",
+		Kind:        KindInteger,
+		Default:     int64(10),
+		Description: "Number of posts per page of a listing",
+		Check:       between(1, 100),
+	})
+	Register(Definition{
+		Key:         "comments_enabled",
+		Kind:        KindBoolean,
+		Default:     true,
+		Description: "Whether visitors can comment on posts",
+	})
+}
+
+func maxLength(n int) func(any) error {
+	return func(value any) error {
+		if len(value.(string)) > n {
+			return fmt.Errorf("must be at most %d characters", n)
+		}
+		return nil
+	}
+}
+
+func between(lo, hi int64) func(any) error {
+	return func(value any) error {
+		if v := value.(int64); v < lo || v > hi {
+			return fmt.Errorf("must be between %d and %d", lo, hi)
+		}
+		return nil
+	}
+}
+
+// Register adds a setting. It panics if the key is taken or the default
+// doesn't fit the kind, so mistakes surface at startup.
+func Register(def Definition) {
+	if _, err := convert(def.Kind, def.Default); err != nil {
+		panic(fmt.Sprintf("sitesettings: default of %s: %v", def.Key, err))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := definitions[def.Key]; ok {
+		panic("sitesettings: " + def.Key + " is already registered")
+	}
+	definitions[def.Key] = def
+}
+
+// Lookup returns the definition of key
+func Lookup(key string) (Definition, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	def, ok := definitions[key]
+	return def, ok
+}
+
+// Definitions returns every setting sorted by key
+func Definitions() []Definition {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	defs := make([]Definition, 0, len(definitions))
+	for _, def := range definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Key < defs[j].Key })
+	return defs
+}
+
+// convert checks a decoded value against kind and normalises numbers to int64
+func convert(kind Kind, value any) (any, error) {
+	switch kind {
+	case KindString:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case KindBoolean:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case KindInteger:
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				return n, nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown kind %q", kind)
+	}
+	return nil, fmt.Errorf("must be a %s", kind)
+}
+
+// Decode parses a value for key, runs its checks and normalises it. An
+// unknown key is a not found error, a bad value a validation error on the
+// value field.
+func Decode(key string, raw json.RawMessage) (any, error) {
+	def, ok := Lookup(key)
+	if !ok {
+		return nil, apperr.NotFound(fmt.Sprintf("unknown setting %s", key), nil)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, apperr.InvalidFields([]apperr.FieldError{{Field: "value", Rule: "json", Message: "must be valid JSON"}}, err)
+	}
+
+	value, err := convert(def.Kind, decoded)
+	if err != nil {
+		return nil, apperr.InvalidFields([]apperr.FieldError{{Field: "value", Rule: "type", Message: err.Error()}}, err)
+	}
+	if def.Check != nil {
+		if err := def.Check(value); err != nil {
+			return nil, apperr.InvalidFields([]apperr.FieldError{{Field: "value", Rule: "check", Message: err.Error()}}, err)
+		}
+	}
+	if def.Normalize != nil {
+		if value, err = def.Normalize(value); err != nil {
+			return nil, apperr.InvalidFields([]apperr.FieldError{{Field: "value", Rule: "check", Message: err.Error()}}, err)
+		}
+	}
+	return value, nil
+}
+
+// Value is the effective value of a setting on a site
+type Value struct {
+	Key         string `json:"key"`
+	Kind        Kind   `json:"kind"`
+	Value       any    `json:"value"`
+	Default     any    `json:"default"`
+	Overridden  bool   `json:"overridden"`
+	Description string `json:"description"`
+}
+
+// Value returns the setting overridden with value, a result of Decode, or
+// at its default when value is nil
+func (def Definition) Value(value any) Value {
+	v := Value{
+		Key:         def.Key,
+		Kind:        def.Kind,
+		Value:       def.Default,
+		Default:     def.Default,
+		Description: def.Description,
+	}
+	if value != nil {
+		v.Value = value
+		v.Overridden = true
+	}
+	return v
+}
+
+// Settings are the effective settings of a site
+type Settings struct {
+	SiteID int64
+	values map[string]Value
+}
+
+// Reader is the part of db.Store settings are loaded with. Wrap the store
+// with cache.Store to cache the reads.
+type Reader interface {
+	ListSiteSettings(ctx context.Context, siteID int64) ([]db.SiteSetting, error)
+}
+
+// Load returns the settings of a site: stored values where they are valid,
+// defaults everywhere else
+func Load(ctx context.Context, store Reader, siteID int64) (Settings, error) {
+	rows, err := store.ListSiteSettings(ctx, siteID)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	settings := Settings{SiteID: siteID, values: map[string]Value{}}
+	for _, def := range Definitions() {
+		settings.values[def.Key] = def.Value(nil)
+	}
+	for _, row := range rows {
+		value, err := Decode(row.Key, row.Value)
+		if err != nil {
+			slog.WarnContext(ctx, "ignoring stored site setting",
+				slog.Int64("site_id", siteID), slog.String("key", row.Key), slog.Any("error", err))
+			continue
+		}
+		def, _ := Lookup(row.Key)
+		settings.values[row.Key] = def.Value(value)
+	}
+	return settings, nil
+}
+
+// Values returns every setting sorted by key
+func (s Settings) Values() []Value {
+	values := make([]Value, 0, len(s.values))
+	for _, v := range s.values {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+	return values
+}
+
+// Get returns the value of key, or nil if no such setting exists
+func (s Settings) Get(key string) any {
+	return s.values[key].Value
+}
+
+// String returns a string setting, or "" if key isn't one
+func (s Settings) String(key string) string {
+	v, _ := s.Get(key).(string)
+	return v
+}
+
+// Int returns an integer setting, or 0 if key isn't one
+func (s Settings) Int(key string) int64 {
+	v, _ := s.Get(key).(int64)
+	return v
+}
+
+// Bool returns a boolean setting, or false if key isn't one
+func (s Settings) Bool(key string) bool {
+	v, _ := s.Get(key).(bool)
+	return v
+}
@@ -0,0 +1,98 @@
+package sitesettings
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReader []db.SiteSetting
+
+func (f fakeReader) ListSiteSettings(_ context.Context, siteID int64) ([]db.SiteSetting, error) {
+	var rows []db.SiteSetting
+	for _, row := range f {
+		if row.SiteID == siteID {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		key      string
+		raw      string
+		expected any
+		rule     string
+	}{
+		{name: "String", key: "site_language", raw: `"pt-BR"`, expected: "pt-BR"},
+		{name: "NormalisedLanguage", key: "site_language", raw: `"zh-hant-tw"`, expected: "zh-Hant-TW"},
+		{name: "Integer", key: "page_amount", raw: `25`, expected: int64(25)},
+		{name: "Boolean", key: "comments_enabled", raw: `false`, expected: false},
+		{name: "WrongType", key: "page_amount", raw: `"25"`, rule: "type"},
+		{name: "Fraction", key: "page_amount", raw: `2.5`, rule: "type"},
+		{name: "OutOfRange", key: "page_amount", raw: `500`, rule: "check"},
+		{name: "BadLanguage", key: "site_language", raw: `"english"`, rule: "check"},
+		{name: "UndeterminedLanguage", key: "site_language", raw: `"und"`, rule: "check"},
+		{name: "NotJSON", key: "site_title", raw: `{`, rule: "json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := Decode(tc.key, json.RawMessage(tc.raw))
+			if tc.rule == "" {
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, value)
+				return
+			}
+
+			var appErr *apperr.Error
+			require.ErrorAs(t, err, &appErr)
+			require.Equal(t, apperr.KindValidation, appErr.Kind)
+			require.Equal(t, "value", appErr.Fields[0].Field)
+			require.Equal(t, tc.rule, appErr.Fields[0].Rule)
+		})
+	}
+
+	_, err := Decode("no_such_setting", json.RawMessage(`1`))
+	require.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func TestLoad(t *testing.T) {
+	reader := fakeReader{
+		{SiteID: 1, Key: "page_amount", Value: json.RawMessage(`25`)},
+		{SiteID: 1, Key: "site_language", Value: json.RawMessage(`"not a language"`)},
+		{SiteID: 1, Key: "retired_setting", Value: json.RawMessage(`true`)},
+		{SiteID: 2, Key: "comments_enabled", Value: json.RawMessage(`false`)},
+	}
+
+	settings, err := Load(context.Background(), reader, 1)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(25), settings.Int("page_amount"))
+	require.Equal(t, "en", settings.String("site_language"), "invalid stored values fall back to the default")
+	require.True(t, settings.Bool("comments_enabled"))
+	require.Nil(t, settings.Get("retired_setting"))
+
+	values := settings.Values()
+	require.Len(t, values, len(Definitions()))
+	for _, v := range values {
+		require.Equal(t, v.Key == "page_amount", v.Overridden, v.Key)
+	}
+}
+
+func TestRegisterRejectsBadDefaults(t *testing.T) {
+	require.Panics(t, func() {
+		Register(Definition{Key: "broken", Kind: KindInteger, Default: "ten"})
+	})
+	require.Panics(t, func() {
+		Register(Definition{Key: "site_title", Kind: KindString, Default: ""})
+	})
+	_, ok := Lookup("broken")
+	require.False(t, ok)
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocal(t.TempDir())
+
+	require.NoError(t, local.Put(ctx, "sites/1/a.txt", strings.NewReader("hello")))
+
+	file, err := local.Open(ctx, "sites/1/a.txt")
+	require.NoError(t, err)
+	_, err = file.Seek(1, io.SeekStart)
+	require.NoError(t, err)
+	body, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, "ello", string(body))
+
+	require.NoError(t, local.Put(ctx, "sites/1/a.txt", strings.NewReader("replaced")))
+	file, err = local.Open(ctx, "sites/1/a.txt")
+	require.NoError(t, err)
+	body, err = io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, "replaced", string(body))
+
+	require.NoError(t, local.Delete(ctx, "sites/1/a.txt"))
+	require.NoError(t, local.Delete(ctx, "sites/1/a.txt"))
+	_, err = local.Open(ctx, "sites/1/a.txt")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalDeleteAll(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocal(t.TempDir())
+	for _, key := range []string{"sites/1/variants/a/640w.jpeg", "sites/1/variants/a/320w.jpeg", "sites/1/variants/ab/640w.jpeg"} {
+		require.NoError(t, local.Put(ctx, key, strings.NewReader("x")))
+	}
+
+	require.NoError(t, local.DeleteAll(ctx, "sites/1/variants/a"))
+	require.NoError(t, local.DeleteAll(ctx, "sites/1/variants/a"))
+
+	_, err := local.Open(ctx, "sites/1/variants/a/640w.jpeg")
+	require.ErrorIs(t, err, ErrNotFound)
+	file, err := local.Open(ctx, "sites/1/variants/ab/640w.jpeg")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+func TestLocalLeavesNoPartialFile(t *testing.T) {
+	root := t.TempDir()
+	local := NewLocal(root)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := local.Put(ctx, "a.txt", strings.NewReader("hello"))
+	require.ErrorIs(t, err, context.Canceled)
+
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestLocalRejectsKeysOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	local := NewLocal(filepath.Join(root, "media"))
+	ctx := context.Background()
+
+	for _, key := range []string{"", "/", "../escape", "a/../../escape", "/abs", "a//b", `a\b`} {
+		require.Error(t, local.Put(ctx, key, strings.NewReader("x")), key)
+	}
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
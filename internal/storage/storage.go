@@ -0,0 +1,28 @@
+// Package storage keeps the files behind media items.
+//
+// Keys are slash-separated paths such as sites/1/sha256/ab/ab12…; what a
+// key names never changes, so a file is written once and read many times.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned for a key that has no file
+var ErrNotFound = errors.New("storage: file not found")
+
+// Storage stores files by key. Local is the filesystem implementation; an
+// object store such as S3 can be plugged in by implementing it.
+type Storage interface {
+	// Put stores the contents of r under key, replacing any file there.
+	// Readers never see a partly written file.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns the file under key for reading; the caller closes it
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete removes the file under key; a missing file is not an error
+	Delete(ctx context.Context, key string) error
+	// DeleteAll removes every file whose key is under prefix/
+	DeleteAll(ctx context.Context, prefix string) error
+}
@@ -0,0 +1,98 @@
+package testutil
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/reflection/frog_blossom_db/db/migration"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+)
+
+// Schema is an isolated Postgres schema with every migration applied.
+// Connections opened through it resolve unqualified table names to the
+// schema only, so tests using different schemas never see each other's rows.
+type Schema struct {
+	Name  string
+	URL   string
+	DB    *sql.DB
+	Store db.Store
+
+	admin *sql.DB
+}
+
+var unsafeIdent = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// NewSchema creates a uniquely named schema on the server, migrates it and
+// opens a connection pool bound to it. prefix is used to make the schema
+// recognisable when inspecting the server, e.g. the package name.
+func (s *Server) NewSchema(ctx context.Context, prefix string) (*Schema, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	prefix = unsafeIdent.ReplaceAllString(strings.ToLower(prefix), "_")
+	if len(prefix) > 40 {
+		prefix = prefix[:40]
+	}
+	name := fmt.Sprintf("test_%s_%s", prefix, hex.EncodeToString(suffix))
+
+	admin, err := sql.Open("postgres", s.URL)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := admin.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA %q", name)); err != nil {
+		admin.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+
+	schema := &Schema{Name: name, admin: admin}
+
+	schema.URL, err = withSearchPath(s.URL, name)
+	if err != nil {
+		schema.Close()
+		return nil, err
+	}
+
+	if err := migration.Up(schema.URL); err != nil {
+		schema.Close()
+		return nil, fmt.Errorf("migrate schema: %w", err)
+	}
+
+	schema.DB, err = sql.Open("postgres", schema.URL)
+	if err != nil {
+		schema.Close()
+		return nil, err
+	}
+	schema.Store = db.NewStore(schema.DB)
+
+	return schema, nil
+}
+
+// Close closes the schema's pool and drops the schema with everything in it
+func (s *Schema) Close() error {
+	if s.DB != nil {
+		s.DB.Close()
+	}
+	defer s.admin.Close()
+
+	_, err := s.admin.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %q CASCADE", s.Name))
+	return err
+}
+
+// withSearchPath sets the search_path run-time parameter on a connection URL
+func withSearchPath(source, schema string) (string, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("parse db source: %w", err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,85 @@
+// Package testutil provides a hermetic Postgres harness for integration tests.
+//
+// Each test package gets its own migrated schema (see Main), and tests that
+// want a clean slate of their own can ask for one with NewTestSchema. All
+// schemas are dropped on cleanup, so packages and parallel tests never share
+// rows.
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+)
+
+var (
+	mu     sync.Mutex
+	server *Server
+)
+
+// Main starts (or connects to) Postgres, creates a schema for the test
+// package, passes it to setup and runs the tests. Everything is torn down
+// before the process exits.
+//
+// When no server is available the package's tests are skipped, unless
+// TEST_DB_REQUIRED is set, in which case they fail.
+func Main(m *testing.M, pkg string, setup func(*Schema)) {
+	os.Exit(run(m, pkg, setup))
+}
+
+func run(m *testing.M, pkg string, setup func(*Schema)) int {
+	ctx := context.Background()
+
+	srv, err := StartServer(ctx)
+	if err != nil {
+		if errors.Is(err, ErrNoServer) && os.Getenv("TEST_DB_REQUIRED") == "" {
+			fmt.Fprintf(os.Stderr, "testutil: skipping %s integration tests: %v\n", pkg, err)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "testutil: cannot start postgres: %v\n", err)
+		return 1
+	}
+	defer srv.Stop()
+
+	mu.Lock()
+	server = srv
+	mu.Unlock()
+
+	schema, err := srv.NewSchema(ctx, pkg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testutil: cannot create schema: %v\n", err)
+		return 1
+	}
+	defer schema.Close()
+
+	setup(schema)
+	return m.Run()
+}
+
+// NewTestSchema gives a single test its own migrated schema, dropped when
+// the test finishes. It is meant for tests that call t.Parallel() or need
+// an empty database; it must be used from a package whose TestMain calls Main.
+func NewTestSchema(tb testing.TB) *Schema {
+	tb.Helper()
+
+	mu.Lock()
+	srv := server
+	mu.Unlock()
+	if srv == nil {
+		tb.Fatal("testutil: NewTestSchema requires testutil.Main in TestMain")
+	}
+
+	schema, err := srv.NewSchema(context.Background(), tb.Name())
+	if err != nil {
+		tb.Fatalf("testutil: cannot create schema: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := schema.Close(); err != nil {
+			tb.Errorf("testutil: cannot drop schema %s: %v", schema.Name, err)
+		}
+	})
+	return schema
+}
@@ -0,0 +1,203 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryNameKey holds the sqlc name of the statement a span covers
+const queryNameKey = attribute.Key("db.sqlc.query")
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// DBTX starts a client span for every query, named after its sqlc query;
+// pass it to db.WithDBTXMiddleware
+func DBTX(next db.DBTX) db.DBTX {
+	return tracedDBTX{next: next}
+}
+
+type tracedDBTX struct {
+	next db.DBTX
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := db.QueryName(query)
+	return tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperation(name),
+			queryNameKey.String(name),
+		),
+	)
+}
+
+func (d tracedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+	result, err := d.next.ExecContext(ctx, query, args...)
+	endSpan(span, err)
+	return result, err
+}
+
+func (d tracedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.next.PrepareContext(ctx, query)
+}
+
+func (d tracedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuery(ctx, query)
+	rows, err := d.next.QueryContext(ctx, query, args...)
+	endSpan(span, err)
+	return rows, err
+}
+
+func (d tracedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuery(ctx, query)
+	row := d.next.QueryRowContext(ctx, query, args...)
+	endSpan(span, row.Err())
+	return row
+}
+
+// Store wraps a Store so each transaction method gets a span that parents
+// the spans of the queries it runs
+func Store(next db.Store) db.Store {
+	return &tracedStore{Store: next}
+}
+
+type tracedStore struct {
+	db.Store
+}
+
+func startTx(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "Store."+method, trace.WithAttributes(semconv.DBSystemPostgreSQL))
+}
+
+func (s *tracedStore) InitSetupConfigTx(ctx context.Context, args db.InitSetupConfigTxParams) (db.InitSetupConfigTxResult, error) {
+	ctx, span := startTx(ctx, "InitSetupConfigTx")
+	result, err := s.Store.InitSetupConfigTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) CreatePostsTx(ctx context.Context, args db.CreateContentTxParams) (db.CreateContentTxResult, error) {
+	ctx, span := startTx(ctx, "CreatePostsTx")
+	result, err := s.Store.CreatePostsTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) CreatePageTx(ctx context.Context, args db.CreateContentTxParams) (db.CreateContentTxResult, error) {
+	ctx, span := startTx(ctx, "CreatePageTx")
+	result, err := s.Store.CreatePageTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) UpdatePostsTx(ctx context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	ctx, span := startTx(ctx, "UpdatePostsTx")
+	result, err := s.Store.UpdatePostsTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) UpdatePageTx(ctx context.Context, args db.UpdateContentTxParams) (db.UpdateContentTxResult, error) {
+	ctx, span := startTx(ctx, "UpdatePageTx")
+	result, err := s.Store.UpdatePageTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) DeletePostsTx(ctx context.Context, args db.DeleteContentTxParams) (db.DeleteContentTxResult, error) {
+	ctx, span := startTx(ctx, "DeletePostsTx")
+	result, err := s.Store.DeletePostsTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) DeletePageTx(ctx context.Context, args db.DeleteContentTxParams) (db.DeleteContentTxResult, error) {
+	ctx, span := startTx(ctx, "DeletePageTx")
+	result, err := s.Store.DeletePageTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) MovePageTx(ctx context.Context, args db.MovePageTxParams) (db.MovePageTxResult, error) {
+	ctx, span := startTx(ctx, "MovePageTx")
+	result, err := s.Store.MovePageTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) ReorderPagesTx(ctx context.Context, args db.ReorderPagesTxParams) (db.ReorderPagesTxResult, error) {
+	ctx, span := startTx(ctx, "ReorderPagesTx")
+	result, err := s.Store.ReorderPagesTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) AddPageComponentTx(ctx context.Context, args db.AddPageComponentTxParams) (db.PageComponentsTxResult, error) {
+	ctx, span := startTx(ctx, "AddPageComponentTx")
+	result, err := s.Store.AddPageComponentTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) MovePageComponentTx(ctx context.Context, args db.MovePageComponentTxParams) (db.PageComponentsTxResult, error) {
+	ctx, span := startTx(ctx, "MovePageComponentTx")
+	result, err := s.Store.MovePageComponentTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) RemovePageComponentTx(ctx context.Context, args db.RemovePageComponentTxParams) (db.PageComponentsTxResult, error) {
+	ctx, span := startTx(ctx, "RemovePageComponentTx")
+	result, err := s.Store.RemovePageComponentTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) CreateSiteUserTx(ctx context.Context, args db.CreateSiteUserTxParams) (db.CreateSiteUserTxResult, error) {
+	ctx, span := startTx(ctx, "CreateSiteUserTx")
+	result, err := s.Store.CreateSiteUserTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) AddPageTranslationTx(ctx context.Context, args db.AddTranslationTxParams) (db.PageTranslationsTxResult, error) {
+	ctx, span := startTx(ctx, "AddPageTranslationTx")
+	result, err := s.Store.AddPageTranslationTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) AddPostTranslationTx(ctx context.Context, args db.AddTranslationTxParams) (db.PostTranslationsTxResult, error) {
+	ctx, span := startTx(ctx, "AddPostTranslationTx")
+	result, err := s.Store.AddPostTranslationTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) CreateMediaTx(ctx context.Context, args db.CreateMediaParams) (db.CreateMediaTxResult, error) {
+	ctx, span := startTx(ctx, "CreateMediaTx")
+	result, err := s.Store.CreateMediaTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStore) DeleteMediaTx(ctx context.Context, args db.DeleteMediaParams) (db.Media, error) {
+	ctx, span := startTx(ctx, "DeleteMediaTx")
+	result, err := s.Store.DeleteMediaTx(ctx, args)
+	endSpan(span, err)
+	return result, err
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/reflection/frog_blossom_db/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return recorder
+}
+
+func TestMiddlewareContinuesTraceparent(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/api/v1/users/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/7", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GET /api/v1/users/:id", spans[0].Name())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
+
+type fakeDBTX struct {
+	db.DBTX
+}
+
+func (fakeDBTX) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+// fakeStore runs one query per transaction method through the traced DBTX
+type fakeStore struct {
+	db.Store
+	conn db.DBTX
+}
+
+func (s fakeStore) DeletePostsTx(ctx context.Context, args db.DeleteContentTxParams) (db.DeleteContentTxResult, error) {
+	_, err := s.conn.ExecContext(ctx, "-- name: DeletePosts :exec\nDELETE FROM posts WHERE id = $1", *args.PostId)
+	return db.DeleteContentTxResult{DeletedPost: true}, err
+}
+
+func TestStoreSpansParentQuerySpans(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	store := Store(fakeStore{conn: DBTX(fakeDBTX{})})
+	postID := int64(3)
+	_, err := store.DeletePostsTx(context.Background(), db.DeleteContentTxParams{PostId: &postID})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	query, tx := spans[0], spans[1]
+	require.Equal(t, "DeletePosts", query.Name())
+	require.Equal(t, "Store.DeletePostsTx", tx.Name())
+	require.Equal(t, tx.SpanContext().SpanID(), query.Parent().SpanID())
+
+	var found bool
+	for _, attr := range query.Attributes() {
+		if attr.Key == queryNameKey {
+			found = true
+			require.Equal(t, "DeletePosts", attr.Value.AsString())
+		}
+	}
+	require.True(t, found, "query span must carry the sqlc query name")
+}
+
+func TestSetupRejectsUnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: "zipkin"})
+	require.Error(t, err)
+
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
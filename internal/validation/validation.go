@@ -0,0 +1,159 @@
+// Package validation configures request validation for Gin bindings and
+// turns binding failures into field-level apperr errors.
+//
+// Besides the go-playground built-ins, request DTOs can use:
+//
+//	enum=<pg type>  value must be a label of the Postgres enum type
+//	locale          value must be a BCP 47 language tag such as fr-CA
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/reflection/frog_blossom_db/internal/locale"
+)
+
+// Enums lists the labels of the Postgres enum types,
+// see db/migration/000002_add_ENUM_type.up.sql, 000006_add_page_options.up.sql,
+// 000010_add_sites.up.sql and 000016_add_post_status.up.sql
+var Enums = map[string][]string{
+	"access":      {"admin", "user"},
+	"option_type": {"string", "number", "boolean", "json"},
+	"post_status": {"draft", "pending", "private", "publish"},
+	"site_role":   {"owner", "editor", "viewer"},
+}
+
+var registerOnce sync.Once
+
+// Register installs the field naming and custom rules on Gin's validator.
+// It is safe to call more than once.
+func Register() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			panic("validation: gin binding validator is not go-playground/validator")
+		}
+		v.RegisterTagNameFunc(fieldName)
+		if err := v.RegisterValidation("enum", validateEnum); err != nil {
+			panic(err)
+		}
+		if err := v.RegisterValidation("locale", validateLocale); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// fieldName reports fields by the name clients send them with
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "uri", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func validateEnum(fl validator.FieldLevel) bool {
+	labels, ok := Enums[fl.Param()]
+	if !ok {
+		return false
+	}
+	value := fl.Field().String()
+	for _, label := range labels {
+		if value == label {
+			return true
+		}
+	}
+	return false
+}
+
+func validateLocale(fl validator.FieldLevel) bool {
+	_, err := locale.Parse(fl.Field().String())
+	return err == nil
+}
+
+// FromBinding converts an error from ctx.ShouldBind* into a validation
+// error that lists the offending fields
+func FromBinding(err error) *apperr.Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]apperr.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, apperr.FieldError{
+				Field:   fieldPath(fe),
+				Rule:    fe.Tag(),
+				Message: message(fe),
+			})
+		}
+		return apperr.InvalidFields(fields, err)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return apperr.InvalidFields([]apperr.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("must be a %s", typeErr.Type.Kind()),
+		}}, err)
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return apperr.Validation("malformed JSON body", err)
+	}
+
+	return apperr.Validation("invalid request", err)
+}
+
+// fieldPath drops the struct name from the namespace, e.g.
+// createUsersRequest.email becomes email
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "enum":
+		labels := append([]string(nil), Enums[fe.Param()]...)
+		sort.Strings(labels)
+		return "must be one of: " + strings.Join(labels, ", ")
+	case "locale":
+		return "must be a locale such as en or fr-CA"
+	}
+	return fmt.Sprintf("failed the %s rule", fe.Tag())
+}
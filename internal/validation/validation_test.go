@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/reflection/frog_blossom_db/internal/apperr"
+	"github.com/stretchr/testify/require"
+)
+
+type sampleRequest struct {
+	Status string `json:"status" binding:"required,enum=post_status"`
+	Image  string `json:"meta_og_image" binding:"omitempty,url,max=255"`
+	Locale string `json:"locale" binding:"omitempty,locale"`
+}
+
+func TestEnum(t *testing.T) {
+	Register()
+
+	require.NoError(t, binding.Validator.ValidateStruct(sampleRequest{Status: "publish", Locale: "fr-ca"}))
+
+	err := binding.Validator.ValidateStruct(sampleRequest{Status: "archived", Image: "not a url", Locale: "french"})
+	appErr := FromBinding(err)
+	require.ErrorIs(t, appErr, apperr.ErrValidation)
+	require.Equal(t, []apperr.FieldError{
+		{Field: "status", Rule: "enum", Message: "must be one of: draft, pending, private, publish"},
+		{Field: "meta_og_image", Rule: "url", Message: "must be a valid URL"},
+		{Field: "locale", Rule: "locale", Message: "must be a locale such as en or fr-CA"},
+	}, appErr.Fields)
+}
+
+func TestFromBindingSyntaxError(t *testing.T) {
+	var req sampleRequest
+	err := json.Unmarshal([]byte(`{"status":`), &req)
+
+	appErr := FromBinding(err)
+	require.ErrorIs(t, appErr, apperr.ErrValidation)
+	require.Equal(t, "malformed JSON body", appErr.Detail)
+	require.Empty(t, appErr.Fields)
+}
@@ -1,36 +0,0 @@
-package utils
-
-import (
-	"math/rand"
-	"strings"
-	"time"
-)
-
-const alphabet = "abcdefghijklmnopkrstuvwxyz"
-
-func init() {
-	rand.New(rand.NewSource(time.Now().UnixNano()))
-}
-
-func RandomInt(min, max int64) int64 {
-	return min + rand.Int63n(max-min+1)
-}
-
-func RandomID() int64 {
-	return RandomInt(0, 100)
-}
-
-func RandomString(n int) string {
-	var sb strings.Builder
-	k := len(alphabet)
-
-	for i := 0; i < n; i++ {
-		c := alphabet[rand.Intn(k)]
-		sb.WriteByte(c)
-	}
-	return sb.String()
-}
-
-func RandomUsername() string {
-	return RandomString(6)
-}